@@ -7,16 +7,24 @@ package client
 import (
 	"time"
 
+	"github.com/DirusK/utils/printer"
 	"github.com/spf13/cobra"
+
+	"authentication-chains/cmd/helpers"
+	"authentication-chains/internal/client"
 )
 
-var cfgName string
+var (
+	cfgName          string
+	passphraseSource *helpers.PassphraseSource
+)
 
 const (
 	defaultConfigPath  = "configs/clients/%s.yaml"
 	defaultConfigName  = "default"
 	defaultGRPCAddress = "localhost:50051"
 	defaultGRPCTimeout = 15 * time.Second
+	defaultValidity    = 7 * 24 * time.Hour
 )
 
 // ClientCmd represents the client command
@@ -27,6 +35,7 @@ var ClientCmd = &cobra.Command{
 
 func init() {
 	ClientCmd.PersistentFlags().StringVarP(&cfgName, "name", "n", defaultConfigName, "name for the config file")
+	passphraseSource = helpers.NewPassphraseSource(ClientCmd, "", helpers.EnvPassphrase)
 
 	// Here you will define your flags and configuration settings.
 
@@ -38,3 +47,14 @@ func init() {
 	// is called directly, e.g.:
 	// clientCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
+
+// newClient creates the client of the config, its private key is decrypted with the passphrase.
+func newClient(configPath string) (*client.Client, error) {
+	passphrase, err := passphraseSource.Passphrase()
+	if err != nil {
+		printer.Errort(helpers.TagCLI, err, "Failed to read passphrase")
+		return nil, err
+	}
+
+	return client.New(helpers.Ctx, configPath, passphrase)
+}
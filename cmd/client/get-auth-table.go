@@ -6,13 +6,13 @@ package client
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/jedib0t/go-pretty/v6/text"
 	"github.com/spf13/cobra"
 
 	"authentication-chains/cmd/helpers"
-	"authentication-chains/internal/client"
 )
 
 // getAuthTableCmd represents the getAuthTable command
@@ -22,7 +22,7 @@ var getAuthTableCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		configPath := fmt.Sprintf(defaultConfigPath, cfgName)
 
-		nodeClient, err := client.New(helpers.Ctx, configPath)
+		nodeClient, err := newClient(configPath)
 		if err != nil {
 			return
 		}
@@ -34,7 +34,7 @@ var getAuthTableCmd = &cobra.Command{
 
 		for level, authTable := range authTable.Table {
 			t := table.NewWriter()
-			t.AppendHeader(table.Row{"Device ID", "Cluster Head ID", "Block Hash", "Block Index"})
+			t.AppendHeader(table.Row{"Device ID", "Cluster Head ID", "Block Hash", "Block Index", "Not After", "Revoked", "Rotated From", "Rotated To"})
 			t.SetOutputMirror(cmd.OutOrStdout())
 			t.SetStyle(table.StyleColoredBright)
 			t.SetTitle("Authentication table level %d", level)
@@ -63,13 +63,42 @@ var getAuthTableCmd = &cobra.Command{
 					AlignHeader: text.AlignCenter,
 					Align:       text.AlignCenter,
 				},
+				{
+					Name:        "Not After",
+					AlignHeader: text.AlignCenter,
+					Align:       text.AlignCenter,
+				},
+				{
+					Name:        "Revoked",
+					AlignHeader: text.AlignCenter,
+					Align:       text.AlignCenter,
+				},
+				{
+					Name:        "Rotated From",
+					AlignHeader: text.AlignCenter,
+					Align:       text.AlignLeft,
+				},
+				{
+					Name:        "Rotated To",
+					AlignHeader: text.AlignCenter,
+					Align:       text.AlignLeft,
+				},
 			})
 			for _, entry := range authTable.Entries {
+				notAfter := "never"
+				if entry.NotAfter != 0 {
+					notAfter = time.Unix(entry.NotAfter, 0).Format(time.DateTime)
+				}
+
 				t.AppendRow(table.Row{
-					helpers.Truncate(string(entry.DeviceId), 30),
-					helpers.Truncate(string(entry.ClusterHeadId), 30),
+					helpers.FormatID(entry.DeviceId),
+					helpers.FormatID(entry.ClusterHeadId),
 					helpers.Truncate(fmt.Sprintf("%x", entry.BlockHash), 30),
 					helpers.Truncate(fmt.Sprintf("%d", entry.BlockIndex), 20),
+					notAfter,
+					entry.Revoked,
+					helpers.FormatID(entry.RotatedFrom),
+					helpers.FormatID(entry.RotatedTo),
 				})
 			}
 			t.Render()
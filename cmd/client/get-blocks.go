@@ -39,7 +39,7 @@ var getBlocksCmd = &cobra.Command{
 			return
 		}
 
-		nodeClient, err := client.New(helpers.Ctx, configPath)
+		nodeClient, err := newClient(configPath)
 		if err != nil {
 			return
 		}
@@ -50,7 +50,7 @@ var getBlocksCmd = &cobra.Command{
 		}
 
 		t := table.NewWriter()
-		t.AppendHeader(table.Row{"Index", "Hash", "Previous Hash", "Timestamp", "DAR"})
+		t.AppendHeader(table.Row{"Index", "Hash", "Previous Hash", "Timestamp", "DARs", "Revocations", "Renewals", "Rotations", "Approvals"})
 		t.SetOutputMirror(cmd.OutOrStdout())
 		t.SetStyle(table.StyleColoredDark)
 		t.SetTitle("Blocks from %d to %d", from, to)
@@ -78,17 +78,78 @@ var getBlocksCmd = &cobra.Command{
 				Align:       text.AlignCenter,
 			},
 			{
-				Name:        "DAR",
+				Name:        "DARs",
 				AlignHeader: text.AlignCenter,
 				Align:       text.AlignLeft,
 			},
+			{
+				Name:        "Revocations",
+				AlignHeader: text.AlignCenter,
+				Align:       text.AlignLeft,
+			},
+			{
+				Name:        "Renewals",
+				AlignHeader: text.AlignCenter,
+				Align:       text.AlignLeft,
+			},
+			{
+				Name:        "Rotations",
+				AlignHeader: text.AlignCenter,
+				Align:       text.AlignLeft,
+			},
+			{
+				Name:        "Approvals",
+				AlignHeader: text.AlignCenter,
+				Align:       text.AlignCenter,
+			},
 		})
 
 		for _, block := range blocks {
-			dar := client.DeviceAuthenticationRequest{
-				DeviceID:      helpers.Truncate(fmt.Sprintf("%s", block.Dar.DeviceId), 30),
-				ClusterHeadID: helpers.Truncate(fmt.Sprintf("%s", block.Dar.ClusterHeadId), 30),
-				Signature:     helpers.Truncate(fmt.Sprintf("%x", block.Dar.Signature), 30),
+			dars := make([]client.DeviceAuthenticationRequest, len(block.Dars))
+			for i, dar := range block.Dars {
+				dars[i] = client.DeviceAuthenticationRequest{
+					DeviceID:      helpers.FormatID(dar.DeviceId),
+					ClusterHeadID: helpers.FormatID(dar.ClusterHeadId),
+					Signature:     helpers.Truncate(fmt.Sprintf("%x", dar.Signature), 30),
+					Validity:      dar.Validity,
+				}
+			}
+
+			renewals := make([]client.DeviceRenewalRequest, len(block.Renewals))
+			for i, renewal := range block.Renewals {
+				renewals[i] = client.DeviceRenewalRequest{
+					DeviceID:      helpers.FormatID(renewal.DeviceId),
+					PrevBlockHash: helpers.Truncate(fmt.Sprintf("%x", renewal.PrevBlockHash), 30),
+					Validity:      renewal.Validity,
+					Signature:     helpers.Truncate(fmt.Sprintf("%x", renewal.Signature), 30),
+				}
+			}
+
+			revocations := make([]client.DeviceRevocationRequest, len(block.Revocations))
+			for i, revocation := range block.Revocations {
+				revocations[i] = client.DeviceRevocationRequest{
+					DeviceID:      helpers.FormatID(revocation.DeviceId),
+					ClusterHeadID: helpers.FormatID(revocation.ClusterHeadId),
+					SignerID:      helpers.FormatID(revocation.SignerId),
+					Signature:     helpers.Truncate(fmt.Sprintf("%x", revocation.Signature), 30),
+				}
+			}
+
+			rotations := make([]client.DeviceKeyRotationRequest, len(block.Rotations))
+			for i, rotation := range block.Rotations {
+				rotations[i] = client.DeviceKeyRotationRequest{
+					DeviceID:      helpers.FormatID(rotation.DeviceId),
+					NewDeviceID:   helpers.FormatID(rotation.NewDeviceId),
+					PrevBlockHash: helpers.Truncate(fmt.Sprintf("%x", rotation.PrevBlockHash), 30),
+					Validity:      rotation.Validity,
+				}
+			}
+
+			approvals := 0
+			for _, vote := range block.Votes {
+				if vote.IsValid {
+					approvals++
+				}
 			}
 
 			t.AppendRow(table.Row{
@@ -96,7 +157,11 @@ var getBlocksCmd = &cobra.Command{
 				helpers.Truncate(fmt.Sprintf("%x", block.Hash), 20),
 				helpers.Truncate(fmt.Sprintf("%x", block.PrevHash), 20),
 				helpers.Truncate(time.Unix(block.Timestamp, 0).Format(time.DateTime), 20),
-				helpers.Truncate(litter.Sdump(dar), 200),
+				helpers.Truncate(litter.Sdump(dars), 200),
+				helpers.Truncate(litter.Sdump(revocations), 200),
+				helpers.Truncate(litter.Sdump(renewals), 200),
+				helpers.Truncate(litter.Sdump(rotations), 200),
+				fmt.Sprintf("%d/%d", approvals, len(block.Votes)),
 			})
 		}
 
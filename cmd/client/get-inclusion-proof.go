@@ -0,0 +1,80 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package client
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"authentication-chains/cmd/helpers"
+)
+
+// getInclusionProofCmd represents the get-inclusion-proof command
+var getInclusionProofCmd = &cobra.Command{
+	Use:   "get-inclusion-proof",
+	Short: "Get and verify the merkle proof of device registration",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		configPath := fmt.Sprintf(defaultConfigPath, cfgName)
+
+		nodeClient, err := newClient(configPath)
+		if err != nil {
+			return
+		}
+
+		proof, err := nodeClient.GetInclusionProof()
+		if err != nil {
+			return
+		}
+
+		t := table.NewWriter()
+		t.AppendHeader(table.Row{"Step", "Sibling Hash", "Side"})
+		t.SetOutputMirror(cmd.OutOrStdout())
+		t.SetStyle(table.StyleColoredDark)
+		t.SetTitle("Block %d %s, merkle root %s",
+			proof.Header.Index,
+			helpers.Truncate(fmt.Sprintf("%x", proof.Header.Hash), 20),
+			helpers.Truncate(fmt.Sprintf("%x", proof.Header.MerkleRoot), 20),
+		)
+		t.SetCaption("Block timestamp: %s", time.Unix(proof.Header.Timestamp, 0).Format(time.DateTime))
+		t.Style().Title.Align = text.AlignCenter
+		t.SetColumnConfigs([]table.ColumnConfig{
+			{
+				Name:        "Step",
+				AlignHeader: text.AlignCenter,
+				Align:       text.AlignCenter,
+			},
+			{
+				Name:        "Sibling Hash",
+				AlignHeader: text.AlignCenter,
+				Align:       text.AlignLeft,
+			},
+			{
+				Name:        "Side",
+				AlignHeader: text.AlignCenter,
+				Align:       text.AlignCenter,
+			},
+		})
+
+		for i, step := range proof.Path {
+			side := "right"
+			if step.Left {
+				side = "left"
+			}
+
+			t.AppendRow(table.Row{i + 1, fmt.Sprintf("%x", step.Hash), side})
+		}
+
+		t.Render()
+	},
+}
+
+func init() {
+	ClientCmd.AddCommand(getInclusionProofCmd)
+}
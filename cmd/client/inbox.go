@@ -0,0 +1,104 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package client
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/DirusK/utils/printer"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"authentication-chains/cmd/helpers"
+)
+
+var (
+	// inboxLimit is the max number of fetched messages, zero means all of them.
+	inboxLimit uint32
+	// inboxKeep keeps the fetched messages in the mailbox instead of acknowledging them.
+	inboxKeep bool
+)
+
+// inboxCmd represents the inbox command
+var inboxCmd = &cobra.Command{
+	Use:   "inbox",
+	Short: "Read and acknowledge messages kept for the device in the mailbox of the node",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		configPath := fmt.Sprintf(defaultConfigPath, cfgName)
+
+		nodeClient, err := newClient(configPath)
+		if err != nil {
+			return
+		}
+
+		messages, err := nodeClient.FetchMessages(inboxLimit)
+		if err != nil {
+			return
+		}
+
+		if len(messages) == 0 {
+			printer.Infot(helpers.TagCLI, "Mailbox is empty")
+			return
+		}
+
+		t := table.NewWriter()
+		t.AppendHeader(table.Row{"Received At", "Sender ID", "Message"})
+		t.SetOutputMirror(cmd.OutOrStdout())
+		t.SetStyle(table.StyleColoredBright)
+		t.SetTitle("Inbox")
+		t.Style().Title.Align = text.AlignCenter
+		t.SetColumnConfigs([]table.ColumnConfig{
+			{
+				Name:        "Received At",
+				AlignHeader: text.AlignCenter,
+				Align:       text.AlignCenter,
+			},
+			{
+				Name:        "Sender ID",
+				AlignHeader: text.AlignCenter,
+				Align:       text.AlignLeft,
+			},
+			{
+				Name:        "Message",
+				AlignHeader: text.AlignCenter,
+				Align:       text.AlignLeft,
+				WidthMax:    60,
+			},
+		})
+
+		ids := make([][]byte, 0, len(messages))
+
+		for _, message := range messages {
+			t.AppendRow(table.Row{
+				message.ReceivedAt.Format(time.DateTime),
+				helpers.FormatID(message.Content.SenderId),
+				string(message.Content.Data),
+			})
+
+			ids = append(ids, message.ID)
+		}
+
+		t.Render()
+		fmt.Println()
+
+		if inboxKeep {
+			return
+		}
+
+		if _, err = nodeClient.AckMessages(ids); err != nil {
+			return
+		}
+	},
+}
+
+func init() {
+	ClientCmd.AddCommand(inboxCmd)
+
+	inboxCmd.Flags().Uint32VarP(&inboxLimit, "limit", "l", 0, "max number of fetched messages, zero means all of them")
+	inboxCmd.Flags().BoolVarP(&inboxKeep, "keep", "k", false, "keep the messages in the mailbox instead of acknowledging them")
+}
@@ -7,6 +7,7 @@ package client
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/DirusK/utils/printer"
 	"github.com/spf13/cobra"
@@ -17,28 +18,51 @@ import (
 	cfg "authentication-chains/internal/config"
 )
 
+var keyAlgorithm string
+
 // keygenCmd represents the keygen command
 var keygenCmd = &cobra.Command{
 	Use:   "keygen",
 	Short: "Generate a new key pair and saves it to the config file",
 	Args:  cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
-		cipher, err := cipher.New(nil)
+		algorithm, err := cipher.ParseAlgorithm(keyAlgorithm)
+		if err != nil {
+			printer.Errort(helpers.TagCLI, err, "Invalid key algorithm", "supported", strings.Join(cipher.Algorithms(), ", "))
+			return
+		}
+
+		c, err := cipher.Generate(algorithm)
 		if err != nil {
 			printer.Errort(helpers.TagCLI, err, "Failed to generate a new key pair")
 			return
 		}
 
+		passphrase, err := passphraseSource.Passphrase()
+		if err != nil {
+			printer.Errort(helpers.TagCLI, err, "Failed to read passphrase")
+			return
+		}
+
+		privateKey := c.SerializePrivateKey()
+		if len(passphrase) != 0 {
+			if privateKey, err = cipher.EncryptPrivateKey(privateKey, passphrase); err != nil {
+				printer.Errort(helpers.TagCLI, err, "Failed to encrypt private key")
+				return
+			}
+		}
+
 		config := cfg.Client{
 			Name:      cfgName,
 			BlockHash: "",
+			Validity:  defaultValidity,
 			GRPC: cfg.GRPC{
 				Address: defaultGRPCAddress,
 				Timeout: defaultGRPCTimeout,
 			},
 			Keys: cfg.Keys{
-				PublicKey:  cipher.ToStringPublicKey(),
-				PrivateKey: cipher.ToStringPrivateKey(),
+				PublicKey:  c.ToStringPublicKey(),
+				PrivateKey: string(privateKey),
 			},
 		}
 
@@ -66,4 +90,6 @@ func init() {
 
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
+	keygenCmd.Flags().StringVarP(&keyAlgorithm, "algorithm", "a", string(cipher.AlgorithmRSA),
+		"key algorithm: "+strings.Join(cipher.Algorithms(), ", "))
 }
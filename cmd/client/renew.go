@@ -0,0 +1,39 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package client
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// renewCmd represents the renew command
+var renewCmd = &cobra.Command{
+	Use:   "renew",
+	Short: "Renew authentication of the device before it expires",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		configPath := fmt.Sprintf(defaultConfigPath, cfgName)
+
+		nodeClient, err := newClient(configPath)
+		if err != nil {
+			return
+		}
+
+		blockHash, err := nodeClient.RenewDAR()
+		if err != nil {
+			return
+		}
+
+		if err = nodeClient.SaveBlockHash(configPath, blockHash); err != nil {
+			return
+		}
+	},
+}
+
+func init() {
+	ClientCmd.AddCommand(renewCmd)
+}
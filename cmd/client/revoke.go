@@ -0,0 +1,38 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package client
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// revokeCmd represents the revoke command
+var revokeCmd = &cobra.Command{
+	Use:   "revoke",
+	Short: "Revoke authentication of the device",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		configPath := fmt.Sprintf(defaultConfigPath, cfgName)
+
+		nodeClient, err := newClient(configPath)
+		if err != nil {
+			return
+		}
+
+		if _, err = nodeClient.RevokeDevice(); err != nil {
+			return
+		}
+
+		if err = nodeClient.SaveBlockHash(configPath, ""); err != nil {
+			return
+		}
+	},
+}
+
+func init() {
+	ClientCmd.AddCommand(revokeCmd)
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/DirusK/utils/printer"
+	"github.com/spf13/cobra"
+
+	"authentication-chains/cmd/helpers"
+	"authentication-chains/internal/cipher"
+)
+
+var rotateKeyAlgorithm string
+
+// rotateKeyCmd represents the rotate-key command
+var rotateKeyCmd = &cobra.Command{
+	Use:   "rotate-key",
+	Short: "Replace the key of the device keeping its authentication history",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		configPath := fmt.Sprintf(defaultConfigPath, cfgName)
+
+		algorithm, err := cipher.ParseAlgorithm(rotateKeyAlgorithm)
+		if err != nil {
+			printer.Errort(helpers.TagCLI, err, "Invalid key algorithm", "supported", strings.Join(cipher.Algorithms(), ", "))
+			return
+		}
+
+		passphrase, err := passphraseSource.Passphrase()
+		if err != nil {
+			printer.Errort(helpers.TagCLI, err, "Failed to read passphrase")
+			return
+		}
+
+		next, err := cipher.Generate(algorithm)
+		if err != nil {
+			printer.Errort(helpers.TagCLI, err, "Failed to generate a new key pair")
+			return
+		}
+
+		nodeClient, err := newClient(configPath)
+		if err != nil {
+			return
+		}
+
+		blockHash, err := nodeClient.RotateKey(next, passphrase)
+		if err != nil {
+			return
+		}
+
+		if err = nodeClient.SaveBlockHash(configPath, blockHash); err != nil {
+			return
+		}
+	},
+}
+
+func init() {
+	ClientCmd.AddCommand(rotateKeyCmd)
+
+	rotateKeyCmd.Flags().StringVarP(&rotateKeyAlgorithm, "algorithm", "a", string(cipher.AlgorithmRSA),
+		"key algorithm: "+strings.Join(cipher.Algorithms(), ", "))
+}
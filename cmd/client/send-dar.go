@@ -8,9 +8,6 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
-
-	"authentication-chains/cmd/helpers"
-	"authentication-chains/internal/client"
 )
 
 // sendDar represents the send-dar command
@@ -21,7 +18,7 @@ var sendDar = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		configPath := fmt.Sprintf(defaultConfigPath, cfgName)
 
-		client, err := client.New(helpers.Ctx, configPath)
+		client, err := newClient(configPath)
 		if err != nil {
 			return
 		}
@@ -5,30 +5,39 @@
 package client
 
 import (
+	"encoding/hex"
 	"fmt"
 
 	"github.com/DirusK/utils/printer"
 	"github.com/spf13/cobra"
 
 	"authentication-chains/cmd/helpers"
-	"authentication-chains/internal/client"
 )
 
+// sendMessageReceiver is the hex device id of the message receiver.
+var sendMessageReceiver string
+
 // sendMessageCmd represents the sendMessage command
 var sendMessageCmd = &cobra.Command{
 	Use:   "send-message [message]",
-	Short: "Send message to the node",
+	Short: "Send message to the node or to another device through the nodes",
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
+		receiverID, err := hex.DecodeString(sendMessageReceiver)
+		if err != nil {
+			printer.Errort(helpers.TagCLI, err, "Invalid receiver device id")
+			return
+		}
+
 		configPath := fmt.Sprintf(defaultConfigPath, cfgName)
 
-		nodeClient, err := client.New(helpers.Ctx, configPath)
+		nodeClient, err := newClient(configPath)
 		if err != nil {
 			return
 		}
 
-		response, err := nodeClient.SendMessage([]byte(args[0]))
-		if err != nil {
+		response, err := nodeClient.SendMessage(receiverID, []byte(args[0]))
+		if err != nil || response == nil {
 			return
 		}
 
@@ -39,13 +48,6 @@ var sendMessageCmd = &cobra.Command{
 func init() {
 	ClientCmd.AddCommand(sendMessageCmd)
 
-	// Here you will define your flags and configuration settings.
-
-	// Cobra supports Persistent Flags which will work for this command
-	// and all subcommands, e.g.:
-	// sendMessageCmd.PersistentFlags().String("foo", "", "A help for foo")
-
-	// Cobra supports local flags which will only run when this command
-	// is called directly, e.g.:
-	// sendMessageCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	sendMessageCmd.Flags().StringVarP(&sendMessageReceiver, "to", "t", "",
+		"hex device id of the receiver, the message is sent to the node if it's empty")
 }
@@ -0,0 +1,182 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package client
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/DirusK/utils/printer"
+	"github.com/jedib0t/go-pretty/v6/list"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"authentication-chains/cmd/helpers"
+	"authentication-chains/internal/types"
+)
+
+// Output formats of the topology.
+const (
+	topologyFormatTree = "tree"
+	topologyFormatJSON = "json"
+	topologyFormatDOT  = "dot"
+)
+
+// topologyFormats are the supported output formats of the topology.
+var topologyFormats = []string{topologyFormatTree, topologyFormatJSON, topologyFormatDOT}
+
+var (
+	// topologyFormat is the output format of the topology.
+	topologyFormat string
+	// topologyDepth is the max number of levels below the top cluster, zero means the default of the node.
+	topologyDepth uint32
+)
+
+// topologyCmd represents the topology command
+var topologyCmd = &cobra.Command{
+	Use:   "topology",
+	Short: "Show the tree of clusters and their heads the node belongs to",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		switch topologyFormat {
+		case topologyFormatTree, topologyFormatJSON, topologyFormatDOT:
+		default:
+			printer.Errort(helpers.TagCLI, errors.New("unknown format"), "Invalid topology format", "supported", strings.Join(topologyFormats, ", "))
+			return
+		}
+
+		configPath := fmt.Sprintf(defaultConfigPath, cfgName)
+
+		nodeClient, err := newClient(configPath)
+		if err != nil {
+			return
+		}
+
+		nodes, err := nodeClient.GetTopology(topologyDepth)
+		if err != nil {
+			return
+		}
+
+		switch topologyFormat {
+		case topologyFormatTree:
+			renderTopologyTree(cmd.OutOrStdout(), nodes)
+		case topologyFormatJSON:
+			err = renderTopologyJSON(cmd.OutOrStdout(), nodes)
+		case topologyFormatDOT:
+			renderTopologyDOT(cmd.OutOrStdout(), nodes)
+		}
+
+		if err != nil {
+			printer.Errort(helpers.TagCLI, err, "Failed to render topology", "format", topologyFormat)
+		}
+	},
+}
+
+func init() {
+	ClientCmd.AddCommand(topologyCmd)
+
+	topologyCmd.Flags().StringVarP(&topologyFormat, "format", "f", topologyFormatTree,
+		"output format: "+strings.Join(topologyFormats, ", "))
+	topologyCmd.Flags().Uint32VarP(&topologyDepth, "depth", "d", 0, "max number of levels below the top cluster, zero means the default of the node")
+}
+
+// renderTopologyTree writes the topology as the tree of nodes with their children.
+func renderTopologyTree(w io.Writer, nodes []*types.TopologyNode) {
+	l := list.NewWriter()
+	l.SetOutputMirror(w)
+	l.SetStyle(list.StyleConnectedRounded)
+
+	var appendNodes func(nodes []*types.TopologyNode)
+	appendNodes = func(nodes []*types.TopologyNode) {
+		for _, node := range nodes {
+			l.AppendItem(topologyLabel(node))
+
+			if len(node.Children) != 0 {
+				l.Indent()
+				appendNodes(node.Children)
+				l.UnIndent()
+			}
+		}
+	}
+
+	appendNodes(nodes)
+
+	l.Render()
+	fmt.Fprintln(w)
+}
+
+// renderTopologyJSON writes the topology as the JSON object with the top nodes.
+func renderTopologyJSON(w io.Writer, nodes []*types.TopologyNode) error {
+	data, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(&types.TopologyResponse{Nodes: nodes})
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(w, string(data))
+
+	return err
+}
+
+// renderTopologyDOT writes the topology as the Graphviz graph, the edges go from cluster heads to their children
+// and the top cluster is grouped.
+func renderTopologyDOT(w io.Writer, nodes []*types.TopologyNode) {
+	fmt.Fprintln(w, "digraph topology {")
+	fmt.Fprintln(w, "  node [shape=box];")
+
+	var writeNodes func(nodes []*types.TopologyNode)
+	writeNodes = func(nodes []*types.TopologyNode) {
+		for _, node := range nodes {
+			fmt.Fprintf(w, "  %q [label=%q];\n", topologyNodeID(node), topologyLabel(node))
+
+			for _, child := range node.Children {
+				fmt.Fprintf(w, "  %q -> %q;\n", topologyNodeID(node), topologyNodeID(child))
+			}
+
+			writeNodes(node.Children)
+		}
+	}
+
+	writeNodes(nodes)
+
+	fmt.Fprintln(w, "  subgraph cluster_top {")
+	fmt.Fprintln(w, "    label=\"top cluster\";")
+
+	for _, node := range nodes {
+		fmt.Fprintf(w, "    %q;\n", topologyNodeID(node))
+	}
+
+	fmt.Fprintln(w, "  }")
+	fmt.Fprintln(w, "}")
+}
+
+// topologyNodeID returns the id of the node in the graph.
+func topologyNodeID(node *types.TopologyNode) string {
+	if node.Peer == nil {
+		return ""
+	}
+
+	return hex.EncodeToString(node.Peer.DeviceId)
+}
+
+// topologyLabel describes the node with its level, id and address, the missing subtrees are marked.
+func topologyLabel(node *types.TopologyNode) string {
+	if node.Peer == nil {
+		return "unknown node"
+	}
+
+	label := fmt.Sprintf("%s level %d %s %s", node.Peer.Name, node.Peer.Level, helpers.FormatID(node.Peer.DeviceId), node.Peer.GrpcAddress)
+
+	switch {
+	case node.Error != "":
+		label += " (unavailable: " + node.Error + ")"
+	case node.Truncated:
+		label += " (children are beyond the depth)"
+	}
+
+	return label
+}
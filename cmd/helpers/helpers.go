@@ -6,9 +6,12 @@ package helpers
 
 import (
 	"context"
+	"encoding/hex"
 	"os"
 	"os/signal"
 	"syscall"
+
+	"authentication-chains/internal/cipher"
 )
 
 const TagCLI = "CLI"
@@ -22,6 +25,11 @@ func Truncate(str string, num int) string {
 	return str[:num] + "..."
 }
 
+// FormatID formats the device id as the short hex fingerprint, PEM ids of the previous versions are converted.
+func FormatID(id []byte) string {
+	return Truncate(hex.EncodeToString(cipher.DeviceID(id)), 16)
+}
+
 // registerGracefulHandle registers a graceful shutdown handler for the application.
 func registerGracefulHandle() context.Context {
 	gracefulCtx, cancel := context.WithCancel(context.Background())
@@ -0,0 +1,57 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package helpers
+
+import (
+	"bytes"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// EnvPassphrase is the environment variable with the passphrase of the private key.
+	EnvPassphrase = "AUTH_CHAINS_PASSPHRASE"
+	// EnvNewPassphrase is the environment variable with the new passphrase of the private key.
+	EnvNewPassphrase = "AUTH_CHAINS_NEW_PASSPHRASE"
+)
+
+// PassphraseSource is the flag, the key file and the environment variable the passphrase is taken from.
+type PassphraseSource struct {
+	env        string
+	passphrase string
+	file       string
+}
+
+// NewPassphraseSource creates a passphrase source with the persistent flags of the command
+// named with the prefix and the environment variable.
+func NewPassphraseSource(cmd *cobra.Command, prefix, env string) *PassphraseSource {
+	source := &PassphraseSource{env: env}
+	flags := cmd.PersistentFlags()
+
+	flags.StringVar(&source.passphrase, prefix+"passphrase", "",
+		"passphrase of the private key, the "+env+" environment variable is used if it's not set")
+	flags.StringVar(&source.file, prefix+"passphrase-file", "", "path to the file with the passphrase of the private key")
+
+	return source
+}
+
+// Passphrase returns the passphrase from the flag, the key file or the environment variable in that order.
+// It's empty if the private key is not encrypted.
+func (s *PassphraseSource) Passphrase() ([]byte, error) {
+	switch {
+	case s.passphrase != "":
+		return []byte(s.passphrase), nil
+	case s.file != "":
+		data, err := os.ReadFile(s.file)
+		if err != nil {
+			return nil, err
+		}
+
+		return bytes.TrimRight(data, "\r\n"), nil
+	default:
+		return []byte(os.Getenv(s.env)), nil
+	}
+}
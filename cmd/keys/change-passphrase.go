@@ -0,0 +1,143 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package keys
+
+import (
+	"fmt"
+	"os"
+
+	utils "github.com/DirusK/utils/config"
+	"github.com/DirusK/utils/printer"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"authentication-chains/cmd/helpers"
+	"authentication-chains/internal/cipher"
+	"authentication-chains/internal/config"
+	"authentication-chains/internal/storage"
+)
+
+const clientConfigPath = "configs/clients/%s.yaml"
+
+var (
+	// cfgPath is a path to configuration file of the node.
+	cfgPath string
+	// cfgName is a name of the client config file.
+	cfgName string
+	// passphraseSource is a source of the current passphrase of the private key.
+	passphraseSource *helpers.PassphraseSource
+	// newPassphraseSource is a source of the new passphrase of the private key.
+	newPassphraseSource *helpers.PassphraseSource
+)
+
+// changePassphraseCmd represents the change-passphrase command
+var changePassphraseCmd = &cobra.Command{
+	Use:   "change-passphrase",
+	Short: "Re-encrypt the private key of the node storage or the client config with a new passphrase",
+	Long: "Re-encrypt the private key of the node storage or the client config with a new passphrase.\n" +
+		"The key is encrypted for the first time if it's stored in plain and decrypted if the new passphrase is empty.\n" +
+		"The node must be stopped, so its storage can be opened.",
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		passphrase, err := passphraseSource.Passphrase()
+		if err != nil {
+			printer.Errort(helpers.TagCLI, err, "Failed to read passphrase")
+			return
+		}
+
+		newPassphrase, err := newPassphraseSource.Passphrase()
+		if err != nil {
+			printer.Errort(helpers.TagCLI, err, "Failed to read new passphrase")
+			return
+		}
+
+		if cfgPath != "" {
+			err = changeNodePassphrase(passphrase, newPassphrase)
+		} else {
+			err = changeClientPassphrase(passphrase, newPassphrase)
+		}
+
+		if err != nil {
+			return
+		}
+
+		if len(newPassphrase) == 0 {
+			printer.Infot(helpers.TagCLI, "private key is stored in plain")
+			return
+		}
+
+		printer.Infot(helpers.TagCLI, "private key is encrypted with the new passphrase")
+	},
+}
+
+func init() {
+	KeysCmd.AddCommand(changePassphraseCmd)
+
+	changePassphraseCmd.Flags().StringVarP(&cfgPath, "config", "c", "", "Path to configuration file of the node")
+	changePassphraseCmd.Flags().StringVarP(&cfgName, "name", "n", "", "name for the client config file")
+	changePassphraseCmd.MarkFlagsMutuallyExclusive("config", "name")
+	changePassphraseCmd.MarkFlagsOneRequired("config", "name")
+
+	passphraseSource = helpers.NewPassphraseSource(changePassphraseCmd, "", helpers.EnvPassphrase)
+	newPassphraseSource = helpers.NewPassphraseSource(changePassphraseCmd, "new-", helpers.EnvNewPassphrase)
+}
+
+// changeNodePassphrase re-encrypts the private key in the storage of the node.
+func changeNodePassphrase(passphrase, newPassphrase []byte) error {
+	var cfg config.Config
+
+	if err := utils.LoadFromFile(cfgPath, &cfg); err != nil {
+		printer.Errort(helpers.TagCLI, err, "Failed to load config")
+		return err
+	}
+
+	db, err := storage.New(cfg.Storage)
+	if err != nil {
+		printer.Errort(helpers.TagCLI, err, "Failed to open storage", "directory", cfg.Storage.Directory)
+		return err
+	}
+	defer db.Close()
+
+	if err = cipher.ChangeStoredPassphrase(db, passphrase, newPassphrase); err != nil {
+		printer.Errort(helpers.TagCLI, err, "Failed to change passphrase of the node key")
+		return err
+	}
+
+	return nil
+}
+
+// changeClientPassphrase re-encrypts the private key in the client config.
+func changeClientPassphrase(passphrase, newPassphrase []byte) error {
+	var (
+		cfg  config.Client
+		path = fmt.Sprintf(clientConfigPath, cfgName)
+	)
+
+	if err := utils.LoadFromFile(path, &cfg); err != nil {
+		printer.Errort(helpers.TagCLI, err, "Failed to load config")
+		return err
+	}
+
+	privateKey, err := cipher.ChangePassphrase([]byte(cfg.Keys.PrivateKey), passphrase, newPassphrase)
+	if err != nil {
+		printer.Errort(helpers.TagCLI, err, "Failed to change passphrase of the client key")
+		return err
+	}
+
+	cfg.Keys.PrivateKey = string(privateKey)
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		printer.Errort(helpers.TagCLI, err, "Failed to marshal config")
+		return err
+	}
+
+	if err = os.WriteFile(path, data, 0644); err != nil {
+		printer.Errort(helpers.TagCLI, err, "Failed to write config file")
+		return err
+	}
+
+	return nil
+}
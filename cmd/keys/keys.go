@@ -0,0 +1,15 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package keys
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// KeysCmd represents the keys command
+var KeysCmd = &cobra.Command{
+	Use:   "keys",
+	Short: "Manage private keys of nodes and clients",
+}
@@ -0,0 +1,41 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package node
+
+import (
+	"github.com/DirusK/utils/printer"
+	"github.com/spf13/cobra"
+
+	"authentication-chains/cmd/helpers"
+	"authentication-chains/internal/app"
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export [archive]",
+	Short: "Export the chain, authentication tables and peers of the node to the archive",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		passphrase, err := passphraseSource.Passphrase()
+		if err != nil {
+			printer.Errort(helpers.TagCLI, err, "Failed to read passphrase")
+			return
+		}
+
+		application := app.NewOffline(helpers.Ctx, cfgPath, passphrase)
+		defer application.Close()
+
+		if err = application.Export(args[0]); err != nil {
+			printer.Errort(helpers.TagCLI, err, "Failed to export node", "archive", args[0])
+			return
+		}
+
+		printer.Infot(helpers.TagCLI, "node exported", "archive", args[0])
+	},
+}
+
+func init() {
+	NodeCmd.AddCommand(exportCmd)
+}
@@ -0,0 +1,41 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package node
+
+import (
+	"github.com/DirusK/utils/printer"
+	"github.com/spf13/cobra"
+
+	"authentication-chains/cmd/helpers"
+	"authentication-chains/internal/app"
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import [archive]",
+	Short: "Restore the node with an empty chain from the archive",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		passphrase, err := passphraseSource.Passphrase()
+		if err != nil {
+			printer.Errort(helpers.TagCLI, err, "Failed to read passphrase")
+			return
+		}
+
+		application := app.NewOffline(helpers.Ctx, cfgPath, passphrase)
+		defer application.Close()
+
+		if err = application.Import(args[0]); err != nil {
+			printer.Errort(helpers.TagCLI, err, "Failed to import node", "archive", args[0])
+			return
+		}
+
+		printer.Infot(helpers.TagCLI, "node imported", "archive", args[0])
+	},
+}
+
+func init() {
+	NodeCmd.AddCommand(importCmd)
+}
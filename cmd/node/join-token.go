@@ -0,0 +1,50 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package node
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/DirusK/utils/printer"
+	"github.com/spf13/cobra"
+
+	"authentication-chains/cmd/helpers"
+	"authentication-chains/internal/app"
+)
+
+var joinTokenTTL time.Duration
+
+// joinTokenCmd represents the join-token command
+var joinTokenCmd = &cobra.Command{
+	Use:   "join-token",
+	Short: "Create the token which approves the registration of a new node in the cluster of the stopped node",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		passphrase, err := passphraseSource.Passphrase()
+		if err != nil {
+			printer.Errort(helpers.TagCLI, err, "Failed to read passphrase")
+			return
+		}
+
+		application := app.NewOffline(helpers.Ctx, cfgPath, passphrase)
+		defer application.Close()
+
+		token, err := application.CreateJoinToken(joinTokenTTL)
+		if err != nil {
+			printer.Errort(helpers.TagCLI, err, "Failed to create join token")
+			return
+		}
+
+		printer.Infot(helpers.TagCLI, "join token is created, set it to node.registration.join-token of the new node", "ttl", joinTokenTTL)
+		fmt.Fprintln(cmd.OutOrStdout(), token)
+	},
+}
+
+func init() {
+	NodeCmd.AddCommand(joinTokenCmd)
+
+	joinTokenCmd.Flags().DurationVarP(&joinTokenTTL, "ttl", "t", 24*time.Hour, "how long the token is valid, zero means it doesn't expire")
+}
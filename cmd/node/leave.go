@@ -0,0 +1,46 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package node
+
+import (
+	"github.com/DirusK/utils/printer"
+	"github.com/spf13/cobra"
+
+	"authentication-chains/cmd/helpers"
+	"authentication-chains/internal/app"
+)
+
+// leaveRevoke revokes the authentication of the node before it leaves.
+var leaveRevoke bool
+
+// leaveCmd represents the leave command
+var leaveCmd = &cobra.Command{
+	Use:   "leave",
+	Short: "Deregister the stopped node from its peers and hand off its children to a cluster node",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		passphrase, err := passphraseSource.Passphrase()
+		if err != nil {
+			printer.Errort(helpers.TagCLI, err, "Failed to read passphrase")
+			return
+		}
+
+		application := app.NewOffline(helpers.Ctx, cfgPath, passphrase)
+		defer application.Close()
+
+		if err = application.Leave(leaveRevoke); err != nil {
+			printer.Errort(helpers.TagCLI, err, "Failed to leave the network")
+			return
+		}
+
+		printer.Infot(helpers.TagCLI, "node left the network", "revoked", leaveRevoke)
+	},
+}
+
+func init() {
+	NodeCmd.AddCommand(leaveCmd)
+
+	leaveCmd.Flags().BoolVarP(&leaveRevoke, "revoke", "r", false, "revoke the authentication of the node before it leaves")
+}
@@ -7,11 +7,16 @@ package node
 import (
 	"github.com/spf13/cobra"
 
+	"authentication-chains/cmd/helpers"
 	"authentication-chains/internal/config"
 )
 
-// cfgPath is a path to configuration file.
-var cfgPath string
+var (
+	// cfgPath is a path to configuration file.
+	cfgPath string
+	// passphraseSource is a source of the passphrase of the node private key.
+	passphraseSource *helpers.PassphraseSource
+)
 
 // NodeCmd represents the node command
 var NodeCmd = &cobra.Command{
@@ -21,6 +26,7 @@ var NodeCmd = &cobra.Command{
 
 func init() {
 	NodeCmd.PersistentFlags().StringVarP(&cfgPath, "config", "c", config.DefaultPath, "Path to configuration file")
+	passphraseSource = helpers.NewPassphraseSource(NodeCmd, "", helpers.EnvPassphrase)
 
 	// Here you will define your flags and configuration settings.
 
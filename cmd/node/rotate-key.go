@@ -0,0 +1,55 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package node
+
+import (
+	"strings"
+
+	"github.com/DirusK/utils/printer"
+	"github.com/spf13/cobra"
+
+	"authentication-chains/cmd/helpers"
+	"authentication-chains/internal/app"
+	"authentication-chains/internal/cipher"
+)
+
+var keyAlgorithm string
+
+// rotateKeyCmd represents the rotate-key command
+var rotateKeyCmd = &cobra.Command{
+	Use:   "rotate-key",
+	Short: "Replace the key of the stopped node and notify its peers",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		algorithm, err := cipher.ParseAlgorithm(keyAlgorithm)
+		if err != nil {
+			printer.Errort(helpers.TagCLI, err, "Invalid key algorithm", "supported", strings.Join(cipher.Algorithms(), ", "))
+			return
+		}
+
+		passphrase, err := passphraseSource.Passphrase()
+		if err != nil {
+			printer.Errort(helpers.TagCLI, err, "Failed to read passphrase")
+			return
+		}
+
+		application := app.NewOffline(helpers.Ctx, cfgPath, passphrase)
+		defer application.Close()
+
+		if err = application.RotateKey(algorithm); err != nil {
+			printer.Errort(helpers.TagCLI, err, "Failed to rotate node key")
+			return
+		}
+
+		printer.Infot(helpers.TagCLI, "node key is rotated")
+	},
+}
+
+func init() {
+	NodeCmd.AddCommand(rotateKeyCmd)
+
+	rotateKeyCmd.Flags().StringVarP(&keyAlgorithm, "algorithm", "a", string(cipher.AlgorithmRSA),
+		"key algorithm: "+strings.Join(cipher.Algorithms(), ", "))
+}
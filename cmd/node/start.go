@@ -17,8 +17,14 @@ var startCmd = &cobra.Command{
 	Use:   "start",
 	Short: "Start a blockchain node",
 	Run: func(cmd *cobra.Command, args []string) {
+		passphrase, err := passphraseSource.Passphrase()
+		if err != nil {
+			printer.Errort(helpers.TagCLI, err, "Failed to read passphrase")
+			return
+		}
+
 		printer.Infot(helpers.TagCLI, "starting node")
-		app.New(helpers.Ctx, cfgPath).Run()
+		app.New(helpers.Ctx, cfgPath, passphrase).Run()
 		printer.Infot(helpers.TagCLI, "node stopped")
 	},
 }
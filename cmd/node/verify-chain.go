@@ -0,0 +1,102 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package node
+
+import (
+	"fmt"
+
+	utils "github.com/DirusK/utils/config"
+	"github.com/DirusK/utils/printer"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"authentication-chains/cmd/helpers"
+	"authentication-chains/internal/blockchain"
+	"authentication-chains/internal/config"
+	"authentication-chains/internal/storage"
+)
+
+// verifyChainCmd represents the verify-chain command
+var verifyChainCmd = &cobra.Command{
+	Use:   "verify-chain",
+	Short: "Verify integrity of the local blockchain and authentication table",
+	Run: func(cmd *cobra.Command, args []string) {
+		var cfg config.Config
+
+		if err := utils.LoadFromFile(cfgPath, &cfg); err != nil {
+			printer.Errort(helpers.TagCLI, err, "Failed to load config")
+			return
+		}
+
+		db, err := storage.New(cfg.Storage)
+		if err != nil {
+			printer.Errort(helpers.TagCLI, err, "Failed to open storage", "directory", cfg.Storage.Directory)
+			return
+		}
+		defer db.Close()
+
+		chain, err := blockchain.New(db)
+		if err != nil {
+			printer.Errort(helpers.TagCLI, err, "Failed to init blockchain")
+			return
+		}
+
+		report, err := chain.Verify(cfg.Node.Level)
+		if err != nil {
+			printer.Errort(helpers.TagCLI, err, "Failed to verify blockchain")
+			return
+		}
+
+		if report.BrokenLink != nil {
+			t := table.NewWriter()
+			t.AppendHeader(table.Row{"Index", "Hash", "Reason"})
+			t.SetOutputMirror(cmd.OutOrStdout())
+			t.SetStyle(table.StyleColoredDark)
+			t.SetTitle("First broken link")
+			t.Style().Title.Align = text.AlignCenter
+			t.AppendRow(table.Row{
+				report.BrokenLink.Index,
+				helpers.Truncate(fmt.Sprintf("%x", report.BrokenLink.Hash), 30),
+				report.BrokenLink.Reason,
+			})
+			t.Render()
+			fmt.Println()
+		}
+
+		if len(report.MismatchedEntries) != 0 {
+			t := table.NewWriter()
+			t.AppendHeader(table.Row{"Device ID", "Block Index", "Reason"})
+			t.SetOutputMirror(cmd.OutOrStdout())
+			t.SetStyle(table.StyleColoredBright)
+			t.SetTitle("Mismatched authentication entries level %d", cfg.Node.Level)
+			t.SortBy([]table.SortBy{{Name: "Block Index", Mode: table.Asc}})
+			t.Style().Title.Align = text.AlignCenter
+
+			for _, entry := range report.MismatchedEntries {
+				t.AppendRow(table.Row{
+					helpers.FormatID(entry.DeviceID),
+					entry.BlockIndex,
+					entry.Reason,
+				})
+			}
+
+			t.Render()
+			fmt.Println()
+		}
+
+		if !report.IsValid() {
+			printer.Errort(helpers.TagCLI, blockchain.ErrChainIntegrity, "Blockchain verification failed",
+				"blocks", report.Blocks, "entries", report.Entries)
+			return
+		}
+
+		printer.Infot(helpers.TagCLI, "Blockchain is intact", "blocks", report.Blocks, "entries", report.Entries)
+	},
+}
+
+func init() {
+	NodeCmd.AddCommand(verifyChainCmd)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"authentication-chains/cmd/client"
+	"authentication-chains/cmd/keys"
 	"authentication-chains/cmd/node"
 )
 
@@ -27,6 +28,7 @@ var rootCmd = &cobra.Command{
 func Execute() {
 	rootCmd.AddCommand(client.ClientCmd)
 	rootCmd.AddCommand(node.NodeCmd)
+	rootCmd.AddCommand(keys.KeysCmd)
 
 	err := rootCmd.Execute()
 	if err != nil {
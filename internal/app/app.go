@@ -11,11 +11,11 @@ import (
 	"github.com/DirusK/utils/validator"
 	"github.com/alitto/pond"
 	"github.com/go-co-op/gocron"
-	"github.com/nutsdb/nutsdb"
 	"google.golang.org/grpc"
 
 	"authentication-chains/internal/config"
 	"authentication-chains/internal/node"
+	"authentication-chains/internal/storage"
 )
 
 type (
@@ -31,19 +31,21 @@ type (
 		ctx        context.Context
 		validator  validator.Validator
 		cfg        *config.Config
-		db         *nutsdb.DB
+		db         storage.Storage
 		grpcServer *grpc.Server
 		logger     log.Logger
 		workerPool *pond.WorkerPool
 		scheduler  *gocron.Scheduler
 		node       *node.Node
+		passphrase []byte
 	}
 )
 
-// New creates a new application instance.
-func New(ctx context.Context, configPath string) *App {
+// New creates a new application instance, the passphrase encrypts the node private key in the storage.
+func New(ctx context.Context, configPath string, passphrase []byte) *App {
 	app := new(App)
 	app.ctx = ctx
+	app.passphrase = passphrase
 
 	app.initValidator()
 	app.initConfig(configPath)
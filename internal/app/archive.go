@@ -0,0 +1,86 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package app
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"authentication-chains/internal/cipher"
+)
+
+// NewOffline creates an application instance for the node storage maintenance.
+// The node is neither initialized in the network nor served.
+func NewOffline(ctx context.Context, configPath string, passphrase []byte) *App {
+	app := new(App)
+	app.ctx = ctx
+	app.passphrase = passphrase
+
+	app.initValidator()
+	app.initConfig(configPath)
+
+	app.meta = Meta{
+		Name:  app.cfg.Node.Name,
+		Level: app.cfg.Node.Level,
+	}
+
+	app.initLogger()
+	app.initStorage()
+	app.initWorkerPool(ctx)
+	app.createNode(ctx)
+
+	return app
+}
+
+// Export writes the node archive to the file.
+func (a *App) Export(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err = a.node.Export(a.ctx, file); err != nil {
+		return err
+	}
+
+	return file.Sync()
+}
+
+// Import restores the node from the archive file.
+func (a *App) Import(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return a.node.Import(a.ctx, file)
+}
+
+// RotateKey replaces the key of the node with a new one of the algorithm.
+func (a *App) RotateKey(algorithm cipher.Algorithm) error {
+	return a.node.RotateNodeKey(a.ctx, algorithm)
+}
+
+// CreateJoinToken creates the join token which approves the registration of a new node during the ttl.
+func (a *App) CreateJoinToken(ttl time.Duration) (string, error) {
+	return a.node.CreateJoinToken(a.ctx, ttl)
+}
+
+// Leave deregisters the node from the network and revokes its authentication if requested.
+func (a *App) Leave(revoke bool) error {
+	return a.node.Leave(a.ctx, revoke)
+}
+
+// Close stops the worker pool and closes the storage.
+func (a *App) Close() {
+	a.workerPool.StopAndWait()
+
+	if err := a.db.Close(); err != nil {
+		a.logger.Errorf("close storage: %s", err)
+	}
+}
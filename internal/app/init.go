@@ -13,11 +13,12 @@ import (
 	"github.com/DirusK/utils/validator"
 	"github.com/alitto/pond"
 	"github.com/go-co-op/gocron"
-	"github.com/nutsdb/nutsdb"
 	"google.golang.org/grpc"
 
 	"authentication-chains/internal/config"
 	"authentication-chains/internal/node"
+	"authentication-chains/internal/storage"
+	"authentication-chains/internal/transport"
 )
 
 func (a *App) initValidator() {
@@ -42,24 +43,25 @@ func (a *App) initLogger() {
 func (a *App) initStorage() {
 	var err error
 
-	a.db, err = nutsdb.Open(
-		nutsdb.DefaultOptions,
-		nutsdb.WithDir(a.cfg.Storage.Directory),
-	)
+	a.db, err = storage.New(a.cfg.Storage)
 	if err != nil {
 		a.logger.Fatal(err)
 	}
 }
 
 func (a *App) initNode(ctx context.Context) {
-	var err error
+	a.createNode(ctx)
 
-	a.node, err = node.New(ctx, a.cfg.Node, a.db, a.workerPool, a.logger)
-	if err != nil {
+	if err := a.node.Init(ctx); err != nil {
 		a.logger.Fatal(err)
 	}
+}
+
+func (a *App) createNode(ctx context.Context) {
+	var err error
 
-	if err = a.node.Init(ctx); err != nil {
+	a.node, err = node.New(ctx, a.cfg.Node, a.db, a.passphrase, a.workerPool, a.logger)
+	if err != nil {
 		a.logger.Fatal(err)
 	}
 }
@@ -69,7 +71,12 @@ func (a *App) initWorkerPool(ctx context.Context) {
 }
 
 func (a *App) initGRPCServer() {
-	a.grpcServer = grpc.NewServer(grpc.ConnectionTimeout(a.cfg.Node.GRPC.Timeout))
+	creds, err := transport.ServerCredentials(a.cfg.Node.GRPC.TLS, a.node.Cipher)
+	if err != nil {
+		a.logger.Fatal(err)
+	}
+
+	a.grpcServer = grpc.NewServer(grpc.ConnectionTimeout(a.cfg.Node.GRPC.Timeout), grpc.Creds(creds))
 }
 
 func (a *App) initScheduler() {
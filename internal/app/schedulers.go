@@ -32,6 +32,39 @@ func serveSchedulers(ctx context.Context, app *App) {
 		}
 	}
 
+	if app.cfg.Schedulers.BlockProducer.Enabled {
+		app.scheduler.Every(app.cfg.Schedulers.BlockProducer.Interval)
+		if !app.cfg.Schedulers.BlockProducer.StartImmediately {
+			app.scheduler.WaitForSchedule()
+		}
+
+		if _, err := app.scheduler.Do(func() { app.node.ProduceBlock(ctx) }); err != nil {
+			app.logger.Fatal(err)
+		}
+	}
+
+	if app.cfg.Schedulers.Renewal.Enabled {
+		app.scheduler.Every(app.cfg.Schedulers.Renewal.Interval)
+		if !app.cfg.Schedulers.Renewal.StartImmediately {
+			app.scheduler.WaitForSchedule()
+		}
+
+		if _, err := app.scheduler.Do(func() { app.node.RenewAuthentication(ctx) }); err != nil {
+			app.logger.Fatal(err)
+		}
+	}
+
+	if app.cfg.Schedulers.Heartbeat.Enabled {
+		app.scheduler.Every(app.cfg.Schedulers.Heartbeat.Interval)
+		if !app.cfg.Schedulers.Heartbeat.StartImmediately {
+			app.scheduler.WaitForSchedule()
+		}
+
+		if _, err := app.scheduler.Do(func() { app.node.Heartbeat(ctx) }); err != nil {
+			app.logger.Fatal(err)
+		}
+	}
+
 	app.scheduler.StartAsync()
 
 	<-ctx.Done()
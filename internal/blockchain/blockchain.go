@@ -6,13 +6,12 @@ package blockchain
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"sync"
 
-	"github.com/nutsdb/nutsdb"
-	"google.golang.org/protobuf/proto"
-
 	"authentication-chains/internal/cipher"
+	"authentication-chains/internal/storage"
 	"authentication-chains/internal/types"
 )
 
@@ -20,107 +19,222 @@ import (
 
 // blockchain implements chain logic.
 type blockchain struct {
-	db          *nutsdb.DB
+	db          storage.Storage
 	lastBlock   *types.Block
 	genesisHash []byte
 	mutex       sync.RWMutex
 }
 
 // New creates a new blockchain instance.
-func New(db *nutsdb.DB) (Blockchain, error) {
-	var lastBlock *types.Block
+func New(db storage.Storage) (Blockchain, error) {
+	var (
+		lastBlock   *types.Block
+		genesisHash []byte
+	)
+
+	db.View(func(tx storage.Tx) error {
+		if entry, err := tx.Get(types.BucketIndexes, types.KeyGenesisHash); err == nil {
+			genesisHash = entry
+		}
 
-	db.View(func(tx *nutsdb.Tx) error {
 		lastBlockIndex, err := tx.Get(types.BucketIndexes, types.KeyLastBlock)
 		if err != nil {
 			return err
 		}
 
-		block, err := tx.Get(types.BucketBlocks, lastBlockIndex.Value)
+		block, err := tx.Get(types.BucketBlocks, lastBlockIndex)
 		if err != nil {
 			return err
 		}
 
-		lastBlock = types.DeserializeBlock(block.Value)
+		lastBlock = types.DeserializeBlock(block)
 
 		return nil
 	})
 
 	return &blockchain{
-		lastBlock: lastBlock,
-		mutex:     sync.RWMutex{},
-		db:        db,
+		lastBlock:   lastBlock,
+		genesisHash: genesisHash,
+		mutex:       sync.RWMutex{},
+		db:          db,
 	}, nil
 }
 
-// // AddToMemPool adds a device authentication request to the mem-pool.
-// func (b *blockchain) AddToMemPool(request *types.DeviceAuthenticationRequest) {
-// 	b.mempool.Add(request)
-// }
-
 // SetGenesisHash sets the genesis block hash.
-func (b *blockchain) SetGenesisHash(hash []byte) {
+func (b *blockchain) SetGenesisHash(hash []byte) error {
 	b.mutex.Lock()
 	defer b.mutex.Unlock()
 
+	if err := b.db.Update(func(tx storage.Tx) error {
+		return tx.Put(types.BucketIndexes, types.KeyGenesisHash, hash, types.InfinityTTL)
+	}); err != nil {
+		return err
+	}
+
 	b.genesisHash = hash
+
+	return nil
+}
+
+// Reset removes the blocks of the chain, of the competing branches and the genesis block hash,
+// the chain starts over once the genesis block hash is set.
+func (b *blockchain) Reset() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if err := b.db.Update(func(tx storage.Tx) error {
+		blocks, err := tx.GetAll(types.BucketBlocks)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return err
+		}
+
+		for _, entry := range blocks {
+			if err = tx.Delete(types.BucketBlocks, entry.Key); err != nil {
+				return err
+			}
+
+			err = tx.Delete(types.BucketIndexes, types.DeserializeBlock(entry.Value).Hash)
+			if err != nil && !errors.Is(err, storage.ErrNotFound) {
+				return err
+			}
+		}
+
+		forks, err := tx.GetAll(types.BucketForks)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return err
+		}
+
+		for _, entry := range forks {
+			if err = tx.Delete(types.BucketForks, entry.Key); err != nil {
+				return err
+			}
+		}
+
+		for _, key := range [][]byte{types.KeyLastBlock, types.KeyGenesisHash} {
+			if err = tx.Delete(types.BucketIndexes, key); err != nil && !errors.Is(err, storage.ErrNotFound) {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	b.lastBlock = nil
+	b.genesisHash = nil
+
+	return nil
 }
 
-// CreateBlock creates a new block from the mem-pool.
-func (b *blockchain) CreateBlock(dar *types.DeviceAuthenticationRequest) (*types.Block, error) {
+// GetGenesisHash returns the genesis block hash.
+func (b *blockchain) GetGenesisHash() []byte {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	return b.genesisHash
+}
+
+// CreateBlock creates a new block from provided transactions, the validators of the block are committed by its hash.
+func (b *blockchain) CreateBlock(txs types.Transactions, validators [][]byte) (*types.Block, error) {
 	b.mutex.RLock()
 	defer b.mutex.RUnlock()
 
 	var block *types.Block
 	if b.lastBlock != nil {
-		block = types.NewBlock(b.lastBlock.Hash, b.lastBlock.Index+1, dar)
+		block = types.NewBlock(b.lastBlock.Hash, b.lastBlock.Index+1, txs)
 	} else {
-		block = types.NewBlock(b.genesisHash, 1, dar)
+		block = types.NewBlock(b.genesisHash, 1, txs)
 	}
 
-	data, err := proto.Marshal(block)
-	if err != nil {
+	block.ValidatorIds = validators
+
+	var err error
+
+	if block.MerkleRoot, err = cipher.MerkleRoot(block); err != nil {
 		return nil, err
 	}
 
-	block.Hash = cipher.Hash(data)
+	if block.Hash, err = cipher.HashBlock(block); err != nil {
+		return nil, err
+	}
 
 	return block, nil
 }
 
+// ValidateBlock checks that the block can be appended to the chain.
+func (b *blockchain) ValidateBlock(block *types.Block) error {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	return b.db.View(func(tx storage.Tx) error {
+		return b.validateBlock(tx, block)
+	})
+}
+
 // AddBlock adds a block to the chain.
 func (b *blockchain) AddBlock(block *types.Block) error {
 	b.mutex.Lock()
 	defer b.mutex.Unlock()
 
-	return b.db.Update(func(tx *nutsdb.Tx) error {
-		if b.lastBlock != nil {
-			data, _ := tx.Get(types.BucketBlocks, uint64ToBytes(block.Index))
-			if data != nil {
-				return fmt.Errorf("%w: block %x: already exists", ErrBlockValidation, block.Hash)
-			}
+	var forked bool
 
-			if block.Index != b.lastBlock.Index+1 {
-				return fmt.Errorf("%w: block %x: index is not valid", ErrBlockValidation, block.Hash)
-			}
-
-			if !bytes.Equal(block.PrevHash, b.lastBlock.Hash) {
-				return fmt.Errorf("%w: block %x: prev hash is not valid", ErrBlockValidation, block.Hash)
-			}
+	if err := b.db.Update(func(tx storage.Tx) error {
+		err := b.validateBlock(tx, block)
+		switch {
+		case errors.Is(err, ErrForkDetected):
+			forked = true
+			return tx.Put(types.BucketForks, block.Hash, block.Serialize(), types.InfinityTTL)
+		case err != nil:
+			return err
 		}
 
-		if err := tx.Put(types.BucketBlocks, uint64ToBytes(block.Index), block.Serialize(), types.InfinityTTL); err != nil {
+		if err = putBlock(tx, block); err != nil {
 			return err
 		}
 
-		if err := tx.Put(types.BucketIndexes, types.KeyLastBlock, uint64ToBytes(block.Index), types.InfinityTTL); err != nil {
+		if err = tx.Put(types.BucketIndexes, types.KeyLastBlock, uint64ToBytes(block.Index), types.InfinityTTL); err != nil {
 			return err
 		}
 
 		b.lastBlock = block
 
 		return nil
-	})
+	}); err != nil {
+		return err
+	}
+
+	if forked {
+		return fmt.Errorf("%w: block %d %x is stored as a competing branch", ErrForkDetected, block.Index, block.Hash)
+	}
+
+	return nil
+}
+
+// validateBlock checks the block against the last block of the chain.
+func (b *blockchain) validateBlock(tx storage.Tx, block *types.Block) error {
+	if b.lastBlock == nil {
+		return nil
+	}
+
+	if block.Index == b.lastBlock.Index+1 && bytes.Equal(block.PrevHash, b.lastBlock.Hash) {
+		return nil
+	}
+
+	if b.hasBlock(tx, block) {
+		return fmt.Errorf("%w: block %x: already exists", ErrBlockValidation, block.Hash)
+	}
+
+	// the block competes with the chain but links to a known block
+	if b.hasParent(tx, block) {
+		return fmt.Errorf("%w: %w: block %x", ErrBlockValidation, ErrForkDetected, block.Hash)
+	}
+
+	if block.Index != b.lastBlock.Index+1 {
+		return fmt.Errorf("%w: block %x: index is not valid", ErrBlockValidation, block.Hash)
+	}
+
+	return fmt.Errorf("%w: block %x: prev hash is not valid", ErrBlockValidation, block.Hash)
 }
 
 // GetBlock returns a block by index.
@@ -130,13 +244,13 @@ func (b *blockchain) GetBlock(index uint64) (*types.Block, error) {
 
 	var block *types.Block
 
-	if err := b.db.View(func(tx *nutsdb.Tx) error {
+	if err := b.db.View(func(tx storage.Tx) error {
 		entry, err := tx.Get(types.BucketBlocks, uint64ToBytes(index))
 		if err != nil {
 			return err
 		}
 
-		block = types.DeserializeBlock(entry.Value)
+		block = types.DeserializeBlock(entry)
 
 		return nil
 	}); err != nil {
@@ -153,7 +267,7 @@ func (b *blockchain) GetAllBlocks(from, to uint64) ([]*types.Block, error) {
 
 	var blocks []*types.Block
 
-	if err := b.db.View(func(tx *nutsdb.Tx) error {
+	if err := b.db.View(func(tx storage.Tx) error {
 		entries, err := tx.RangeScan(types.BucketBlocks, uint64ToBytes(from), uint64ToBytes(to))
 		if err != nil {
 			return err
@@ -181,11 +295,16 @@ func (b *blockchain) GetLastBlock() *types.Block {
 	}
 
 	lastBlock := &types.Block{
-		Hash:      b.lastBlock.Hash,
-		PrevHash:  b.lastBlock.PrevHash,
-		Index:     b.lastBlock.Index,
-		Dar:       b.lastBlock.Dar,
-		Timestamp: b.lastBlock.Timestamp,
+		Hash:        b.lastBlock.Hash,
+		PrevHash:    b.lastBlock.PrevHash,
+		Index:       b.lastBlock.Index,
+		Dars:        b.lastBlock.Dars,
+		Timestamp:   b.lastBlock.Timestamp,
+		MerkleRoot:  b.lastBlock.MerkleRoot,
+		Revocations: b.lastBlock.Revocations,
+		Renewals:    b.lastBlock.Renewals,
+		Rotations:   b.lastBlock.Rotations,
+		Votes:       b.lastBlock.Votes,
 	}
 
 	return lastBlock
@@ -11,4 +11,8 @@ import (
 var (
 	ErrBlockValidation = errors.New("block validation failed")
 	ErrEmptyMemPool    = errors.New("mempool is empty")
+	ErrDARInMemPool    = errors.New("device authentication request is already in the mem-pool")
+	ErrForkDetected    = errors.New("fork detected")
+	ErrNotFoundBlock   = errors.New("block not found")
+	ErrChainIntegrity  = errors.New("chain integrity is broken")
 )
@@ -0,0 +1,450 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package blockchain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	"authentication-chains/internal/storage"
+	"authentication-chains/internal/types"
+)
+
+// Reorganization is a switch of the chain from the current branch to a competing one.
+type Reorganization struct {
+	// Removed are blocks of the abandoned branch from the last block down to the fork point.
+	Removed []*types.Block
+	// Added are blocks of the winning branch from the fork point up to the new last block.
+	Added []*types.Block
+}
+
+// QuorumVerifier verifies that the block is approved by the quorum of its validators
+// and returns the number of the approvals counted to the quorum.
+type QuorumVerifier func(block *types.Block) (uint64, error)
+
+// branch is a sequence of blocks with the number of their quorum-verified approvals.
+type branch struct {
+	blocks    []*types.Block
+	approvals uint64
+}
+
+// ForkChoice selects the best of the competing branches. It returns nil if the current branch wins.
+// A competing branch with a block the verifier rejects is not chosen.
+//
+// Branches are compared by the following rules:
+//   - the branch with more quorum-verified approvals wins;
+//   - the longer branch wins;
+//   - the branch whose first block has the earliest timestamp wins;
+//   - the branch whose first block has the lowest hash wins.
+func (b *blockchain) ForkChoice(verify QuorumVerifier) (*Reorganization, error) {
+	reorgs, err := b.getReorganizations()
+	if err != nil {
+		return nil, err
+	}
+
+	// the verifier may read the chain, so the quorum is verified once the chain is unlocked
+	verified := make(map[string]uint64)
+	rejected := make(map[string]bool)
+
+	approvals := func(block *types.Block) (uint64, error) {
+		if count, ok := verified[string(block.Hash)]; ok {
+			return count, nil
+		}
+
+		if rejected[string(block.Hash)] {
+			return 0, fmt.Errorf("%w: block %x has no quorum", ErrBlockValidation, block.Hash)
+		}
+
+		count, err := verify(block)
+		if err != nil {
+			rejected[string(block.Hash)] = true
+			return 0, err
+		}
+
+		verified[string(block.Hash)] = count
+
+		return count, nil
+	}
+
+	var (
+		best       *Reorganization
+		bestBranch branch
+	)
+
+	for _, reorg := range reorgs {
+		added := branch{blocks: reorg.Added}
+		current := branch{blocks: reversed(reorg.Removed)}
+
+		if added.approvals, err = countApprovals(added.blocks, approvals); err != nil {
+			continue
+		}
+
+		// blocks of the current branch are accepted already, the ones the verifier rejects now add no approvals
+		for _, block := range current.blocks {
+			if blockApprovals, err := approvals(block); err == nil {
+				current.approvals += blockApprovals
+			}
+		}
+
+		if compareBranches(added, current) <= 0 {
+			continue
+		}
+
+		if best == nil || compareBranches(added, bestBranch) > 0 {
+			best, bestBranch = reorg, added
+		}
+	}
+
+	return best, nil
+}
+
+// getReorganizations returns the reorganizations to the competing branches which link to the chain.
+func (b *blockchain) getReorganizations() ([]*Reorganization, error) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	if b.lastBlock == nil {
+		return nil, nil
+	}
+
+	var reorgs []*Reorganization
+
+	if err := b.db.View(func(tx storage.Tx) error {
+		entries, err := tx.GetAll(types.BucketForks)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				return nil
+			}
+
+			return err
+		}
+
+		forks := make(map[string]*types.Block, len(entries))
+		parents := make(map[string]bool, len(entries))
+
+		for _, entry := range entries {
+			block := types.DeserializeBlock(entry.Value)
+			forks[string(block.Hash)] = block
+			parents[string(block.PrevHash)] = true
+		}
+
+		for _, tip := range sortedTips(forks, parents) {
+			reorg, err := b.newReorganization(tx, forks, tip)
+			if err != nil {
+				return err
+			}
+
+			if reorg != nil {
+				reorgs = append(reorgs, reorg)
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return reorgs, nil
+}
+
+// Reorganize switches the chain to the winning branch of the reorganization.
+// Blocks of the abandoned branch are kept as a competing branch. The replay is run in the same transaction,
+// so the chain isn't switched if it fails. It must not call the chain.
+func (b *blockchain) Reorganize(reorg *Reorganization, replay func(tx storage.Tx) error) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if len(reorg.Added) == 0 {
+		return fmt.Errorf("%w: reorganization has no blocks to add", ErrBlockValidation)
+	}
+
+	if len(reorg.Removed) != 0 && !bytes.Equal(b.lastBlock.Hash, reorg.Removed[0].Hash) {
+		return fmt.Errorf("%w: last block %x has changed", ErrBlockValidation, b.lastBlock.Hash)
+	}
+
+	lastBlock := reorg.Added[len(reorg.Added)-1]
+
+	if err := b.db.Update(func(tx storage.Tx) error {
+		for _, block := range reorg.Removed {
+			if err := tx.Delete(types.BucketBlocks, uint64ToBytes(block.Index)); err != nil {
+				return err
+			}
+
+			if err := tx.Delete(types.BucketIndexes, block.Hash); err != nil && !errors.Is(err, storage.ErrNotFound) {
+				return err
+			}
+
+			if err := tx.Put(types.BucketForks, block.Hash, block.Serialize(), types.InfinityTTL); err != nil {
+				return err
+			}
+		}
+
+		for _, block := range reorg.Added {
+			if err := tx.Delete(types.BucketForks, block.Hash); err != nil {
+				return err
+			}
+
+			if err := putBlock(tx, block); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Put(types.BucketIndexes, types.KeyLastBlock, uint64ToBytes(lastBlock.Index), types.InfinityTTL); err != nil {
+			return err
+		}
+
+		return replay(tx)
+	}); err != nil {
+		return err
+	}
+
+	b.lastBlock = lastBlock
+
+	return nil
+}
+
+// DiscardBranch removes blocks of the competing branch.
+func (b *blockchain) DiscardBranch(blocks []*types.Block) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.db.Update(func(tx storage.Tx) error {
+		for _, block := range blocks {
+			if err := tx.Delete(types.BucketForks, block.Hash); err != nil && !errors.Is(err, storage.ErrNotFound) {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// PruneForks removes blocks of the competing branches which are at least depth blocks below the last block,
+// they can't replace the finalized blocks of the chain anymore.
+func (b *blockchain) PruneForks(depth uint64) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.lastBlock == nil || b.lastBlock.Index <= depth {
+		return nil
+	}
+
+	finalized := b.lastBlock.Index - depth
+
+	return b.db.Update(func(tx storage.Tx) error {
+		entries, err := tx.GetAll(types.BucketForks)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				return nil
+			}
+
+			return err
+		}
+
+		for _, entry := range entries {
+			if types.DeserializeBlock(entry.Value).Index > finalized {
+				continue
+			}
+
+			if err = tx.Delete(types.BucketForks, entry.Key); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetBlockByHash returns a block of the chain or of a competing branch by hash.
+func (b *blockchain) GetBlockByHash(hash []byte) (*types.Block, error) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	var block *types.Block
+
+	if err := b.db.View(func(tx storage.Tx) error {
+		if index, err := tx.Get(types.BucketIndexes, hash); err == nil {
+			if entry, err := tx.Get(types.BucketBlocks, index); err == nil {
+				block = types.DeserializeBlock(entry)
+				return nil
+			}
+		}
+
+		if entry, err := tx.Get(types.BucketForks, hash); err == nil {
+			block = types.DeserializeBlock(entry)
+			return nil
+		}
+
+		return fmt.Errorf("%w: %x", ErrNotFoundBlock, hash)
+	}); err != nil {
+		return nil, err
+	}
+
+	return block, nil
+}
+
+// newReorganization builds the reorganization from the current branch to the branch ending with the tip.
+// It returns nil if the branch doesn't link to the chain.
+func (b *blockchain) newReorganization(tx storage.Tx, forks map[string]*types.Block, tip *types.Block) (*Reorganization, error) {
+	reorg := &Reorganization{}
+
+	for block := tip; block != nil; block = forks[string(block.PrevHash)] {
+		reorg.Added = append([]*types.Block{block}, reorg.Added...)
+	}
+
+	root := reorg.Added[0]
+	if !b.isMainParent(tx, root) {
+		return nil, nil
+	}
+
+	for index := b.lastBlock.Index; index >= root.Index; index-- {
+		block, err := getBlock(tx, index)
+		if err != nil {
+			return nil, err
+		}
+
+		reorg.Removed = append(reorg.Removed, block)
+	}
+
+	return reorg, nil
+}
+
+// hasBlock checks if the block is already stored in the chain or in a competing branch.
+func (b *blockchain) hasBlock(tx storage.Tx, block *types.Block) bool {
+	if stored, err := getBlock(tx, block.Index); err == nil && bytes.Equal(stored.Hash, block.Hash) {
+		return true
+	}
+
+	_, err := tx.Get(types.BucketForks, block.Hash)
+
+	return err == nil
+}
+
+// hasParent checks if the previous block is stored in the chain or in a competing branch.
+func (b *blockchain) hasParent(tx storage.Tx, block *types.Block) bool {
+	if _, err := tx.Get(types.BucketForks, block.PrevHash); err == nil {
+		return true
+	}
+
+	return b.isMainParent(tx, block)
+}
+
+// isMainParent checks if the previous block of the block is in the chain.
+func (b *blockchain) isMainParent(tx storage.Tx, block *types.Block) bool {
+	if block.Index == 1 {
+		first, err := getBlock(tx, 1)
+		return err == nil && bytes.Equal(first.PrevHash, block.PrevHash)
+	}
+
+	parent, err := getBlock(tx, block.Index-1)
+
+	return err == nil && bytes.Equal(parent.Hash, block.PrevHash)
+}
+
+// getBlock returns a block of the chain by index.
+func getBlock(tx storage.Tx, index uint64) (*types.Block, error) {
+	entry, err := tx.Get(types.BucketBlocks, uint64ToBytes(index))
+	if err != nil {
+		return nil, err
+	}
+
+	return types.DeserializeBlock(entry), nil
+}
+
+// putBlock stores the block in the chain and indexes it by hash.
+func putBlock(tx storage.Tx, block *types.Block) error {
+	if err := tx.Put(types.BucketBlocks, uint64ToBytes(block.Index), block.Serialize(), types.InfinityTTL); err != nil {
+		return err
+	}
+
+	return tx.Put(types.BucketIndexes, block.Hash, uint64ToBytes(block.Index), types.InfinityTTL)
+}
+
+// sortedTips returns the last blocks of competing branches ordered by hash.
+func sortedTips(forks map[string]*types.Block, parents map[string]bool) []*types.Block {
+	var tips []*types.Block
+
+	for hash, block := range forks {
+		if !parents[hash] {
+			tips = append(tips, block)
+		}
+	}
+
+	sort.Slice(tips, func(i, j int) bool {
+		return bytes.Compare(tips[i].Hash, tips[j].Hash) < 0
+	})
+
+	return tips
+}
+
+// compareBranches compares the branches by the fork-choice rules.
+// It returns a positive number if the first branch wins, a negative one if the second branch wins.
+func compareBranches(first, second branch) int {
+	switch {
+	case len(second.blocks) == 0:
+		return 1
+	case len(first.blocks) == 0:
+		return -1
+	}
+
+	if first.approvals != second.approvals {
+		return compareUint64(first.approvals, second.approvals)
+	}
+
+	firstTip, secondTip := first.blocks[len(first.blocks)-1], second.blocks[len(second.blocks)-1]
+	if firstTip.Index != secondTip.Index {
+		return compareUint64(firstTip.Index, secondTip.Index)
+	}
+
+	firstRoot, secondRoot := first.blocks[0], second.blocks[0]
+
+	switch {
+	case firstRoot.Timestamp < secondRoot.Timestamp:
+		return 1
+	case firstRoot.Timestamp > secondRoot.Timestamp:
+		return -1
+	}
+
+	return bytes.Compare(secondRoot.Hash, firstRoot.Hash)
+}
+
+// countApprovals sums the quorum-verified approvals of the blocks, it fails on the first block without the quorum.
+func countApprovals(blocks []*types.Block, approvals QuorumVerifier) (uint64, error) {
+	var total uint64
+
+	for _, block := range blocks {
+		blockApprovals, err := approvals(block)
+		if err != nil {
+			return 0, err
+		}
+
+		total += blockApprovals
+	}
+
+	return total, nil
+}
+
+func compareUint64(a, b uint64) int {
+	switch {
+	case a > b:
+		return 1
+	case a < b:
+		return -1
+	default:
+		return 0
+	}
+}
+
+func reversed(blocks []*types.Block) []*types.Block {
+	result := make([]*types.Block, len(blocks))
+
+	for i, block := range blocks {
+		result[len(blocks)-1-i] = block
+	}
+
+	return result
+}
@@ -13,3 +13,7 @@ func uint64ToBytes(num uint64) []byte {
 	binary.BigEndian.PutUint64(bytes, num)
 	return bytes
 }
+
+func bytesToUint64(bytes []byte) uint64 {
+	return binary.BigEndian.Uint64(bytes)
+}
@@ -5,6 +5,7 @@
 package blockchain
 
 import (
+	"authentication-chains/internal/storage"
 	"authentication-chains/internal/types"
 )
 
@@ -12,8 +13,10 @@ import (
 type (
 	// Blockchain - describe an interface for working with blockchain.
 	Blockchain interface {
-		// CreateBlock creates a new block from provided device authentication request.
-		CreateBlock(dar *types.DeviceAuthenticationRequest) (*types.Block, error)
+		// CreateBlock creates a new block from provided transactions, the validators of the block are committed by its hash.
+		CreateBlock(txs types.Transactions, validators [][]byte) (*types.Block, error)
+		// ValidateBlock checks that the block can be appended to the chain.
+		ValidateBlock(block *types.Block) error
 		// AddBlock adds a block to the chain.
 		AddBlock(block *types.Block) error
 		// GetBlock returns a block by index.
@@ -23,7 +26,27 @@ type (
 		// GetLastBlock returns the last block of the chain.
 		GetLastBlock() *types.Block
 		// SetGenesisHash sets the genesis block hash.
-		SetGenesisHash(hash []byte)
+		SetGenesisHash(hash []byte) error
+		// Reset removes the blocks of the chain, of the competing branches and the genesis block hash,
+		// the chain starts over once the genesis block hash is set.
+		Reset() error
+		// GetGenesisHash returns the genesis block hash.
+		GetGenesisHash() []byte
+		// ForkChoice selects the best of the competing branches. It returns nil if the current branch wins.
+		// Blocks of the competing branches are verified to be approved by the quorum.
+		ForkChoice(verify QuorumVerifier) (*Reorganization, error)
+		// Reorganize switches the chain to the winning branch of the reorganization.
+		// The replay is run in the same transaction, so the chain isn't switched if it fails.
+		Reorganize(reorg *Reorganization, replay func(tx storage.Tx) error) error
+		// DiscardBranch removes blocks of the competing branch.
+		DiscardBranch(blocks []*types.Block) error
+		// PruneForks removes blocks of the competing branches which are at least depth blocks below the last block.
+		PruneForks(depth uint64) error
+		// Verify walks the chain from the first block and verifies hashes, links and transaction signatures.
+		// Then it cross-checks every entry of the authentication table of the level against its block.
+		Verify(level uint32) (*VerificationReport, error)
+		// GetBlockByHash returns a block of the chain or of a competing branch by hash.
+		GetBlockByHash(hash []byte) (*types.Block, error)
 	}
 
 	// MemPool - describe an interface for working with memory pool.
@@ -32,9 +55,15 @@ type (
 		GetFirst() *types.DeviceAuthenticationRequest
 		// GetAll returns all device authentication requests from the mem-pool.
 		GetAll() []*types.DeviceAuthenticationRequest
-		// Add adds a device authentication request to the mem-pool.
-		Add(request *types.DeviceAuthenticationRequest)
+		// GetBatch returns up to size first device authentication requests from the mem-pool.
+		GetBatch(size int) []*types.DeviceAuthenticationRequest
+		// Add adds a device authentication request to the mem-pool, it fails if a request of the device is already there.
+		Add(request *types.DeviceAuthenticationRequest) error
 		// Remove removes a device authentication request from the mem-pool.
 		Remove(request *types.DeviceAuthenticationRequest)
+		// Exists checks if a device authentication request of the device is in the mem-pool.
+		Exists(deviceID []byte) bool
+		// Len returns the number of device authentication requests in the mem-pool.
+		Len() int
 	}
 )
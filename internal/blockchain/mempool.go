@@ -20,7 +20,7 @@ type memPool struct {
 }
 
 // NewMemPool creates a new mem-pool instance.
-func newMemPool() MemPool {
+func NewMemPool() MemPool {
 	return &memPool{
 		memPool: make([]*types.DeviceAuthenticationRequest, 0),
 	}
@@ -49,12 +49,35 @@ func (m *memPool) GetAll() []*types.DeviceAuthenticationRequest {
 	return result
 }
 
-// Add adds a device authentication request to the mem-pool.
-func (m *memPool) Add(request *types.DeviceAuthenticationRequest) {
+// GetBatch returns up to size first device authentication requests from the mem-pool.
+func (m *memPool) GetBatch(size int) []*types.DeviceAuthenticationRequest {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if size <= 0 || size > len(m.memPool) {
+		size = len(m.memPool)
+	}
+
+	result := make([]*types.DeviceAuthenticationRequest, size)
+	copy(result, m.memPool[:size])
+
+	return result
+}
+
+// Add adds a device authentication request to the mem-pool, it fails if a request of the device is already there.
+func (m *memPool) Add(request *types.DeviceAuthenticationRequest) error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
+	for _, r := range m.memPool {
+		if bytes.Equal(r.DeviceId, request.DeviceId) {
+			return ErrDARInMemPool
+		}
+	}
+
 	m.memPool = append(m.memPool, request)
+
+	return nil
 }
 
 // Remove removes a device authentication request from the mem-pool.
@@ -69,3 +92,25 @@ func (m *memPool) Remove(request *types.DeviceAuthenticationRequest) {
 		}
 	}
 }
+
+// Exists checks if a device authentication request of the device is in the mem-pool.
+func (m *memPool) Exists(deviceID []byte) bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	for _, r := range m.memPool {
+		if bytes.Equal(r.DeviceId, deviceID) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Len returns the number of device authentication requests in the mem-pool.
+func (m *memPool) Len() int {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return len(m.memPool)
+}
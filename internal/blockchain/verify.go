@@ -0,0 +1,310 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package blockchain
+
+import (
+	"bytes"
+	"crypto"
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+
+	"authentication-chains/internal/cipher"
+	"authentication-chains/internal/storage"
+	"authentication-chains/internal/types"
+)
+
+type (
+	// VerificationReport is the result of the chain integrity verification.
+	VerificationReport struct {
+		// Blocks is the number of blocks verified before the first broken link.
+		Blocks uint64
+		// Entries is the number of cross-checked authentication table entries.
+		Entries uint64
+		// BrokenLink is the first block failed verification, nil if the chain is intact.
+		BrokenLink *BrokenLink
+		// MismatchedEntries are authentication table entries which don't match their blocks.
+		MismatchedEntries []*MismatchedEntry
+	}
+
+	// BrokenLink is a block failed verification.
+	BrokenLink struct {
+		Index  uint64
+		Hash   []byte
+		Reason string
+	}
+
+	// MismatchedEntry is an authentication table entry which doesn't match its block.
+	MismatchedEntry struct {
+		DeviceID   []byte
+		BlockIndex uint64
+		Reason     string
+	}
+)
+
+// IsValid returns true if neither broken links nor mismatched entries are found.
+func (r *VerificationReport) IsValid() bool {
+	return r.BrokenLink == nil && len(r.MismatchedEntries) == 0
+}
+
+// Verify walks the chain from the first block and verifies hashes, links and transaction signatures.
+// Then it cross-checks every entry of the authentication table of the level against its block.
+func (b *blockchain) Verify(level uint32) (*VerificationReport, error) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	report := &VerificationReport{}
+
+	if err := b.db.View(func(tx storage.Tx) error {
+		report.BrokenLink = b.verifyBlocks(tx, report)
+
+		entries, err := tx.GetAll(types.BucketAuthenticationTableLevel(level))
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				return nil
+			}
+
+			return err
+		}
+
+		for _, data := range entries {
+			var entry types.AuthenticationEntry
+			if err = proto.Unmarshal(data.Value, &entry); err != nil {
+				report.MismatchedEntries = append(report.MismatchedEntries, &MismatchedEntry{
+					DeviceID: data.Key,
+					Reason:   "entry is corrupted: " + err.Error(),
+				})
+
+				continue
+			}
+
+			report.Entries++
+
+			if reason := verifyEntry(tx, &entry); reason != "" {
+				report.MismatchedEntries = append(report.MismatchedEntries, &MismatchedEntry{
+					DeviceID:   entry.DeviceId,
+					BlockIndex: entry.BlockIndex,
+					Reason:     reason,
+				})
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// verifyBlocks verifies blocks up to the last one and returns the first broken link.
+func (b *blockchain) verifyBlocks(tx storage.Tx, report *VerificationReport) *BrokenLink {
+	if b.lastBlock == nil {
+		return nil
+	}
+
+	prevHash := b.genesisHash
+
+	for index := uint64(1); index <= b.lastBlock.Index; index++ {
+		block, reason := verifyBlock(tx, index, prevHash)
+		if reason != "" {
+			link := &BrokenLink{Index: index, Reason: reason}
+			if block != nil {
+				link.Hash = block.Hash
+			}
+
+			return link
+		}
+
+		report.Blocks++
+		prevHash = block.Hash
+	}
+
+	return nil
+}
+
+// verifyBlock verifies the block with the index and returns the reason of the failure.
+// Linkage of the first block is checked only if the genesis hash is known.
+func verifyBlock(tx storage.Tx, index uint64, prevHash []byte) (*types.Block, string) {
+	data, err := tx.Get(types.BucketBlocks, uint64ToBytes(index))
+	if err != nil {
+		return nil, "block is missing"
+	}
+
+	block := &types.Block{}
+	if err = proto.Unmarshal(data, block); err != nil {
+		return nil, "block is corrupted: " + err.Error()
+	}
+
+	if block.Index != index {
+		return block, fmt.Sprintf("block is stored under index %d", index)
+	}
+
+	hash, err := cipher.HashBlock(block)
+	if err != nil || !bytes.Equal(hash, block.Hash) {
+		return block, "hash mismatch"
+	}
+
+	if (index != 1 || prevHash != nil) && !bytes.Equal(block.PrevHash, prevHash) {
+		return block, fmt.Sprintf("prev hash %x doesn't link to %x", block.PrevHash, prevHash)
+	}
+
+	root, err := cipher.MerkleRoot(block)
+	if err != nil || !bytes.Equal(root, block.MerkleRoot) {
+		return block, "merkle root mismatch"
+	}
+
+	for _, dar := range block.Dars {
+		if err = cipher.VerifyDAR(dar); err != nil {
+			return block, fmt.Sprintf("invalid dar of device %x", dar.DeviceId)
+		}
+	}
+
+	for _, revocation := range block.Revocations {
+		publicKey, err := getPublicKey(tx, revocation.SignerId)
+		if err == nil {
+			err = cipher.VerifyRevocation(revocation, publicKey)
+		}
+
+		if err != nil {
+			return block, fmt.Sprintf("invalid revocation of device %x", revocation.DeviceId)
+		}
+	}
+
+	for _, renewal := range block.Renewals {
+		publicKey, err := getPublicKey(tx, renewal.DeviceId)
+		if err == nil {
+			err = cipher.VerifyRenewal(renewal, publicKey)
+		}
+
+		if err != nil {
+			return block, fmt.Sprintf("invalid renewal of device %x", renewal.DeviceId)
+		}
+	}
+
+	for _, rotation := range block.Rotations {
+		publicKey, err := getPublicKey(tx, rotation.DeviceId)
+		if err == nil {
+			err = cipher.VerifyRotation(rotation, publicKey)
+		}
+
+		if err != nil {
+			return block, fmt.Sprintf("invalid key rotation of device %x", rotation.DeviceId)
+		}
+	}
+
+	return block, ""
+}
+
+// verifyEntry checks that the entry matches the transactions of its blocks and returns the reason of the mismatch.
+func verifyEntry(tx storage.Tx, entry *types.AuthenticationEntry) string {
+	block, err := getBlock(tx, entry.BlockIndex)
+	if err != nil {
+		return "block is missing"
+	}
+
+	if !bytes.Equal(block.Hash, entry.BlockHash) {
+		return fmt.Sprintf("block hash %x differs from %x", entry.BlockHash, block.Hash)
+	}
+
+	var (
+		clusterHeadID []byte
+		validity      uint64
+		found         bool
+	)
+
+	// blocks of the previous versions identify devices by PEM public keys
+	for _, dar := range block.Dars {
+		if bytes.Equal(cipher.DeviceID(dar.DeviceId), entry.DeviceId) {
+			clusterHeadID, validity, found = cipher.DeviceID(dar.ClusterHeadId), dar.Validity, true
+		}
+	}
+
+	for _, renewal := range block.Renewals {
+		if bytes.Equal(cipher.DeviceID(renewal.DeviceId), entry.DeviceId) {
+			clusterHeadID, validity, found = cipher.DeviceID(renewal.ClusterHeadId), renewal.Validity, true
+		}
+	}
+
+	for _, rotation := range block.Rotations {
+		if bytes.Equal(rotation.NewDeviceId, entry.DeviceId) {
+			clusterHeadID, validity, found = cipher.DeviceID(rotation.ClusterHeadId), rotation.Validity, true
+		}
+	}
+
+	switch {
+	case !found:
+		return "block has no transaction of the device"
+	case !bytes.Equal(clusterHeadID, entry.ClusterHeadId):
+		return "cluster head mismatch"
+	case block.NotAfter(validity) != entry.NotAfter:
+		return "validity period mismatch"
+	}
+
+	if entry.RotatedTo != nil {
+		if reason := verifyRotatedEntry(tx, entry); reason != "" {
+			return reason
+		}
+	}
+
+	if !entry.Revoked {
+		return ""
+	}
+
+	index, err := tx.Get(types.BucketIndexes, entry.RevocationBlockHash)
+	if err != nil {
+		return "revocation block is missing"
+	}
+
+	revocationBlock, err := getBlock(tx, bytesToUint64(index))
+	if err != nil {
+		return "revocation block is missing"
+	}
+
+	for _, revocation := range revocationBlock.Revocations {
+		if bytes.Equal(cipher.DeviceID(revocation.DeviceId), entry.DeviceId) {
+			return ""
+		}
+	}
+
+	return "revocation block has no revocation of the device"
+}
+
+// verifyRotatedEntry checks that the rotation block of the entry rotates the device key to the recorded one.
+func verifyRotatedEntry(tx storage.Tx, entry *types.AuthenticationEntry) string {
+	index, err := tx.Get(types.BucketIndexes, entry.RotationBlockHash)
+	if err != nil {
+		return "rotation block is missing"
+	}
+
+	rotationBlock, err := getBlock(tx, bytesToUint64(index))
+	if err != nil {
+		return "rotation block is missing"
+	}
+
+	for _, rotation := range rotationBlock.Rotations {
+		if bytes.Equal(cipher.DeviceID(rotation.DeviceId), entry.DeviceId) && bytes.Equal(rotation.NewDeviceId, entry.RotatedTo) {
+			return ""
+		}
+	}
+
+	return "rotation block has no key rotation of the device"
+}
+
+// getPublicKey returns the public key of the device from the key bucket.
+// Ids of the previous versions are public keys themselves.
+func getPublicKey(tx storage.Tx, deviceID []byte) (crypto.PublicKey, error) {
+	if publicKey, err := cipher.DeserializePublicKey(deviceID); err == nil {
+		return publicKey, nil
+	}
+
+	data, err := tx.Get(types.BucketKeys, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.DeserializePublicKey(data)
+}
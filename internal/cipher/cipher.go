@@ -5,19 +5,16 @@
 package cipher
 
 import (
-	"bytes"
 	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha256"
-	"crypto/x509"
-	"encoding/gob"
-	"encoding/pem"
+	"errors"
 	"fmt"
 
-	"github.com/nutsdb/nutsdb"
 	"google.golang.org/protobuf/proto"
 
+	"authentication-chains/internal/storage"
 	"authentication-chains/internal/types"
 )
 
@@ -30,7 +27,7 @@ const (
 	bytes256 = 2048
 )
 
-// Types for PEM encoding
+// Types for PEM encoding of RSA keys
 const (
 	typeRSAPublicKey  = "RSA PUBLIC KEY"
 	typeRSAPrivateKey = "RSA PRIVATE KEY"
@@ -38,68 +35,185 @@ const (
 
 // cipher is an implementation of the Cipher interface
 type cipher struct {
+	algorithm  Algorithm
+	privateKey crypto.Signer
+}
+
+// legacyCipher is the gob serialized RSA cipher stored by the previous versions.
+type legacyCipher struct {
 	PrivateKey *rsa.PrivateKey
 	PublicKey  *rsa.PublicKey
 }
 
-func New(db *nutsdb.DB) (Cipher, error) {
-	var c Cipher
+// New loads the cipher from the database or generates a new RSA one and stores it.
+// The stored key is encrypted with the passphrase if it's given, so keys stored in plain are encrypted on load.
+func New(db storage.Storage, passphrase []byte) (Cipher, error) {
+	if db == nil {
+		return Generate(AlgorithmRSA)
+	}
 
-	if db != nil {
-		if err := db.View(func(tx *nutsdb.Tx) error {
-			entry, err := tx.Get(types.BucketCipher, types.KeyCipher)
-			if err != nil {
-				return err
-			}
-
-			c, err = Deserialize(entry.Value)
-			if err != nil {
-				return err
-			}
-
-			return nil
-		}); err == nil {
-			return c, nil
-		}
+	c, encrypted, err := load(db, passphrase)
+
+	switch {
+	case err == nil && (encrypted || len(passphrase) == 0):
+		return c, nil
+	case err == nil:
+		return c, Save(db, c, passphrase)
+	case !errors.Is(err, storage.ErrNotFound):
+		return nil, err
 	}
 
-	// The GenerateKey method takes in a reader that returns random bits, and the number of bits
-	privateKey, err := rsa.GenerateKey(rand.Reader, bytes256)
+	c, err = Generate(AlgorithmRSA)
 	if err != nil {
 		return nil, err
 	}
 
-	c = cipher{
-		PrivateKey: privateKey,
-		PublicKey:  &privateKey.PublicKey,
+	if err = Save(db, c, passphrase); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// ChangeStoredPassphrase re-encrypts the key stored in the database and the pending one with the new passphrase,
+// the keys are stored in plain if the new passphrase is empty.
+func ChangeStoredPassphrase(db storage.Storage, oldPassphrase, newPassphrase []byte) error {
+	c, _, err := load(db, oldPassphrase)
+	if err != nil {
+		return err
+	}
+
+	pending, err := LoadPending(db, oldPassphrase)
+	switch {
+	case err == nil:
+		if err = SavePending(db, pending, newPassphrase); err != nil {
+			return err
+		}
+	case !errors.Is(err, storage.ErrNotFound):
+		return err
+	}
+
+	return Save(db, c, newPassphrase)
+}
+
+// load loads the cipher from the database and reports whether the stored key is encrypted.
+func load(db storage.Storage, passphrase []byte) (Cipher, bool, error) {
+	var (
+		c         Cipher
+		encrypted bool
+	)
+
+	if err := db.View(func(tx storage.Tx) error {
+		entry, err := tx.Get(types.BucketCipher, types.KeyCipher)
+		if err != nil {
+			return err
+		}
+
+		encrypted = IsEncryptedPrivateKey(entry)
+
+		c, err = Deserialize(entry, passphrase)
+
+		return err
+	}); err != nil {
+		return nil, false, err
 	}
 
-	if db != nil {
-		if err = db.Update(func(tx *nutsdb.Tx) error {
-			return tx.Put(types.BucketCipher, types.KeyCipher, c.Serialize(), types.InfinityTTL)
-		}); err != nil {
-			return nil, err
+	return c, encrypted, nil
+}
+
+// Save stores the cipher in the database, the key is encrypted if the passphrase is given.
+func Save(db storage.Storage, c Cipher, passphrase []byte) error {
+	return save(db, types.KeyCipher, c, passphrase)
+}
+
+// SavePending stores the cipher which is going to replace the stored one, the key is encrypted if the passphrase is given.
+func SavePending(db storage.Storage, c Cipher, passphrase []byte) error {
+	return save(db, types.KeyPendingCipher, c, passphrase)
+}
+
+// LoadPending loads the cipher stored by SavePending.
+func LoadPending(db storage.Storage, passphrase []byte) (Cipher, error) {
+	var c Cipher
+
+	if err := db.View(func(tx storage.Tx) error {
+		entry, err := tx.Get(types.BucketCipher, types.KeyPendingCipher)
+		if err != nil {
+			return err
 		}
+
+		c, err = Deserialize(entry, passphrase)
+
+		return err
+	}); err != nil {
+		return nil, err
 	}
 
 	return c, nil
 }
 
-// GetPublicKey returns the public rsa key.
-func (c cipher) GetPublicKey() *rsa.PublicKey {
-	return c.PublicKey
+// PromotePending replaces the stored cipher with the pending one.
+func PromotePending(db storage.Storage) error {
+	return db.Update(func(tx storage.Tx) error {
+		entry, err := tx.Get(types.BucketCipher, types.KeyPendingCipher)
+		if err != nil {
+			return err
+		}
+
+		if err = tx.Put(types.BucketCipher, types.KeyCipher, entry, types.InfinityTTL); err != nil {
+			return err
+		}
+
+		return tx.Delete(types.BucketCipher, types.KeyPendingCipher)
+	})
 }
 
-// SerializePublicKey serializes the public key.
-func (c cipher) SerializePublicKey() []byte {
-	publicKeyBytes := x509.MarshalPKCS1PublicKey(c.PublicKey)
+// save stores the cipher in the database under the key.
+func save(db storage.Storage, key []byte, c Cipher, passphrase []byte) error {
+	data := c.Serialize()
+
+	if len(passphrase) != 0 {
+		var err error
+		if data, err = EncryptPrivateKey(data, passphrase); err != nil {
+			return err
+		}
+	}
+
+	return db.Update(func(tx storage.Tx) error {
+		return tx.Put(types.BucketCipher, key, data, types.InfinityTTL)
+	})
+}
 
-	b := pem.Block{
-		Type:  typeRSAPublicKey,
-		Bytes: publicKeyBytes,
+// Generate generates a new cipher with the key of the algorithm.
+func Generate(algorithm Algorithm) (Cipher, error) {
+	privateKey, err := generateKey(algorithm)
+	if err != nil {
+		return nil, err
 	}
 
-	return pem.EncodeToMemory(&b)
+	return cipher{
+		algorithm:  algorithm,
+		privateKey: privateKey,
+	}, nil
+}
+
+// Algorithm returns the algorithm of the key.
+func (c cipher) Algorithm() Algorithm {
+	return c.algorithm
+}
+
+// GetPublicKey returns the public key.
+func (c cipher) GetPublicKey() crypto.PublicKey {
+	return c.privateKey.Public()
+}
+
+// DeviceID returns the fingerprint of the public key which identifies the device.
+func (c cipher) DeviceID() []byte {
+	return Fingerprint(c.GetPublicKey())
+}
+
+// SerializePublicKey serializes the public key.
+func (c cipher) SerializePublicKey() []byte {
+	return SerializePublicKey(c.GetPublicKey())
 }
 
 func (c cipher) ToStringPublicKey() string {
@@ -108,28 +222,26 @@ func (c cipher) ToStringPublicKey() string {
 
 // SerializePrivateKey serializes the private key.
 func (c cipher) SerializePrivateKey() []byte {
-	privateKeyBytes := x509.MarshalPKCS1PrivateKey(c.PrivateKey)
-
-	b := pem.Block{
-		Type:  typeRSAPrivateKey,
-		Bytes: privateKeyBytes,
-	}
-
-	return pem.EncodeToMemory(&b)
+	return SerializePrivateKey(c.privateKey)
 }
 
 func (c cipher) ToStringPrivateKey() string {
 	return string(c.SerializePrivateKey())
 }
 
-// GetPrivateKey returns the private rsa key.
-func (c cipher) GetPrivateKey() *rsa.PrivateKey {
-	return c.PrivateKey
+// GetPrivateKey returns the private key.
+func (c cipher) GetPrivateKey() crypto.Signer {
+	return c.privateKey
 }
 
-// Encrypt encrypts the given plain text using the public key.
+// Encrypt encrypts the given plain text using the public key, it's supported by RSA keys only.
 func (c cipher) Encrypt(plainText []byte) ([]byte, error) {
-	cipherText, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, c.PublicKey, plainText, nil)
+	publicKey, ok := c.GetPublicKey().(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: direct encryption with %s key", ErrUnsupportedAlgorithm, c.algorithm)
+	}
+
+	cipherText, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, publicKey, plainText, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -137,9 +249,14 @@ func (c cipher) Encrypt(plainText []byte) ([]byte, error) {
 	return cipherText, nil
 }
 
-// Decrypt decrypts the given cipher text using the private key.
+// Decrypt decrypts the given cipher text using the private key, it's supported by RSA keys only.
 func (c cipher) Decrypt(cipherText []byte) ([]byte, error) {
-	plainText, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, c.PrivateKey, cipherText, nil)
+	privateKey, ok := c.privateKey.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: direct decryption with %s key", ErrUnsupportedAlgorithm, c.algorithm)
+	}
+
+	plainText, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, privateKey, cipherText, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -147,9 +264,22 @@ func (c cipher) Decrypt(cipherText []byte) ([]byte, error) {
 	return plainText, nil
 }
 
-// DecryptContent decrypts the given Content.
-func (c cipher) DecryptContent(cipherText []byte) (*types.Content, error) {
-	plainText, err := c.Decrypt(cipherText)
+// DecryptContent decrypts the given Content in the format of the envelope version.
+func (c cipher) DecryptContent(cipherText []byte, version uint32) (*types.Content, error) {
+	var (
+		plainText []byte
+		err       error
+	)
+
+	switch version {
+	case types.EnvelopeVersionDirect:
+		plainText, err = c.Decrypt(cipherText)
+	case types.EnvelopeVersionHybrid:
+		plainText, err = openEnvelope(c.privateKey, cipherText)
+	default:
+		err = fmt.Errorf("%w: %d", ErrUnsupportedEnvelope, version)
+	}
+
 	if err != nil {
 		return nil, err
 	}
@@ -164,34 +294,23 @@ func (c cipher) DecryptContent(cipherText []byte) (*types.Content, error) {
 
 // Sign signs the given data using the private key.
 func (c cipher) Sign(data []byte) ([]byte, error) {
-	// In order to generate the signature, we provide a random number generator,
-	// our private key, the hashing algorithm that we used, and the hash sum
-	// of our message
-	return rsa.SignPSS(rand.Reader, c.PrivateKey, crypto.SHA256, Hash(data), nil)
+	return sign(c.privateKey, data)
 }
 
 // VerifySignature verifies the given signature against the given data using the public key.
 func (c cipher) VerifySignature(signature []byte, data []byte) error {
-	// To verify the signature, we provide the public key, the hashing algorithm
-	// the hash sum of our message and the signature we generated previously
-	// there is an optional "options" parameter which can omit for now
-	return rsa.VerifyPSS(c.PublicKey, crypto.SHA256, Hash(data), signature, nil)
+	return VerifySignature(c.GetPublicKey(), signature, data)
 }
 
 // Serialize serializes the Cipher into bytes.
 func (c cipher) Serialize() []byte {
-	buffer := new(bytes.Buffer)
-	encoder := gob.NewEncoder(buffer)
-
-	if err := encoder.Encode(c); err != nil {
-		panic("cipher serialization error: " + err.Error())
-	}
-
-	return buffer.Bytes()
+	return c.SerializePrivateKey()
 }
 
-// SignDAR signs the given DeviceAuthenticationRequest.
+// SignDAR attaches the public key to the given DeviceAuthenticationRequest and signs it.
 func (c cipher) SignDAR(dar *types.DeviceAuthenticationRequest) error {
+	dar.PublicKey = c.SerializePublicKey()
+
 	data, err := proto.Marshal(dar)
 	if err != nil {
 		return fmt.Errorf("failed to marshal dar: %w", err)
@@ -205,22 +324,237 @@ func (c cipher) SignDAR(dar *types.DeviceAuthenticationRequest) error {
 	return nil
 }
 
-// HashBlock without a hash field.
-func (c cipher) HashBlock(block *types.Block) ([]byte, error) {
-	bc := &types.Block{
-		Hash:      nil,
-		PrevHash:  block.PrevHash,
-		Index:     block.Index,
-		Dar:       block.Dar,
-		Timestamp: block.Timestamp,
+// SignRevocation signs the given DeviceRevocationRequest.
+func (c cipher) SignRevocation(revocation *types.DeviceRevocationRequest) error {
+	revocation.SignerId = c.DeviceID()
+
+	data, err := proto.Marshal(revocation)
+	if err != nil {
+		return fmt.Errorf("failed to marshal revocation: %w", err)
 	}
 
-	data, err := proto.Marshal(bc)
+	revocation.Signature, err = c.Sign(data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal block: %w", err)
+		return fmt.Errorf("failed to sign revocation: %w", err)
 	}
 
-	result := Hash(data)
+	return nil
+}
+
+// SignRenewal signs the given DeviceRenewalRequest.
+func (c cipher) SignRenewal(renewal *types.DeviceRenewalRequest) error {
+	data, err := proto.Marshal(renewal)
+	if err != nil {
+		return fmt.Errorf("failed to marshal renewal: %w", err)
+	}
+
+	renewal.Signature, err = c.Sign(data)
+	if err != nil {
+		return fmt.Errorf("failed to sign renewal: %w", err)
+	}
+
+	return nil
+}
+
+// SignRotation attaches the ids and the new public key to the given DeviceKeyRotationRequest
+// and signs it with both the current and the next key.
+func (c cipher) SignRotation(rotation *types.DeviceKeyRotationRequest, next Cipher) error {
+	rotation.DeviceId = c.DeviceID()
+	rotation.NewDeviceId = next.DeviceID()
+	rotation.NewPublicKey = next.SerializePublicKey()
 
-	return result, nil
+	data, err := proto.Marshal(unsignedRotation(rotation))
+	if err != nil {
+		return fmt.Errorf("failed to marshal rotation: %w", err)
+	}
+
+	if rotation.Signature, err = c.Sign(data); err != nil {
+		return fmt.Errorf("failed to sign rotation: %w", err)
+	}
+
+	if rotation.NewSignature, err = next.Sign(data); err != nil {
+		return fmt.Errorf("failed to sign rotation with the new key: %w", err)
+	}
+
+	return nil
+}
+
+// SignRegistration attaches the public key to the given NodeRegistrationRequest and signs it.
+func (c cipher) SignRegistration(request *types.NodeRegistrationRequest) error {
+	request.PublicKey = c.SerializePublicKey()
+	request.Signature = nil
+
+	data, err := proto.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal registration: %w", err)
+	}
+
+	request.Signature, err = c.Sign(data)
+	if err != nil {
+		return fmt.Errorf("failed to sign registration: %w", err)
+	}
+
+	return nil
+}
+
+// SignContent attaches the sender id to the given Content and signs it.
+func (c cipher) SignContent(content *types.Content) error {
+	content.SenderId = c.DeviceID()
+
+	data, err := proto.Marshal(unsignedContent(content))
+	if err != nil {
+		return fmt.Errorf("failed to marshal content: %w", err)
+	}
+
+	if content.Signature, err = c.Sign(data); err != nil {
+		return fmt.Errorf("failed to sign content: %w", err)
+	}
+
+	return nil
+}
+
+// SignMessage attaches the sender id to the given Message and signs its envelope.
+func (c cipher) SignMessage(message *types.Message) error {
+	message.SenderId = c.DeviceID()
+
+	data, err := proto.Marshal(unsignedMessage(message))
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	if message.Signature, err = c.Sign(data); err != nil {
+		return fmt.Errorf("failed to sign message: %w", err)
+	}
+
+	return nil
+}
+
+// SignDeviceVerification attaches the requester id to the given VerifyDeviceRequest and signs it.
+func (c cipher) SignDeviceVerification(request *types.VerifyDeviceRequest) error {
+	request.RequesterId = c.DeviceID()
+
+	data, err := proto.Marshal(unsignedDeviceVerification(request))
+	if err != nil {
+		return fmt.Errorf("failed to marshal device verification: %w", err)
+	}
+
+	if request.Signature, err = c.Sign(data); err != nil {
+		return fmt.Errorf("failed to sign device verification: %w", err)
+	}
+
+	return nil
+}
+
+// SignDeviceVerificationResult attaches the signer id to the given VerifyDeviceResponse and signs it.
+func (c cipher) SignDeviceVerificationResult(response *types.VerifyDeviceResponse) error {
+	response.SignerId = c.DeviceID()
+
+	data, err := proto.Marshal(unsignedDeviceVerificationResult(response))
+	if err != nil {
+		return fmt.Errorf("failed to marshal device verification result: %w", err)
+	}
+
+	if response.Signature, err = c.Sign(data); err != nil {
+		return fmt.Errorf("failed to sign device verification result: %w", err)
+	}
+
+	return nil
+}
+
+// SignVerificationHop attaches the node id to the given VerificationHop and signs it.
+func (c cipher) SignVerificationHop(hop *types.VerificationHop) error {
+	hop.NodeId = c.DeviceID()
+
+	data, err := proto.Marshal(unsignedVerificationHop(hop))
+	if err != nil {
+		return fmt.Errorf("failed to marshal verification hop: %w", err)
+	}
+
+	if hop.Signature, err = c.Sign(data); err != nil {
+		return fmt.Errorf("failed to sign verification hop: %w", err)
+	}
+
+	return nil
+}
+
+// SignFetchMessages attaches the device id to the given FetchMessagesRequest and signs it.
+func (c cipher) SignFetchMessages(request *types.FetchMessagesRequest) error {
+	request.DeviceId = c.DeviceID()
+
+	data, err := proto.Marshal(unsignedFetchMessages(request))
+	if err != nil {
+		return fmt.Errorf("failed to marshal fetch messages request: %w", err)
+	}
+
+	if request.Signature, err = c.Sign(data); err != nil {
+		return fmt.Errorf("failed to sign fetch messages request: %w", err)
+	}
+
+	return nil
+}
+
+// SignAckMessages attaches the device id to the given AckMessagesRequest and signs it.
+func (c cipher) SignAckMessages(request *types.AckMessagesRequest) error {
+	request.DeviceId = c.DeviceID()
+
+	data, err := proto.Marshal(unsignedAckMessages(request))
+	if err != nil {
+		return fmt.Errorf("failed to marshal ack messages request: %w", err)
+	}
+
+	if request.Signature, err = c.Sign(data); err != nil {
+		return fmt.Errorf("failed to sign ack messages request: %w", err)
+	}
+
+	return nil
+}
+
+// SignDeregistration attaches the device id to the given NodeDeregistrationRequest and signs it.
+func (c cipher) SignDeregistration(request *types.NodeDeregistrationRequest) error {
+	request.DeviceId = c.DeviceID()
+
+	data, err := proto.Marshal(unsignedDeregistration(request))
+	if err != nil {
+		return fmt.Errorf("failed to marshal deregistration: %w", err)
+	}
+
+	if request.Signature, err = c.Sign(data); err != nil {
+		return fmt.Errorf("failed to sign deregistration: %w", err)
+	}
+
+	return nil
+}
+
+// SignClusterHeadAnnouncement signs the given ClusterHeadAnnouncement.
+func (c cipher) SignClusterHeadAnnouncement(announcement *types.ClusterHeadAnnouncement) error {
+	data, err := proto.Marshal(unsignedClusterHeadAnnouncement(announcement))
+	if err != nil {
+		return fmt.Errorf("failed to marshal cluster head announcement: %w", err)
+	}
+
+	if announcement.Signature, err = c.Sign(data); err != nil {
+		return fmt.Errorf("failed to sign cluster head announcement: %w", err)
+	}
+
+	return nil
+}
+
+// SignVote signs the given BlockVote.
+func (c cipher) SignVote(vote *types.BlockVote) error {
+	data, err := proto.Marshal(vote)
+	if err != nil {
+		return fmt.Errorf("failed to marshal vote: %w", err)
+	}
+
+	vote.Signature, err = c.Sign(data)
+	if err != nil {
+		return fmt.Errorf("failed to sign vote: %w", err)
+	}
+
+	return nil
+}
+
+// HashBlock hashes the block header without a hash field.
+func (c cipher) HashBlock(block *types.Block) ([]byte, error) {
+	return HashBlock(block)
 }
@@ -0,0 +1,151 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package cipher
+
+import (
+	"crypto"
+	"crypto/aes"
+	gocipher "crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+
+	"authentication-chains/internal/types"
+)
+
+// contentKeySize is the size of the AES-256 content key.
+const contentKeySize = 32
+
+// sealEnvelope encrypts the plain text with a content key for the owner of the public key.
+// The content key is random and wrapped with RSA keys, for EC keys it's agreed with an ephemeral key.
+func sealEnvelope(pubKey crypto.PublicKey, plainText []byte) ([]byte, error) {
+	var (
+		envelope   types.Envelope
+		contentKey []byte
+		err        error
+	)
+
+	if rsaKey, ok := pubKey.(*rsa.PublicKey); ok {
+		contentKey = make([]byte, contentKeySize)
+		if _, err = rand.Read(contentKey); err != nil {
+			return nil, err
+		}
+
+		envelope.WrappedKey, err = rsa.EncryptOAEP(sha256.New(), rand.Reader, rsaKey, contentKey, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to wrap content key: %w", err)
+		}
+	} else {
+		receiverKey, err := ecdhPublicKey(pubKey)
+		if err != nil {
+			return nil, err
+		}
+
+		ephemeralKey, err := receiverKey.Curve().GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+
+		contentKey, err = agreeContentKey(ephemeralKey, receiverKey, ephemeralKey.PublicKey())
+		if err != nil {
+			return nil, err
+		}
+
+		envelope.EphemeralKey = ephemeralKey.PublicKey().Bytes()
+	}
+
+	aead, err := newAEAD(contentKey)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope.Nonce = make([]byte, aead.NonceSize())
+	if _, err = rand.Read(envelope.Nonce); err != nil {
+		return nil, err
+	}
+
+	envelope.Ciphertext = aead.Seal(nil, envelope.Nonce, plainText, envelopeAAD(&envelope))
+
+	return proto.Marshal(&envelope)
+}
+
+// openEnvelope recovers the content key with the private key and decrypts the envelope.
+func openEnvelope(privateKey crypto.Signer, data []byte) ([]byte, error) {
+	var envelope types.Envelope
+	if err := proto.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidEnvelope, err)
+	}
+
+	var (
+		contentKey []byte
+		err        error
+	)
+
+	if rsaKey, ok := privateKey.(*rsa.PrivateKey); ok {
+		contentKey, err = rsa.DecryptOAEP(sha256.New(), rand.Reader, rsaKey, envelope.WrappedKey, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to unwrap content key", ErrInvalidEnvelope)
+		}
+	} else {
+		receiverKey, err := ecdhPrivateKey(privateKey)
+		if err != nil {
+			return nil, err
+		}
+
+		ephemeralKey, err := receiverKey.Curve().NewPublicKey(envelope.EphemeralKey)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid ephemeral key", ErrInvalidEnvelope)
+		}
+
+		contentKey, err = agreeContentKey(receiverKey, ephemeralKey, ephemeralKey)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidEnvelope, err)
+		}
+	}
+
+	aead, err := newAEAD(contentKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(envelope.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("%w: invalid nonce size", ErrInvalidEnvelope)
+	}
+
+	plainText, err := aead.Open(nil, envelope.Nonce, envelope.Ciphertext, envelopeAAD(&envelope))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidEnvelope, err)
+	}
+
+	return plainText, nil
+}
+
+// agreeContentKey derives the content key from the ECDH shared secret bound to the ephemeral key.
+func agreeContentKey(privateKey *ecdh.PrivateKey, publicKey, ephemeralKey *ecdh.PublicKey) ([]byte, error) {
+	secret, err := privateKey.ECDH(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return Hash(append(secret, ephemeralKey.Bytes()...)), nil
+}
+
+// envelopeAAD authenticates the key material, so it can't be swapped with the one of another envelope.
+func envelopeAAD(envelope *types.Envelope) []byte {
+	return append(append([]byte{}, envelope.WrappedKey...), envelope.EphemeralKey...)
+}
+
+func newAEAD(contentKey []byte) (gocipher.AEAD, error) {
+	block, err := aes.NewCipher(contentKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return gocipher.NewGCM(block)
+}
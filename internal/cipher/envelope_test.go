@@ -0,0 +1,97 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package cipher
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestEnvelopeRoundTrip(t *testing.T) {
+	plainText := []byte("message for the owner of the key")
+
+	for _, algorithm := range []Algorithm{AlgorithmRSA, AlgorithmEd25519, AlgorithmECDSA} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			privateKey, err := generateKey(algorithm)
+			if err != nil {
+				t.Fatalf("generate key: %s", err)
+			}
+
+			sealed, err := sealEnvelope(privateKey.Public(), plainText)
+			if err != nil {
+				t.Fatalf("seal envelope: %s", err)
+			}
+
+			opened, err := openEnvelope(privateKey, sealed)
+			if err != nil {
+				t.Fatalf("open envelope: %s", err)
+			}
+
+			if !bytes.Equal(opened, plainText) {
+				t.Fatalf("opened %q, want %q", opened, plainText)
+			}
+
+			otherKey, err := generateKey(algorithm)
+			if err != nil {
+				t.Fatalf("generate other key: %s", err)
+			}
+
+			if _, err = openEnvelope(otherKey, sealed); !errors.Is(err, ErrInvalidEnvelope) {
+				t.Fatalf("open envelope with other key: got %v, want %v", err, ErrInvalidEnvelope)
+			}
+		})
+	}
+}
+
+// TestEd25519ToX25519 checks the conversion against the key of the RFC 8032 test vector 1,
+// the expected keys are the ones libsodium derives for it.
+func TestEd25519ToX25519(t *testing.T) {
+	seed := mustDecodeHex(t, "9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60")
+	ed25519PublicKey := mustDecodeHex(t, "d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a")
+	wantPublicKey := mustDecodeHex(t, "d85e07ec22b0ad881537c2f44d662d1a143cf830c57aca4305d85c7a90f6b62e")
+	wantPrivateKey := mustDecodeHex(t, "357c83864f2833cb427a2ef1c00a013cfdff2768d980c0a3a520f006904de90f")
+
+	privateKey := ed25519.NewKeyFromSeed(seed)
+
+	if got := privateKey.Public().(ed25519.PublicKey); !bytes.Equal(got, ed25519PublicKey) {
+		t.Fatalf("ed25519 public key %x, want %x", got, ed25519PublicKey)
+	}
+
+	publicKey, err := ecdhPublicKey(privateKey.Public())
+	if err != nil {
+		t.Fatalf("convert public key: %s", err)
+	}
+
+	if !bytes.Equal(publicKey.Bytes(), wantPublicKey) {
+		t.Fatalf("x25519 public key %x, want %x", publicKey.Bytes(), wantPublicKey)
+	}
+
+	ecdhKey, err := ecdhPrivateKey(privateKey)
+	if err != nil {
+		t.Fatalf("convert private key: %s", err)
+	}
+
+	if !bytes.Equal(ecdhKey.Bytes(), wantPrivateKey) {
+		t.Fatalf("x25519 private key %x, want %x", ecdhKey.Bytes(), wantPrivateKey)
+	}
+
+	if !ecdhKey.PublicKey().Equal(publicKey) {
+		t.Fatalf("x25519 public key of the private key %x, want %x", ecdhKey.PublicKey().Bytes(), wantPublicKey)
+	}
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("decode hex: %s", err)
+	}
+
+	return data
+}
@@ -9,8 +9,28 @@ import (
 )
 
 var (
-	ErrFailedDecode          = errors.New("failed to decode PEM block containing public key")
-	ErrFailedParsePublicKey  = errors.New("failed to parse encoded public key")
-	ErrFailedParsePrivateKey = errors.New("failed to parse encoded private key")
-	ErrDARVerification       = errors.New("failed to verify dar signature")
+	ErrFailedDecode             = errors.New("failed to decode PEM block containing public key")
+	ErrFailedParsePublicKey     = errors.New("failed to parse encoded public key")
+	ErrFailedParsePrivateKey    = errors.New("failed to parse encoded private key")
+	ErrDARVerification          = errors.New("failed to verify dar signature")
+	ErrMerkleProof              = errors.New("failed to verify merkle proof")
+	ErrRevocationVerification   = errors.New("failed to verify revocation signature")
+	ErrRenewalVerification      = errors.New("failed to verify renewal signature")
+	ErrRotationVerification     = errors.New("failed to verify key rotation signature")
+	ErrVoteVerification         = errors.New("failed to verify vote signature")
+	ErrRegistrationVerification = errors.New("failed to verify node registration signature")
+	ErrContentVerification      = errors.New("failed to verify content signature")
+	ErrMessageVerification      = errors.New("failed to verify message signature")
+	ErrVerificationSignature    = errors.New("failed to verify device verification signature")
+	ErrMailboxVerification      = errors.New("failed to verify mailbox request signature")
+	ErrHopVerification          = errors.New("failed to verify verification hop signature")
+	ErrAnnouncementVerification = errors.New("failed to verify cluster head announcement signature")
+	ErrLeaveVerification        = errors.New("failed to verify node deregistration signature")
+	ErrInvalidEnvelope          = errors.New("failed to open envelope")
+	ErrUnsupportedEnvelope      = errors.New("unsupported envelope version")
+	ErrUnsupportedAlgorithm     = errors.New("unsupported key algorithm")
+	ErrSignatureVerification    = errors.New("failed to verify signature")
+	ErrFingerprintMismatch      = errors.New("public key doesn't match device id")
+	ErrPassphraseRequired       = errors.New("private key is encrypted, passphrase is required")
+	ErrInvalidPassphrase        = errors.New("failed to decrypt private key, invalid passphrase")
 )
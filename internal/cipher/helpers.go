@@ -6,39 +6,62 @@ import (
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha256"
-	"crypto/x509"
 	"encoding/gob"
 	"encoding/pem"
 	"fmt"
+	"time"
 
 	"google.golang.org/protobuf/proto"
 
 	"authentication-chains/internal/types"
 )
 
+// contentNonceSize is the size of the nonce which makes the content unique.
+const contentNonceSize = 16
+
 // Deserialize deserializes the given data into a Cipher.
-func Deserialize(data []byte) (Cipher, error) {
-	var c cipher
+// Data is either a PEM private key, optionally encrypted with the passphrase, or the gob serialized RSA cipher of the previous versions.
+func Deserialize(data []byte, passphrase []byte) (Cipher, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		return FromStringPrivateKey(string(data), passphrase)
+	}
+
+	var legacy legacyCipher
 
 	buffer := bytes.NewBuffer(data)
 	decoder := gob.NewDecoder(buffer)
 
-	if err := decoder.Decode(&c); err != nil {
+	if err := decoder.Decode(&legacy); err != nil {
 		return nil, fmt.Errorf("cipher deserialization error: %w", err)
 	}
 
-	return c, nil
+	return cipher{
+		algorithm:  AlgorithmRSA,
+		privateKey: legacy.PrivateKey,
+	}, nil
 }
 
-func FromStringPrivateKey(privateKeyString string) (Cipher, error) {
-	privateKey, err := DeserializePrivateKey([]byte(privateKeyString))
+// FromStringPrivateKey creates the Cipher from the PEM private key, the algorithm is detected from the key.
+// The key encrypted with a passphrase is decrypted first.
+func FromStringPrivateKey(privateKeyString string, passphrase []byte) (Cipher, error) {
+	data, err := DecryptPrivateKey([]byte(privateKeyString), passphrase)
 	if err != nil {
 		return nil, err
 	}
 
-	return &cipher{
-		PrivateKey: privateKey,
-		PublicKey:  &privateKey.PublicKey,
+	privateKey, err := DeserializePrivateKey(data)
+	if err != nil {
+		return nil, err
+	}
+
+	algorithm, err := KeyAlgorithm(privateKey.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher{
+		algorithm:  algorithm,
+		privateKey: privateKey,
 	}, nil
 }
 
@@ -48,19 +71,25 @@ func Hash(data []byte) []byte {
 	return hash[:]
 }
 
-// HashBlock without a hash field.
+// HashBlock hashes the block header without a hash field.
 func HashBlock(block *types.Block) ([]byte, error) {
-	bc := &types.Block{
-		Hash:      nil,
-		PrevHash:  block.PrevHash,
-		Index:     block.Index,
-		Dar:       block.Dar,
-		Timestamp: block.Timestamp,
+	return HashBlockHeader(block.Header())
+}
+
+// HashBlockHeader hashes the block header without a hash field.
+func HashBlockHeader(header *types.BlockHeader) ([]byte, error) {
+	bh := &types.BlockHeader{
+		Hash:         nil,
+		PrevHash:     header.PrevHash,
+		Index:        header.Index,
+		MerkleRoot:   header.MerkleRoot,
+		Timestamp:    header.Timestamp,
+		ValidatorIds: header.ValidatorIds,
 	}
 
-	data, err := proto.Marshal(bc)
+	data, err := proto.Marshal(bh)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal block: %w", err)
+		return nil, fmt.Errorf("failed to marshal block header: %w", err)
 	}
 
 	result := Hash(data)
@@ -73,11 +102,13 @@ func VerifyDAR(dar *types.DeviceAuthenticationRequest) error {
 	copyDar := &types.DeviceAuthenticationRequest{
 		DeviceId:      dar.DeviceId,
 		ClusterHeadId: dar.ClusterHeadId,
+		Validity:      dar.Validity,
+		PublicKey:     dar.PublicKey,
 	}
 
-	pubKey, err := DeserializePublicKey(copyDar.DeviceId)
+	pubKey, err := DARPublicKey(copyDar)
 	if err != nil {
-		return fmt.Errorf("failed to deserialize public key: %w", err)
+		return err
 	}
 
 	data, err := proto.Marshal(copyDar)
@@ -92,73 +123,491 @@ func VerifyDAR(dar *types.DeviceAuthenticationRequest) error {
 	return nil
 }
 
-// VerifySignature verifies the given signature against the given data using the public key.
-func VerifySignature(publicKey *rsa.PublicKey, signature, data []byte) error {
-	return rsa.VerifyPSS(publicKey, crypto.SHA256, Hash(data), signature, nil)
+// VerifyRevocation verifies the signature of the given DeviceRevocationRequest by the public key of its signer.
+func VerifyRevocation(revocation *types.DeviceRevocationRequest, pubKey crypto.PublicKey) error {
+	copyRevocation := &types.DeviceRevocationRequest{
+		DeviceId:      revocation.DeviceId,
+		ClusterHeadId: revocation.ClusterHeadId,
+		SignerId:      revocation.SignerId,
+	}
+
+	if err := verifyKeyOwner(pubKey, copyRevocation.SignerId); err != nil {
+		return err
+	}
+
+	data, err := proto.Marshal(copyRevocation)
+	if err != nil {
+		return fmt.Errorf("failed to marshal revocation: %w", err)
+	}
+
+	if err = VerifySignature(pubKey, revocation.Signature, data); err != nil {
+		return fmt.Errorf("failed to verify revocation signature: %w", ErrRevocationVerification)
+	}
+
+	return nil
+}
+
+// VerifyRenewal verifies the signature of the given DeviceRenewalRequest by the public key of the device.
+func VerifyRenewal(renewal *types.DeviceRenewalRequest, pubKey crypto.PublicKey) error {
+	copyRenewal := &types.DeviceRenewalRequest{
+		DeviceId:      renewal.DeviceId,
+		ClusterHeadId: renewal.ClusterHeadId,
+		PrevBlockHash: renewal.PrevBlockHash,
+		Validity:      renewal.Validity,
+	}
+
+	if err := verifyKeyOwner(pubKey, copyRenewal.DeviceId); err != nil {
+		return err
+	}
+
+	data, err := proto.Marshal(copyRenewal)
+	if err != nil {
+		return fmt.Errorf("failed to marshal renewal: %w", err)
+	}
+
+	if err = VerifySignature(pubKey, renewal.Signature, data); err != nil {
+		return fmt.Errorf("failed to verify renewal signature: %w", ErrRenewalVerification)
+	}
+
+	return nil
+}
+
+// VerifyRegistration verifies the signature of the given NodeRegistrationRequest by the public key it carries,
+// the key must belong to the registered node.
+func VerifyRegistration(request *types.NodeRegistrationRequest) error {
+	if request.Node == nil {
+		return fmt.Errorf("%w: node is missing", ErrRegistrationVerification)
+	}
+
+	copyRequest := &types.NodeRegistrationRequest{
+		Node:      request.Node,
+		Nonce:     request.Nonce,
+		PublicKey: request.PublicKey,
+		JoinToken: request.JoinToken,
+	}
+
+	pubKey, err := DeserializePublicKey(copyRequest.PublicKey)
+	if err != nil {
+		return fmt.Errorf("failed to deserialize public key: %w", err)
+	}
+
+	if err = verifyKeyOwner(pubKey, copyRequest.Node.DeviceId); err != nil {
+		return err
+	}
+
+	data, err := proto.Marshal(copyRequest)
+	if err != nil {
+		return fmt.Errorf("failed to marshal registration: %w", err)
+	}
+
+	if err = VerifySignature(pubKey, request.Signature, data); err != nil {
+		return fmt.Errorf("failed to verify registration signature: %w", ErrRegistrationVerification)
+	}
+
+	return nil
+}
+
+// VerifyRotation verifies the signatures of the given DeviceKeyRotationRequest by the public key of the device
+// and by the new public key carried by the request.
+func VerifyRotation(rotation *types.DeviceKeyRotationRequest, pubKey crypto.PublicKey) error {
+	newPubKey, err := RotationPublicKey(rotation)
+	if err != nil {
+		return err
+	}
+
+	if err = verifyKeyOwner(pubKey, rotation.DeviceId); err != nil {
+		return err
+	}
+
+	data, err := proto.Marshal(unsignedRotation(rotation))
+	if err != nil {
+		return fmt.Errorf("failed to marshal rotation: %w", err)
+	}
+
+	if err = VerifySignature(pubKey, rotation.Signature, data); err != nil {
+		return fmt.Errorf("failed to verify rotation signature of the old key: %w", ErrRotationVerification)
+	}
+
+	if err = VerifySignature(newPubKey, rotation.NewSignature, data); err != nil {
+		return fmt.Errorf("failed to verify rotation signature of the new key: %w", ErrRotationVerification)
+	}
+
+	return nil
+}
+
+// RotationPublicKey returns the new public key carried by the DeviceKeyRotationRequest
+// after checking that it matches the new device id.
+func RotationPublicKey(rotation *types.DeviceKeyRotationRequest) (crypto.PublicKey, error) {
+	pubKey, err := DeserializePublicKey(rotation.NewPublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deserialize public key: %w", err)
+	}
+
+	if err = verifyKeyOwner(pubKey, rotation.NewDeviceId); err != nil {
+		return nil, err
+	}
+
+	return pubKey, nil
+}
+
+// unsignedRotation returns the copy of the DeviceKeyRotationRequest without signatures, both keys sign it.
+func unsignedRotation(rotation *types.DeviceKeyRotationRequest) *types.DeviceKeyRotationRequest {
+	return &types.DeviceKeyRotationRequest{
+		DeviceId:      rotation.DeviceId,
+		NewDeviceId:   rotation.NewDeviceId,
+		ClusterHeadId: rotation.ClusterHeadId,
+		PrevBlockHash: rotation.PrevBlockHash,
+		Validity:      rotation.Validity,
+		NewPublicKey:  rotation.NewPublicKey,
+	}
+}
+
+// NewContent creates the content of the message from the sender to the receiver with the current timestamp
+// and a random nonce. The content is the response to the content with the nonce replyTo if it's not nil.
+func NewContent(senderID, receiverID, blockHash, data, replyTo []byte) (*types.Content, error) {
+	nonce := make([]byte, contentNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return &types.Content{
+		Data:       data,
+		BlockHash:  blockHash,
+		SenderId:   senderID,
+		ReceiverId: receiverID,
+		Timestamp:  time.Now().Unix(),
+		Nonce:      nonce,
+		ReplyTo:    replyTo,
+	}, nil
+}
+
+// VerifyContent verifies the signature of the given Content by the public key of the sender.
+func VerifyContent(content *types.Content, pubKey crypto.PublicKey) error {
+	if err := verifyKeyOwner(pubKey, content.SenderId); err != nil {
+		return err
+	}
+
+	data, err := proto.Marshal(unsignedContent(content))
+	if err != nil {
+		return fmt.Errorf("failed to marshal content: %w", err)
+	}
+
+	if err = VerifySignature(pubKey, content.Signature, data); err != nil {
+		return fmt.Errorf("failed to verify content signature: %w", ErrContentVerification)
+	}
+
+	return nil
+}
+
+// VerifyDeviceVerification verifies the signature of the given VerifyDeviceRequest by the public key of the requester.
+func VerifyDeviceVerification(request *types.VerifyDeviceRequest, pubKey crypto.PublicKey) error {
+	if err := verifyKeyOwner(pubKey, request.RequesterId); err != nil {
+		return err
+	}
+
+	data, err := proto.Marshal(unsignedDeviceVerification(request))
+	if err != nil {
+		return fmt.Errorf("failed to marshal device verification: %w", err)
+	}
+
+	if err = VerifySignature(pubKey, request.Signature, data); err != nil {
+		return fmt.Errorf("failed to verify device verification signature: %w", ErrVerificationSignature)
+	}
+
+	return nil
+}
+
+// VerifyDeviceVerificationResult verifies the signature of the given VerifyDeviceResponse by the public key of the signer.
+func VerifyDeviceVerificationResult(response *types.VerifyDeviceResponse, pubKey crypto.PublicKey) error {
+	if err := verifyKeyOwner(pubKey, response.SignerId); err != nil {
+		return err
+	}
+
+	data, err := proto.Marshal(unsignedDeviceVerificationResult(response))
+	if err != nil {
+		return fmt.Errorf("failed to marshal device verification result: %w", err)
+	}
+
+	if err = VerifySignature(pubKey, response.Signature, data); err != nil {
+		return fmt.Errorf("failed to verify device verification result signature: %w", ErrVerificationSignature)
+	}
+
+	return nil
 }
 
-func SerializePublicKey(publicKey *rsa.PublicKey) []byte {
-	publicKeyBytes := x509.MarshalPKCS1PublicKey(publicKey)
+// VerifyVerificationHop verifies the signature of the given VerificationHop by the public key of the node.
+func VerifyVerificationHop(hop *types.VerificationHop, pubKey crypto.PublicKey) error {
+	if err := verifyKeyOwner(pubKey, hop.NodeId); err != nil {
+		return err
+	}
 
-	b := pem.Block{
-		Type:  typeRSAPublicKey,
-		Bytes: publicKeyBytes,
+	data, err := proto.Marshal(unsignedVerificationHop(hop))
+	if err != nil {
+		return fmt.Errorf("failed to marshal verification hop: %w", err)
 	}
 
-	return pem.EncodeToMemory(&b)
+	if err = VerifySignature(pubKey, hop.Signature, data); err != nil {
+		return fmt.Errorf("failed to verify signature of hop %x: %w", hop.NodeId, ErrHopVerification)
+	}
+
+	return nil
 }
 
-// DeserializePublicKey deserializes a public key from bytes.
-func DeserializePublicKey(data []byte) (*rsa.PublicKey, error) {
-	block, _ := pem.Decode(data)
-	if block == nil || block.Type != typeRSAPublicKey {
-		return nil, ErrFailedDecode
+// VerifyMessage verifies the signature of the envelope of the given Message by the public key of the sender.
+func VerifyMessage(message *types.Message, pubKey crypto.PublicKey) error {
+	if err := verifyKeyOwner(pubKey, message.SenderId); err != nil {
+		return err
 	}
 
-	publicKey, err := x509.ParsePKCS1PublicKey(block.Bytes)
+	data, err := proto.Marshal(unsignedMessage(message))
 	if err != nil {
-		return nil, fmt.Errorf("%w: %s", ErrFailedParsePublicKey, err)
+		return fmt.Errorf("failed to marshal message: %w", err)
 	}
 
-	return publicKey, nil
+	if err = VerifySignature(pubKey, message.Signature, data); err != nil {
+		return fmt.Errorf("failed to verify message signature: %w", ErrMessageVerification)
+	}
+
+	return nil
 }
 
-func SerializePrivateKey(privateKey *rsa.PrivateKey) []byte {
-	privateKeyBytes := x509.MarshalPKCS1PrivateKey(privateKey)
+// VerifyFetchMessages verifies the signature of the given FetchMessagesRequest by the public key of the device.
+func VerifyFetchMessages(request *types.FetchMessagesRequest, pubKey crypto.PublicKey) error {
+	if err := verifyKeyOwner(pubKey, request.DeviceId); err != nil {
+		return err
+	}
+
+	data, err := proto.Marshal(unsignedFetchMessages(request))
+	if err != nil {
+		return fmt.Errorf("failed to marshal fetch messages request: %w", err)
+	}
 
-	b := pem.Block{
-		Type:  typeRSAPrivateKey,
-		Bytes: privateKeyBytes,
+	if err = VerifySignature(pubKey, request.Signature, data); err != nil {
+		return fmt.Errorf("failed to verify fetch messages signature: %w", ErrMailboxVerification)
 	}
 
-	return pem.EncodeToMemory(&b)
+	return nil
 }
 
-// DeserializePrivateKey deserializes a private key from bytes.
-func DeserializePrivateKey(data []byte) (*rsa.PrivateKey, error) {
-	block, _ := pem.Decode(data)
-	if block == nil || block.Type != typeRSAPrivateKey {
-		return nil, ErrFailedDecode
+// VerifyAckMessages verifies the signature of the given AckMessagesRequest by the public key of the device.
+func VerifyAckMessages(request *types.AckMessagesRequest, pubKey crypto.PublicKey) error {
+	if err := verifyKeyOwner(pubKey, request.DeviceId); err != nil {
+		return err
 	}
 
-	privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	data, err := proto.Marshal(unsignedAckMessages(request))
 	if err != nil {
-		return nil, fmt.Errorf("%w: %s", ErrFailedParsePrivateKey, err)
+		return fmt.Errorf("failed to marshal ack messages request: %w", err)
+	}
+
+	if err = VerifySignature(pubKey, request.Signature, data); err != nil {
+		return fmt.Errorf("failed to verify ack messages signature: %w", ErrMailboxVerification)
+	}
+
+	return nil
+}
+
+func unsignedContent(content *types.Content) *types.Content {
+	return &types.Content{
+		Data:       content.Data,
+		BlockHash:  content.BlockHash,
+		SenderId:   content.SenderId,
+		ReceiverId: content.ReceiverId,
+		Timestamp:  content.Timestamp,
+		Nonce:      content.Nonce,
+		ReplyTo:    content.ReplyTo,
+	}
+}
+
+func unsignedDeviceVerification(request *types.VerifyDeviceRequest) *types.VerifyDeviceRequest {
+	return &types.VerifyDeviceRequest{
+		DeviceId:    request.DeviceId,
+		BlockHash:   request.BlockHash,
+		RequesterId: request.RequesterId,
+		Timestamp:   request.Timestamp,
+		Nonce:       request.Nonce,
+	}
+}
+
+func unsignedDeviceVerificationResult(response *types.VerifyDeviceResponse) *types.VerifyDeviceResponse {
+	return &types.VerifyDeviceResponse{
+		IsVerified: response.IsVerified,
+		DeviceId:   response.DeviceId,
+		BlockHash:  response.BlockHash,
+		ReplyTo:    response.ReplyTo,
+		SignerId:   response.SignerId,
+		Hops:       response.Hops,
 	}
+}
 
-	return privateKey, nil
+func unsignedVerificationHop(hop *types.VerificationHop) *types.VerificationHop {
+	return &types.VerificationHop{
+		NodeId:     hop.NodeId,
+		Level:      hop.Level,
+		DeviceId:   hop.DeviceId,
+		BlockHash:  hop.BlockHash,
+		BlockIndex: hop.BlockIndex,
+	}
 }
 
-func EncryptContent(pubKey *rsa.PublicKey, content *types.Content) ([]byte, error) {
+// unsignedMessage returns the envelope of the message, the hops and the stored flag change on the way.
+func unsignedMessage(message *types.Message) *types.Message {
+	return &types.Message{
+		SenderId:        message.SenderId,
+		ReceiverId:      message.ReceiverId,
+		Data:            message.Data,
+		EnvelopeVersion: message.EnvelopeVersion,
+	}
+}
+
+func unsignedFetchMessages(request *types.FetchMessagesRequest) *types.FetchMessagesRequest {
+	return &types.FetchMessagesRequest{
+		DeviceId:  request.DeviceId,
+		Challenge: request.Challenge,
+		Limit:     request.Limit,
+	}
+}
+
+func unsignedAckMessages(request *types.AckMessagesRequest) *types.AckMessagesRequest {
+	return &types.AckMessagesRequest{
+		DeviceId:   request.DeviceId,
+		Challenge:  request.Challenge,
+		MessageIds: request.MessageIds,
+	}
+}
+
+// VerifyDeregistration verifies the signature of the given NodeDeregistrationRequest by the public key of the leaving node.
+func VerifyDeregistration(request *types.NodeDeregistrationRequest, pubKey crypto.PublicKey) error {
+	if err := verifyKeyOwner(pubKey, request.DeviceId); err != nil {
+		return err
+	}
+
+	data, err := proto.Marshal(unsignedDeregistration(request))
+	if err != nil {
+		return fmt.Errorf("failed to marshal deregistration: %w", err)
+	}
+
+	if err = VerifySignature(pubKey, request.Signature, data); err != nil {
+		return fmt.Errorf("failed to verify deregistration signature: %w", ErrLeaveVerification)
+	}
+
+	return nil
+}
+
+func unsignedDeregistration(request *types.NodeDeregistrationRequest) *types.NodeDeregistrationRequest {
+	return &types.NodeDeregistrationRequest{
+		DeviceId:            request.DeviceId,
+		Successor:           request.Successor,
+		Children:            request.Children,
+		ChildrenEntries:     request.ChildrenEntries,
+		Nonce:               request.Nonce,
+		Timestamp:           request.Timestamp,
+		ChildrenGenesisHash: request.ChildrenGenesisHash,
+	}
+}
+
+// VerifyClusterHeadAnnouncement verifies the signature of the given ClusterHeadAnnouncement
+// by the public key of the new cluster head.
+func VerifyClusterHeadAnnouncement(announcement *types.ClusterHeadAnnouncement, pubKey crypto.PublicKey) error {
+	if announcement.ClusterHead == nil {
+		return ErrAnnouncementVerification
+	}
+
+	if err := verifyKeyOwner(pubKey, announcement.ClusterHead.DeviceId); err != nil {
+		return err
+	}
+
+	data, err := proto.Marshal(unsignedClusterHeadAnnouncement(announcement))
+	if err != nil {
+		return fmt.Errorf("failed to marshal cluster head announcement: %w", err)
+	}
+
+	if err = VerifySignature(pubKey, announcement.Signature, data); err != nil {
+		return fmt.Errorf("failed to verify cluster head announcement signature: %w", ErrAnnouncementVerification)
+	}
+
+	return nil
+}
+
+func unsignedClusterHeadAnnouncement(announcement *types.ClusterHeadAnnouncement) *types.ClusterHeadAnnouncement {
+	return &types.ClusterHeadAnnouncement{
+		ClusterHead:           announcement.ClusterHead,
+		PreviousClusterHeadId: announcement.PreviousClusterHeadId,
+		Term:                  announcement.Term,
+		Height:                announcement.Height,
+	}
+}
+
+// VerifyVote verifies the signature of the given BlockVote by the public key of the validator.
+func VerifyVote(vote *types.BlockVote, pubKey crypto.PublicKey) error {
+	copyVote := &types.BlockVote{
+		ValidatorId: vote.ValidatorId,
+		BlockHash:   vote.BlockHash,
+		IsValid:     vote.IsValid,
+	}
+
+	if err := verifyKeyOwner(pubKey, copyVote.ValidatorId); err != nil {
+		return err
+	}
+
+	data, err := proto.Marshal(copyVote)
+	if err != nil {
+		return fmt.Errorf("failed to marshal vote: %w", err)
+	}
+
+	if err = VerifySignature(pubKey, vote.Signature, data); err != nil {
+		return fmt.Errorf("failed to verify vote signature: %w", ErrVoteVerification)
+	}
+
+	return nil
+}
+
+// EncryptContent encrypts the given Content for the owner of the public key in the format of the envelope version.
+func EncryptContent(pubKey crypto.PublicKey, content *types.Content, version uint32) ([]byte, error) {
 	data, err := proto.Marshal(content)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal content: %w", err)
 	}
 
-	cipherText, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, pubKey, data, nil)
+	switch version {
+	case types.EnvelopeVersionDirect:
+		rsaKey, ok := pubKey.(*rsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("%w: direct encryption with %T", ErrUnsupportedAlgorithm, pubKey)
+		}
+
+		return rsa.EncryptOAEP(sha256.New(), rand.Reader, rsaKey, data, nil)
+	case types.EnvelopeVersionHybrid:
+		return sealEnvelope(pubKey, data)
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedEnvelope, version)
+	}
+}
+
+// DARPublicKey returns the public key carried by the DeviceAuthenticationRequest if it matches the device id.
+// Requests of the previous versions carry the PEM public key as the device id.
+func DARPublicKey(dar *types.DeviceAuthenticationRequest) (crypto.PublicKey, error) {
+	if len(dar.PublicKey) == 0 {
+		return DeserializePublicKey(dar.DeviceId)
+	}
+
+	pubKey, err := DeserializePublicKey(dar.PublicKey)
 	if err != nil {
+		return nil, fmt.Errorf("failed to deserialize public key: %w", err)
+	}
+
+	if err = verifyKeyOwner(pubKey, dar.DeviceId); err != nil {
 		return nil, err
 	}
 
-	return cipherText, nil
+	return pubKey, nil
+}
+
+// verifyKeyOwner verifies that the public key belongs to the device with the id.
+func verifyKeyOwner(pubKey crypto.PublicKey, deviceID []byte) error {
+	if !bytes.Equal(Fingerprint(pubKey), DeviceID(deviceID)) {
+		return fmt.Errorf("%w: %x", ErrFingerprintMismatch, deviceID)
+	}
+
+	return nil
 }
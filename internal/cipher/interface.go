@@ -5,15 +5,19 @@
 package cipher
 
 import (
-	"crypto/rsa"
+	"crypto"
 
 	"authentication-chains/internal/types"
 )
 
 // Cipher - describe an interface for working with crypto operations.
 type Cipher interface {
-	// GetPublicKey returns the public rsa key.
-	GetPublicKey() *rsa.PublicKey
+	// Algorithm returns the algorithm of the key.
+	Algorithm() Algorithm
+	// GetPublicKey returns the public key.
+	GetPublicKey() crypto.PublicKey
+	// DeviceID returns the fingerprint of the public key which identifies the device.
+	DeviceID() []byte
 	// SerializePublicKey serializes the public key.
 	SerializePublicKey() []byte
 	// ToStringPublicKey serializes the public key to string.
@@ -22,20 +26,49 @@ type Cipher interface {
 	SerializePrivateKey() []byte
 	// ToStringPrivateKey serializes the private key to string.
 	ToStringPrivateKey() string
-	// GetPrivateKey returns the private rsa key.
-	GetPrivateKey() *rsa.PrivateKey
-	// Encrypt encrypts the given plain text using the public key.
+	// GetPrivateKey returns the private key.
+	GetPrivateKey() crypto.Signer
+	// Encrypt encrypts the given plain text using the public key, it's supported by RSA keys only.
 	Encrypt(plainText []byte) ([]byte, error)
-	// Decrypt decrypts the given cipher text using the private key.
+	// Decrypt decrypts the given cipher text using the private key, it's supported by RSA keys only.
 	Decrypt(cipherText []byte) ([]byte, error)
-	// DecryptContent decrypts the given Content.
-	DecryptContent(cipherText []byte) (*types.Content, error)
+	// DecryptContent decrypts the given Content in the format of the envelope version.
+	DecryptContent(cipherText []byte, version uint32) (*types.Content, error)
 	// Sign signs the given data using the private key.
 	Sign(data []byte) ([]byte, error)
 	// VerifySignature verifies the given signature against the given data using the public key.
 	VerifySignature(signature []byte, data []byte) error
 	// Serialize serializes the Cipher into bytes.
 	Serialize() []byte
-	// SignDAR signs the given DeviceAuthenticationRequest.
+	// SignDAR attaches the public key to the given DeviceAuthenticationRequest and signs it.
 	SignDAR(dar *types.DeviceAuthenticationRequest) error
+	// SignRevocation signs the given DeviceRevocationRequest.
+	SignRevocation(revocation *types.DeviceRevocationRequest) error
+	// SignRenewal signs the given DeviceRenewalRequest.
+	SignRenewal(renewal *types.DeviceRenewalRequest) error
+	// SignRotation attaches the ids and the new public key to the given DeviceKeyRotationRequest
+	// and signs it with both the current and the next key.
+	SignRotation(rotation *types.DeviceKeyRotationRequest, next Cipher) error
+	// SignRegistration attaches the public key to the given NodeRegistrationRequest and signs it.
+	SignRegistration(request *types.NodeRegistrationRequest) error
+	// SignContent attaches the sender id to the given Content and signs it.
+	SignContent(content *types.Content) error
+	// SignMessage attaches the sender id to the given Message and signs its envelope.
+	SignMessage(message *types.Message) error
+	// SignDeviceVerification attaches the requester id to the given VerifyDeviceRequest and signs it.
+	SignDeviceVerification(request *types.VerifyDeviceRequest) error
+	// SignDeviceVerificationResult attaches the signer id to the given VerifyDeviceResponse and signs it.
+	SignDeviceVerificationResult(response *types.VerifyDeviceResponse) error
+	// SignVerificationHop attaches the node id to the given VerificationHop and signs it.
+	SignVerificationHop(hop *types.VerificationHop) error
+	// SignFetchMessages attaches the device id to the given FetchMessagesRequest and signs it.
+	SignFetchMessages(request *types.FetchMessagesRequest) error
+	// SignAckMessages attaches the device id to the given AckMessagesRequest and signs it.
+	SignAckMessages(request *types.AckMessagesRequest) error
+	// SignDeregistration attaches the device id to the given NodeDeregistrationRequest and signs it.
+	SignDeregistration(request *types.NodeDeregistrationRequest) error
+	// SignClusterHeadAnnouncement signs the given ClusterHeadAnnouncement.
+	SignClusterHeadAnnouncement(announcement *types.ClusterHeadAnnouncement) error
+	// SignVote signs the given BlockVote.
+	SignVote(vote *types.BlockVote) error
 }
@@ -0,0 +1,298 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package cipher
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"slices"
+)
+
+// Algorithm is the algorithm of the device key.
+type Algorithm string
+
+// Supported key algorithms.
+const (
+	AlgorithmRSA     Algorithm = "rsa"
+	AlgorithmEd25519 Algorithm = "ed25519"
+	AlgorithmECDSA   Algorithm = "ecdsa-p256"
+)
+
+// Types for PEM encoding of PKIX and PKCS#8 keys.
+const (
+	typePublicKey  = "PUBLIC KEY"
+	typePrivateKey = "PRIVATE KEY"
+)
+
+// Algorithms returns the names of supported key algorithms.
+func Algorithms() []string {
+	return []string{string(AlgorithmRSA), string(AlgorithmEd25519), string(AlgorithmECDSA)}
+}
+
+// ParseAlgorithm parses the name of the key algorithm.
+func ParseAlgorithm(name string) (Algorithm, error) {
+	if !slices.Contains(Algorithms(), name) {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, name)
+	}
+
+	return Algorithm(name), nil
+}
+
+// KeyAlgorithm returns the algorithm of the public key.
+func KeyAlgorithm(publicKey crypto.PublicKey) (Algorithm, error) {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		return AlgorithmRSA, nil
+	case ed25519.PublicKey:
+		return AlgorithmEd25519, nil
+	case *ecdsa.PublicKey:
+		if key.Curve == elliptic.P256() {
+			return AlgorithmECDSA, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %T", ErrUnsupportedAlgorithm, publicKey)
+}
+
+// SerializePublicKey serializes the public key to PEM. RSA keys are kept in PKCS#1, so existing device ids don't change,
+// other keys are serialized in PKIX.
+func SerializePublicKey(publicKey crypto.PublicKey) []byte {
+	if key, ok := publicKey.(*rsa.PublicKey); ok {
+		return pem.EncodeToMemory(&pem.Block{
+			Type:  typeRSAPublicKey,
+			Bytes: x509.MarshalPKCS1PublicKey(key),
+		})
+	}
+
+	publicKeyBytes, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		panic("public key serialization error: " + err.Error())
+	}
+
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  typePublicKey,
+		Bytes: publicKeyBytes,
+	})
+}
+
+// DeserializePublicKey deserializes a public key from bytes, the algorithm is detected from the key.
+func DeserializePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrFailedDecode
+	}
+
+	var (
+		publicKey crypto.PublicKey
+		err       error
+	)
+
+	switch block.Type {
+	case typeRSAPublicKey:
+		publicKey, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case typePublicKey:
+		publicKey, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, ErrFailedDecode
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrFailedParsePublicKey, err)
+	}
+
+	if _, err = KeyAlgorithm(publicKey); err != nil {
+		return nil, err
+	}
+
+	return publicKey, nil
+}
+
+// SerializePrivateKey serializes the private key to PEM. RSA keys are kept in PKCS#1, other keys are serialized in PKCS#8.
+func SerializePrivateKey(privateKey crypto.Signer) []byte {
+	if key, ok := privateKey.(*rsa.PrivateKey); ok {
+		return pem.EncodeToMemory(&pem.Block{
+			Type:  typeRSAPrivateKey,
+			Bytes: x509.MarshalPKCS1PrivateKey(key),
+		})
+	}
+
+	privateKeyBytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		panic("private key serialization error: " + err.Error())
+	}
+
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  typePrivateKey,
+		Bytes: privateKeyBytes,
+	})
+}
+
+// DeserializePrivateKey deserializes a private key from bytes, the algorithm is detected from the key.
+func DeserializePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrFailedDecode
+	}
+
+	var (
+		privateKey any
+		err        error
+	)
+
+	switch block.Type {
+	case typeRSAPrivateKey:
+		privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case typePrivateKey:
+		privateKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, ErrFailedDecode
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrFailedParsePrivateKey, err)
+	}
+
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedAlgorithm, privateKey)
+	}
+
+	if _, err = KeyAlgorithm(signer.Public()); err != nil {
+		return nil, err
+	}
+
+	return signer, nil
+}
+
+// Fingerprint returns the device id of the public key, it's the SHA-256 hash of the key's SPKI.
+func Fingerprint(publicKey crypto.PublicKey) []byte {
+	spki, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		panic("public key serialization error: " + err.Error())
+	}
+
+	return Hash(spki)
+}
+
+// DeviceID returns the canonical device id. Ids of the previous versions are PEM public keys,
+// they are converted to fingerprints, other ids are returned as is.
+func DeviceID(id []byte) []byte {
+	if publicKey, err := DeserializePublicKey(id); err == nil {
+		return Fingerprint(publicKey)
+	}
+
+	return id
+}
+
+// VerifySignature verifies the given signature against the given data using the public key.
+func VerifySignature(publicKey crypto.PublicKey, signature, data []byte) error {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		return rsa.VerifyPSS(key, crypto.SHA256, Hash(data), signature, nil)
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, data, signature) {
+			return ErrSignatureVerification
+		}
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, Hash(data), signature) {
+			return ErrSignatureVerification
+		}
+	default:
+		return fmt.Errorf("%w: %T", ErrUnsupportedAlgorithm, publicKey)
+	}
+
+	return nil
+}
+
+// generateKey generates a private key of the algorithm.
+func generateKey(algorithm Algorithm) (crypto.Signer, error) {
+	switch algorithm {
+	case AlgorithmRSA:
+		return rsa.GenerateKey(rand.Reader, bytes256)
+	case AlgorithmEd25519:
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		return privateKey, err
+	case AlgorithmECDSA:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, algorithm)
+	}
+}
+
+// sign signs the given data using the private key.
+func sign(privateKey crypto.Signer, data []byte) ([]byte, error) {
+	switch key := privateKey.(type) {
+	case *rsa.PrivateKey:
+		return rsa.SignPSS(rand.Reader, key, crypto.SHA256, Hash(data), nil)
+	case ed25519.PrivateKey:
+		return ed25519.Sign(key, data), nil
+	case *ecdsa.PrivateKey:
+		return ecdsa.SignASN1(rand.Reader, key, Hash(data))
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedAlgorithm, privateKey)
+	}
+}
+
+// ecdhPublicKey returns the key agreement counterpart of the EC public key.
+// Ed25519 keys are converted to X25519 ones by the birational map u = (1 + y) / (1 - y).
+func ecdhPublicKey(publicKey crypto.PublicKey) (*ecdh.PublicKey, error) {
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		return key.ECDH()
+	case ed25519.PublicKey:
+		// the key is the little-endian y coordinate with the sign of x in the top bit
+		encoded := reversed(key)
+		encoded[0] &= 0x7f
+
+		p := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+		y := new(big.Int).SetBytes(encoded)
+
+		denominator := new(big.Int).Sub(big.NewInt(1), y)
+		if denominator.Mod(denominator, p).Sign() == 0 {
+			return nil, fmt.Errorf("%w: invalid ed25519 key", ErrUnsupportedAlgorithm)
+		}
+
+		u := new(big.Int).Add(big.NewInt(1), y)
+		u.Mul(u, denominator.ModInverse(denominator, p))
+		u.Mod(u, p)
+
+		return ecdh.X25519().NewPublicKey(reversed(u.FillBytes(make([]byte, 32))))
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedAlgorithm, publicKey)
+	}
+}
+
+// ecdhPrivateKey returns the key agreement counterpart of the EC private key.
+// Ed25519 keys are converted to X25519 ones by the scalar derived from the seed.
+func ecdhPrivateKey(privateKey crypto.Signer) (*ecdh.PrivateKey, error) {
+	switch key := privateKey.(type) {
+	case *ecdsa.PrivateKey:
+		return key.ECDH()
+	case ed25519.PrivateKey:
+		digest := sha512.Sum512(key.Seed())
+		return ecdh.X25519().NewPrivateKey(digest[:32])
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedAlgorithm, privateKey)
+	}
+}
+
+func reversed(data []byte) []byte {
+	result := make([]byte, len(data))
+	for i, b := range data {
+		result[len(data)-1-i] = b
+	}
+
+	return result
+}
@@ -0,0 +1,207 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package cipher
+
+import (
+	"bytes"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+
+	"authentication-chains/internal/types"
+)
+
+// Prefixes separate leaves from inner nodes to prevent second preimage attacks.
+// Leaves of different transaction types have their own prefixes, so one can't be proven as another.
+var (
+	merkleLeafPrefix       = []byte{0x00}
+	merkleNodePrefix       = []byte{0x01}
+	merkleRevocationPrefix = []byte{0x02}
+	merkleRenewalPrefix    = []byte{0x03}
+	merkleRotationPrefix   = []byte{0x04}
+)
+
+// MerkleRoot computes the merkle root of the block transactions.
+func MerkleRoot(block *types.Block) ([]byte, error) {
+	level, err := merkleLeaves(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(level) == 0 {
+		return nil, nil
+	}
+
+	for len(level) > 1 {
+		level = merkleLevel(level)
+	}
+
+	return level[0], nil
+}
+
+// MerkleProof returns the path from the block transaction at index to the merkle root.
+// Device authentication requests go first, then revocations, renewals and rotations.
+func MerkleProof(block *types.Block, index int) ([]*types.MerkleStep, error) {
+	level, err := merkleLeaves(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if index < 0 || index >= len(level) {
+		return nil, fmt.Errorf("%w: leaf index %d is out of range", ErrMerkleProof, index)
+	}
+
+	var path []*types.MerkleStep
+
+	for len(level) > 1 {
+		// the last node of odd level has no sibling and is promoted as is
+		if sibling := index ^ 1; sibling < len(level) {
+			path = append(path, &types.MerkleStep{
+				Hash: level[sibling],
+				Left: sibling < index,
+			})
+		}
+
+		level = merkleLevel(level)
+		index /= 2
+	}
+
+	return path, nil
+}
+
+// VerifyMerkleProof verifies that the leaf is committed by the merkle root.
+func VerifyMerkleProof(root, leaf []byte, path []*types.MerkleStep) error {
+	hash := leaf
+
+	for _, step := range path {
+		if step.Left {
+			hash = merkleNode(step.Hash, hash)
+		} else {
+			hash = merkleNode(hash, step.Hash)
+		}
+	}
+
+	if !bytes.Equal(hash, root) {
+		return fmt.Errorf("%w: merkle root mismatch", ErrMerkleProof)
+	}
+
+	return nil
+}
+
+// VerifyInclusionProof verifies that the device authentication request, renewal or rotation
+// is committed by the block header.
+func VerifyInclusionProof(proof *types.InclusionProofResponse) error {
+	var (
+		leaf []byte
+		err  error
+	)
+
+	switch {
+	case proof.Header == nil:
+		return fmt.Errorf("%w: incomplete proof", ErrMerkleProof)
+	case proof.Renewal != nil:
+		leaf, err = merkleLeaf(merkleRenewalPrefix, proof.Renewal)
+	case proof.Rotation != nil:
+		leaf, err = merkleLeaf(merkleRotationPrefix, proof.Rotation)
+	case proof.Dar != nil:
+		leaf, err = merkleLeaf(merkleLeafPrefix, proof.Dar)
+	default:
+		return fmt.Errorf("%w: incomplete proof", ErrMerkleProof)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	hash, err := HashBlockHeader(proof.Header)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(hash, proof.Header.Hash) {
+		return fmt.Errorf("%w: block hash mismatch", ErrMerkleProof)
+	}
+
+	return VerifyMerkleProof(proof.Header.MerkleRoot, leaf, proof.Path)
+}
+
+func merkleLeaves(block *types.Block) ([][]byte, error) {
+	leaves := make([][]byte, 0, len(block.Dars)+len(block.Revocations)+len(block.Renewals)+len(block.Rotations))
+
+	for _, dar := range block.Dars {
+		leaf, err := merkleLeaf(merkleLeafPrefix, dar)
+		if err != nil {
+			return nil, err
+		}
+
+		leaves = append(leaves, leaf)
+	}
+
+	for _, revocation := range block.Revocations {
+		leaf, err := merkleLeaf(merkleRevocationPrefix, revocation)
+		if err != nil {
+			return nil, err
+		}
+
+		leaves = append(leaves, leaf)
+	}
+
+	for _, renewal := range block.Renewals {
+		leaf, err := merkleLeaf(merkleRenewalPrefix, renewal)
+		if err != nil {
+			return nil, err
+		}
+
+		leaves = append(leaves, leaf)
+	}
+
+	for _, rotation := range block.Rotations {
+		leaf, err := merkleLeaf(merkleRotationPrefix, rotation)
+		if err != nil {
+			return nil, err
+		}
+
+		leaves = append(leaves, leaf)
+	}
+
+	return leaves, nil
+}
+
+func merkleLevel(level [][]byte) [][]byte {
+	next := make([][]byte, 0, (len(level)+1)/2)
+
+	for i := 0; i < len(level); i += 2 {
+		if i+1 == len(level) {
+			next = append(next, level[i])
+			continue
+		}
+
+		next = append(next, merkleNode(level[i], level[i+1]))
+	}
+
+	return next
+}
+
+func merkleLeaf(prefix []byte, tx proto.Message) ([]byte, error) {
+	data, err := proto.Marshal(tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal transaction: %w", err)
+	}
+
+	leaf := make([]byte, 0, len(prefix)+len(data))
+	leaf = append(leaf, prefix...)
+	leaf = append(leaf, data...)
+
+	return Hash(leaf), nil
+}
+
+func merkleNode(left, right []byte) []byte {
+	data := make([]byte, 0, len(merkleNodePrefix)+len(left)+len(right))
+	data = append(data, merkleNodePrefix...)
+	data = append(data, left...)
+	data = append(data, right...)
+
+	return Hash(data)
+}
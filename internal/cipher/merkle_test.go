@@ -0,0 +1,210 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package cipher
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	"authentication-chains/internal/types"
+)
+
+// merkleTx is the block transaction with the prefix of its leaf.
+type merkleTx struct {
+	prefix []byte
+	tx     proto.Message
+}
+
+func TestMerkleProof(t *testing.T) {
+	tests := []struct {
+		name string
+		txs  types.Transactions
+	}{
+		{
+			name: "1 leaf",
+			txs: types.Transactions{
+				DARs: []*types.DeviceAuthenticationRequest{{DeviceId: []byte("finn")}},
+			},
+		},
+		{
+			name: "2 leaves",
+			txs: types.Transactions{
+				DARs:     []*types.DeviceAuthenticationRequest{{DeviceId: []byte("finn")}},
+				Renewals: []*types.DeviceRenewalRequest{{DeviceId: []byte("jake"), Validity: 1}},
+			},
+		},
+		{
+			name: "3 leaves",
+			txs: types.Transactions{
+				DARs: []*types.DeviceAuthenticationRequest{
+					{DeviceId: []byte("finn")},
+					{DeviceId: []byte("jake")},
+				},
+				Rotations: []*types.DeviceKeyRotationRequest{
+					{DeviceId: []byte("marceline"), NewDeviceId: []byte("marceline-2")},
+				},
+			},
+		},
+		{
+			name: "5 leaves",
+			txs: types.Transactions{
+				DARs: []*types.DeviceAuthenticationRequest{
+					{DeviceId: []byte("finn")},
+					{DeviceId: []byte("jake")},
+				},
+				Revocations: []*types.DeviceRevocationRequest{{DeviceId: []byte("bubblegum")}},
+				Renewals:    []*types.DeviceRenewalRequest{{DeviceId: []byte("marceline"), Validity: 1}},
+				Rotations: []*types.DeviceKeyRotationRequest{
+					{DeviceId: []byte("gunter"), NewDeviceId: []byte("gunter-2")},
+				},
+			},
+		},
+	}
+
+	prefixes := [][]byte{
+		merkleLeafPrefix,
+		merkleNodePrefix,
+		merkleRevocationPrefix,
+		merkleRenewalPrefix,
+		merkleRotationPrefix,
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			block := types.NewBlock([]byte("prev"), 1, test.txs)
+
+			root, err := MerkleRoot(block)
+			if err != nil {
+				t.Fatalf("merkle root: %s", err)
+			}
+
+			block.MerkleRoot = root
+
+			if block.Hash, err = HashBlock(block); err != nil {
+				t.Fatalf("hash block: %s", err)
+			}
+
+			txs := merkleTxs(block)
+
+			for index, tx := range txs {
+				leaf, err := merkleLeaf(tx.prefix, tx.tx)
+				if err != nil {
+					t.Fatalf("leaf %d: %s", index, err)
+				}
+
+				path, err := MerkleProof(block, index)
+				if err != nil {
+					t.Fatalf("proof of leaf %d: %s", index, err)
+				}
+
+				if err = VerifyMerkleProof(root, leaf, path); err != nil {
+					t.Fatalf("verify proof of leaf %d: %s", index, err)
+				}
+
+				proof := inclusionProof(block, tx, path)
+				if proof != nil {
+					if err = VerifyInclusionProof(proof); err != nil {
+						t.Fatalf("verify inclusion proof of leaf %d: %s", index, err)
+					}
+				}
+
+				for _, prefix := range prefixes {
+					if bytes.Equal(prefix, tx.prefix) {
+						continue
+					}
+
+					otherLeaf, err := merkleLeaf(prefix, tx.tx)
+					if err != nil {
+						t.Fatalf("leaf %d with prefix %x: %s", index, prefix, err)
+					}
+
+					if err = VerifyMerkleProof(root, otherLeaf, path); !errors.Is(err, ErrMerkleProof) {
+						t.Fatalf("verify leaf %d with prefix %x: got %v, want %v", index, prefix, err, ErrMerkleProof)
+					}
+				}
+
+				for step := range path {
+					tampered := tamperPath(path, step)
+
+					if err = VerifyMerkleProof(root, leaf, tampered); !errors.Is(err, ErrMerkleProof) {
+						t.Fatalf("verify leaf %d with tampered step %d: got %v, want %v", index, step, err, ErrMerkleProof)
+					}
+
+					if proof != nil {
+						proof.Path = tampered
+
+						if err = VerifyInclusionProof(proof); !errors.Is(err, ErrMerkleProof) {
+							t.Fatalf("verify inclusion proof of leaf %d with tampered step %d: got %v, want %v",
+								index, step, err, ErrMerkleProof)
+						}
+					}
+				}
+			}
+
+			for _, index := range []int{-1, len(txs)} {
+				if _, err = MerkleProof(block, index); !errors.Is(err, ErrMerkleProof) {
+					t.Fatalf("proof of leaf %d: got %v, want %v", index, err, ErrMerkleProof)
+				}
+			}
+		})
+	}
+}
+
+// merkleTxs returns the block transactions in the order of the merkle leaves.
+func merkleTxs(block *types.Block) []merkleTx {
+	var txs []merkleTx
+
+	for _, dar := range block.Dars {
+		txs = append(txs, merkleTx{prefix: merkleLeafPrefix, tx: dar})
+	}
+
+	for _, revocation := range block.Revocations {
+		txs = append(txs, merkleTx{prefix: merkleRevocationPrefix, tx: revocation})
+	}
+
+	for _, renewal := range block.Renewals {
+		txs = append(txs, merkleTx{prefix: merkleRenewalPrefix, tx: renewal})
+	}
+
+	for _, rotation := range block.Rotations {
+		txs = append(txs, merkleTx{prefix: merkleRotationPrefix, tx: rotation})
+	}
+
+	return txs
+}
+
+// inclusionProof returns the inclusion proof of the transaction, nil if the transaction can't be proven.
+func inclusionProof(block *types.Block, tx merkleTx, path []*types.MerkleStep) *types.InclusionProofResponse {
+	proof := &types.InclusionProofResponse{Header: block.Header(), Path: path}
+
+	switch tx := tx.tx.(type) {
+	case *types.DeviceAuthenticationRequest:
+		proof.Dar = tx
+	case *types.DeviceRenewalRequest:
+		proof.Renewal = tx
+	case *types.DeviceKeyRotationRequest:
+		proof.Rotation = tx
+	default:
+		return nil
+	}
+
+	return proof
+}
+
+// tamperPath returns the copy of the path with the flipped sibling hash of the step.
+func tamperPath(path []*types.MerkleStep, step int) []*types.MerkleStep {
+	tampered := make([]*types.MerkleStep, len(path))
+	copy(tampered, path)
+
+	hash := bytes.Clone(path[step].Hash)
+	hash[0] ^= 0xff
+
+	tampered[step] = &types.MerkleStep{Hash: hash, Left: path[step].Left}
+
+	return tampered
+}
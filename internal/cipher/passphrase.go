@@ -0,0 +1,167 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package cipher
+
+import (
+	"crypto/aes"
+	gocipher "crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"strconv"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// typeEncryptedPrivateKey is the PEM type of the private key encrypted with a passphrase.
+const typeEncryptedPrivateKey = "SCRYPT ENCRYPTED PRIVATE KEY"
+
+// Headers of the encrypted private key PEM block.
+const (
+	headerSalt  = "Salt"
+	headerNonce = "Nonce"
+	headerN     = "N"
+	headerR     = "R"
+	headerP     = "P"
+)
+
+// Parameters of the scrypt key derivation. The work factor of stored keys is bounded on decryption by maxScryptN
+// and by maxScryptWork for N*R*P, which bounds the memory of N*R as well.
+const (
+	scryptN           = 1 << 15
+	scryptR           = 8
+	scryptP           = 1
+	maxScryptN        = 1 << 20
+	maxScryptWork     = maxScryptN * scryptR
+	saltSize          = 16
+	passphraseKeySize = 32
+)
+
+// IsEncryptedPrivateKey returns true if the data is the PEM private key encrypted with a passphrase.
+func IsEncryptedPrivateKey(data []byte) bool {
+	block, _ := pem.Decode(data)
+	return block != nil && block.Type == typeEncryptedPrivateKey
+}
+
+// EncryptPrivateKey encrypts the PEM private key with the key derived from the passphrase by scrypt.
+// The result is the PEM block which keeps the key derivation parameters in its headers.
+func EncryptPrivateKey(privateKey []byte, passphrase []byte) ([]byte, error) {
+	if len(passphrase) == 0 {
+		return nil, ErrPassphraseRequired
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	aead, err := passphraseAEAD(passphrase, salt, scryptN, scryptR, scryptP)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{
+		Type: typeEncryptedPrivateKey,
+		Headers: map[string]string{
+			headerSalt:  hex.EncodeToString(salt),
+			headerNonce: hex.EncodeToString(nonce),
+			headerN:     strconv.Itoa(scryptN),
+			headerR:     strconv.Itoa(scryptR),
+			headerP:     strconv.Itoa(scryptP),
+		},
+		Bytes: aead.Seal(nil, nonce, privateKey, nil),
+	}), nil
+}
+
+// DecryptPrivateKey decrypts the PEM private key encrypted with the passphrase.
+// Keys which are not encrypted are returned as is.
+func DecryptPrivateKey(data []byte, passphrase []byte) ([]byte, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != typeEncryptedPrivateKey {
+		return data, nil
+	}
+
+	if len(passphrase) == 0 {
+		return nil, ErrPassphraseRequired
+	}
+
+	salt, err := hex.DecodeString(block.Headers[headerSalt])
+	if err != nil || len(salt) == 0 {
+		return nil, fmt.Errorf("%w: invalid salt", ErrFailedParsePrivateKey)
+	}
+
+	nonce, err := hex.DecodeString(block.Headers[headerNonce])
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid nonce", ErrFailedParsePrivateKey)
+	}
+
+	var params [3]int
+
+	for i, header := range []string{headerN, headerR, headerP} {
+		if params[i], err = strconv.Atoi(block.Headers[header]); err != nil || params[i] <= 0 {
+			return nil, fmt.Errorf("%w: invalid scrypt parameter %s", ErrFailedParsePrivateKey, header)
+		}
+	}
+
+	// the products are checked by division, so the huge parameters don't overflow
+	switch n, r, p := params[0], params[1], params[2]; {
+	case n > maxScryptN:
+		return nil, fmt.Errorf("%w: scrypt parameter N %d exceeds %d", ErrFailedParsePrivateKey, n, maxScryptN)
+	case r > maxScryptWork/n || p > maxScryptWork/(n*r):
+		return nil, fmt.Errorf("%w: scrypt work N*R*P exceeds %d", ErrFailedParsePrivateKey, maxScryptWork)
+	}
+
+	aead, err := passphraseAEAD(passphrase, salt, params[0], params[1], params[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrFailedParsePrivateKey, err)
+	}
+
+	if len(nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("%w: invalid nonce", ErrFailedParsePrivateKey)
+	}
+
+	privateKey, err := aead.Open(nil, nonce, block.Bytes, nil)
+	if err != nil {
+		return nil, ErrInvalidPassphrase
+	}
+
+	return privateKey, nil
+}
+
+// ChangePassphrase re-encrypts the private key with the new passphrase, the key is decrypted
+// if the new passphrase is empty.
+func ChangePassphrase(data []byte, oldPassphrase, newPassphrase []byte) ([]byte, error) {
+	privateKey, err := DecryptPrivateKey(data, oldPassphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(newPassphrase) == 0 {
+		return privateKey, nil
+	}
+
+	return EncryptPrivateKey(privateKey, newPassphrase)
+}
+
+// passphraseAEAD derives the AES-256-GCM key from the passphrase.
+func passphraseAEAD(passphrase, salt []byte, n, r, p int) (gocipher.AEAD, error) {
+	key, err := scrypt.Key(passphrase, salt, n, r, p, passphraseKeySize)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return gocipher.NewGCM(block)
+}
@@ -7,13 +7,17 @@ package client
 import (
 	"bytes"
 	"context"
+	"crypto"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/DirusK/utils/config"
 	"github.com/DirusK/utils/printer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
 	"gopkg.in/yaml.v3"
 
 	"authentication-chains/internal/cipher"
@@ -24,6 +28,9 @@ import (
 
 const tag = "client"
 
+// darStatusPollInterval is the interval of polling the status of device authentication request.
+const darStatusPollInterval = time.Second
+
 type Client struct {
 	config cfg.Client
 	ctx    context.Context
@@ -32,38 +39,49 @@ type Client struct {
 	peer   *node.Peer
 }
 
-func New(ctx context.Context, configPath string) (*Client, error) {
+func New(ctx context.Context, configPath string, passphrase []byte) (*Client, error) {
 	var cfg cfg.Client
 	if err := config.LoadFromFile(configPath, &cfg); err != nil {
 		printer.Errort(tag, err, "Failed to load config")
 		return nil, err
 	}
 
-	c, err := cipher.FromStringPrivateKey(cfg.Keys.PrivateKey)
+	c, err := cipher.FromStringPrivateKey(cfg.Keys.PrivateKey, passphrase)
 	if err != nil {
 		printer.Errort(tag, err, "Failed to load private key")
 		return nil, err
 	}
 
-	client, err := initClient(ctx, cfg.GRPC.Address)
+	nodeClient := &Client{
+		ctx:    ctx,
+		config: cfg,
+		cipher: c,
+	}
+
+	// the key of the client may be rotated, so the certificate is signed with the current one
+	client, err := initClient(ctx, cfg.GRPC, func() cipher.Cipher { return nodeClient.cipher })
 	if err != nil {
 		printer.Errort(tag, err, "Failed to grpc init client")
 		return nil, err
 	}
 
-	status, err := client.GetStatus(ctx, &types.StatusRequest{})
+	var p peer.Peer
+
+	status, err := client.GetStatus(ctx, &types.StatusRequest{}, grpc.Peer(&p))
 	if err != nil {
 		printer.Errort(tag, err, "Failed to get status from node", "address", cfg.GRPC.Address)
 		return nil, err
 	}
 
-	return &Client{
-		ctx:    ctx,
-		config: cfg,
-		cipher: c,
-		client: client,
-		peer:   node.NewPeer(status.Peer.Name, status.Peer.DeviceId, status.Peer.ClusterHeadId, status.Peer.GrpcAddress, status.Peer.Level, client),
-	}, nil
+	if err = verifyNodeIdentity(cfg.GRPC.TLS, p.AuthInfo, status.Peer.DeviceId); err != nil {
+		printer.Errort(tag, err, "Failed to verify node", "address", cfg.GRPC.Address)
+		return nil, err
+	}
+
+	nodeClient.client = client
+	nodeClient.peer = node.NewPeer(status.Peer.Name, status.Peer.DeviceId, status.Peer.ClusterHeadId, status.Peer.GrpcAddress, status.Peer.Level, client)
+
+	return nodeClient, nil
 }
 
 func (c *Client) SendDAR() (string, error) {
@@ -71,9 +89,10 @@ func (c *Client) SendDAR() (string, error) {
 	defer cancel()
 
 	dar := &types.DeviceAuthenticationRequest{
-		DeviceId:      c.cipher.SerializePublicKey(),
+		DeviceId:      c.cipher.DeviceID(),
 		ClusterHeadId: c.peer.ClusterHeadID,
 		Signature:     nil,
+		Validity:      uint64(c.config.Validity.Seconds()),
 	}
 
 	if err := c.cipher.SignDAR(dar); err != nil {
@@ -82,9 +101,10 @@ func (c *Client) SendDAR() (string, error) {
 	}
 
 	printer.Infot(tag, "Creating DAR",
-		"device_id", fmt.Sprintf("\n%s\n", dar.DeviceId),
-		"cluster_head_id", fmt.Sprintf("\n%s\n", dar.ClusterHeadId),
+		"device_id", fmt.Sprintf("%x", dar.DeviceId),
+		"cluster_head_id", fmt.Sprintf("%x", dar.ClusterHeadId),
 		"signature", fmt.Sprintf("%x", dar.Signature),
+		"validity", c.config.Validity,
 	)
 
 	printer.Infot(tag, "Sending DAR", "node", c.peer.Name, "address", c.peer.GRPCAddress, "level", c.peer.Level)
@@ -95,11 +115,45 @@ func (c *Client) SendDAR() (string, error) {
 		return "", err
 	}
 
+	printer.Infot(tag, "DAR is pending", "ticket", fmt.Sprintf("%x", response.Ticket))
+
+	response, err = c.waitDAR(ctx, response.Ticket)
+	if err != nil {
+		printer.Errort(tag, err, "DAR is not verified")
+		return "", err
+	}
+
 	printer.Infot(tag, "DAR is verified", "block_hash", fmt.Sprintf("%x", response.BlockHash))
 
 	return fmt.Sprintf("%x", response.BlockHash), nil
 }
 
+// waitDAR polls the node until the device authentication request is mined or rejected.
+func (c *Client) waitDAR(ctx context.Context, ticket []byte) (*types.DeviceAuthenticationResponse, error) {
+	ticker := time.NewTicker(darStatusPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+
+		response, err := c.client.GetDARStatus(ctx, &types.DARStatusRequest{Ticket: ticket})
+		if err != nil {
+			return nil, err
+		}
+
+		switch response.Status {
+		case types.DARStatus_DAR_STATUS_ACCEPTED:
+			return response, nil
+		case types.DARStatus_DAR_STATUS_REJECTED:
+			return nil, errors.New("dar is rejected by the cluster")
+		}
+	}
+}
+
 func (c *Client) GetBlocks(ctx context.Context, from, to uint64) ([]*types.Block, error) {
 	ctx, cancel := context.WithTimeout(c.ctx, c.config.GRPC.Timeout)
 	defer cancel()
@@ -162,11 +216,179 @@ func (c *Client) GetAuthenticationTable() (*types.AuthenticationTableResponse, e
 	return response, nil
 }
 
-func (c *Client) SendMessage(data []byte) (*types.Content, error) {
+// RenewDAR extends the authentication of the client device by proving that it still holds its key.
+func (c *Client) RenewDAR() (string, error) {
+	ctx, cancel := context.WithTimeout(c.ctx, c.config.GRPC.Timeout)
+	defer cancel()
+
+	hash, err := hex.DecodeString(c.config.BlockHash)
+	if err != nil {
+		printer.Errort(tag, err, "Failed to decode block hash")
+		return "", err
+	}
+
+	renewal := &types.DeviceRenewalRequest{
+		DeviceId:      c.cipher.DeviceID(),
+		ClusterHeadId: c.peer.ClusterHeadID,
+		PrevBlockHash: hash,
+		Validity:      uint64(c.config.Validity.Seconds()),
+	}
+
+	if err = c.cipher.SignRenewal(renewal); err != nil {
+		printer.Errort(tag, err, "Failed to sign renewal")
+		return "", err
+	}
+
+	printer.Infot(tag, "Sending renewal", "node", c.peer.Name, "address", c.peer.GRPCAddress, "level", c.peer.Level)
+
+	response, err := c.client.RenewDAR(ctx, renewal)
+	if err != nil {
+		printer.Errort(tag, err, "Renewal is not accepted")
+		return "", err
+	}
+
+	printer.Infot(tag, "Authentication is renewed", "block_hash", fmt.Sprintf("%x", response.BlockHash))
+
+	return fmt.Sprintf("%x", response.BlockHash), nil
+}
+
+// RotateKey replaces the key of the client device with the next one. The rotation is signed by both keys and
+// extends the authentication like a renewal. The config keeps the next key, encrypted if the passphrase is given.
+func (c *Client) RotateKey(next cipher.Cipher, passphrase []byte) (string, error) {
 	ctx, cancel := context.WithTimeout(c.ctx, c.config.GRPC.Timeout)
 	defer cancel()
 
+	hash, err := hex.DecodeString(c.config.BlockHash)
+	if err != nil {
+		printer.Errort(tag, err, "Failed to decode block hash")
+		return "", err
+	}
+
+	privateKey := next.SerializePrivateKey()
+	if len(passphrase) != 0 {
+		if privateKey, err = cipher.EncryptPrivateKey(privateKey, passphrase); err != nil {
+			printer.Errort(tag, err, "Failed to encrypt private key")
+			return "", err
+		}
+	}
+
+	rotation := &types.DeviceKeyRotationRequest{
+		ClusterHeadId: c.peer.ClusterHeadID,
+		PrevBlockHash: hash,
+		Validity:      uint64(c.config.Validity.Seconds()),
+	}
+
+	if err = c.cipher.SignRotation(rotation, next); err != nil {
+		printer.Errort(tag, err, "Failed to sign rotation")
+		return "", err
+	}
+
+	printer.Infot(tag, "Sending key rotation", "node", c.peer.Name, "address", c.peer.GRPCAddress, "level", c.peer.Level)
+
+	response, err := c.client.RotateKey(ctx, rotation)
+	if err != nil {
+		printer.Errort(tag, err, "Key rotation is not accepted")
+		return "", err
+	}
+
+	c.cipher = next
+	c.config.Keys = cfg.Keys{
+		PublicKey:  next.ToStringPublicKey(),
+		PrivateKey: string(privateKey),
+	}
+
+	printer.Infot(tag, "Key is rotated",
+		"device_id", fmt.Sprintf("%x", rotation.NewDeviceId),
+		"block_hash", fmt.Sprintf("%x", response.BlockHash),
+	)
+
+	return fmt.Sprintf("%x", response.BlockHash), nil
+}
+
+// RevokeDevice revokes the authentication of the client device.
+func (c *Client) RevokeDevice() (string, error) {
+	ctx, cancel := context.WithTimeout(c.ctx, c.config.GRPC.Timeout)
+	defer cancel()
+
+	revocation := &types.DeviceRevocationRequest{
+		DeviceId:      c.cipher.DeviceID(),
+		ClusterHeadId: c.peer.ClusterHeadID,
+	}
+
+	if err := c.cipher.SignRevocation(revocation); err != nil {
+		printer.Errort(tag, err, "Failed to sign revocation")
+		return "", err
+	}
+
+	printer.Infot(tag, "Sending revocation", "node", c.peer.Name, "address", c.peer.GRPCAddress, "level", c.peer.Level)
+
+	response, err := c.client.RevokeDevice(ctx, revocation)
+	if err != nil {
+		printer.Errort(tag, err, "Revocation is not accepted")
+		return "", err
+	}
+
+	printer.Infot(tag, "Device is revoked", "block_hash", fmt.Sprintf("%x", response.BlockHash))
+
+	return fmt.Sprintf("%x", response.BlockHash), nil
+}
+
+// GetTopology fetches the cluster tree from the top cluster of the node down to the depth, zero means the default one.
+// Nothing is printed on success, so the tree may be piped in the machine-readable formats.
+func (c *Client) GetTopology(depth uint32) ([]*types.TopologyNode, error) {
+	ctx, cancel := context.WithTimeout(c.ctx, c.config.GRPC.Timeout)
+	defer cancel()
+
+	response, err := c.client.GetTopology(ctx, &types.TopologyRequest{Depth: depth})
+	if err != nil {
+		printer.Errort(tag, err, "Failed to get topology", "node", c.peer.Name, "address", c.peer.GRPCAddress)
+		return nil, err
+	}
+
+	return response.Nodes, nil
+}
+
+// GetInclusionProof fetches and verifies the proof that the client device is registered in the chain.
+func (c *Client) GetInclusionProof() (*types.InclusionProofResponse, error) {
+	ctx, cancel := context.WithTimeout(c.ctx, c.config.GRPC.Timeout)
+	defer cancel()
+
+	printer.Infot(tag, "Getting inclusion proof",
+		"node", c.peer.Name,
+		"address", c.peer.GRPCAddress,
+		"level", c.peer.Level,
+	)
+
+	proof, err := c.client.GetInclusionProof(ctx, &types.InclusionProofRequest{
+		DeviceId: c.cipher.DeviceID(),
+	})
+	if err != nil {
+		printer.Errort(tag, err, "Failed to get inclusion proof")
+		return nil, err
+	}
+
+	if err = cipher.VerifyInclusionProof(proof); err != nil {
+		printer.Errort(tag, err, "Inclusion proof is not valid")
+		return nil, err
+	}
+
+	printer.Infot(tag, "Inclusion proof is valid", "block_hash", fmt.Sprintf("%x", proof.Header.Hash))
+
+	return proof, nil
+}
+
+// SendMessage sends the message to the device with the receiver id, the message is sent to the node
+// the client is connected to if the receiver id is empty. Messages to other devices are routed by the nodes.
+func (c *Client) SendMessage(receiverID, data []byte) (*types.Content, error) {
+	ctx, cancel := context.WithTimeout(c.ctx, c.config.GRPC.Timeout)
+	defer cancel()
+
+	if len(receiverID) == 0 {
+		receiverID = c.peer.DeviceID
+	}
+
 	printer.Infot(tag, "Sending message",
+		"receiver_id", fmt.Sprintf("%x", receiverID),
 		"node", c.peer.Name,
 		"address", c.peer.GRPCAddress,
 		"level", c.peer.Level,
@@ -178,50 +400,206 @@ func (c *Client) SendMessage(data []byte) (*types.Content, error) {
 		return nil, err
 	}
 
-	content := &types.Content{
-		Data:      data,
-		BlockHash: hash,
+	content, err := cipher.NewContent(c.cipher.DeviceID(), receiverID, hash, data, nil)
+	if err != nil {
+		printer.Errort(tag, err, "Failed to create message")
+		return nil, err
 	}
 
-	pubKey, err := cipher.DeserializePublicKey(c.peer.DeviceID)
+	if err = c.cipher.SignContent(content); err != nil {
+		printer.Errort(tag, err, "Failed to sign message")
+		return nil, err
+	}
+
+	pubKey, err := c.getPublicKey(ctx, receiverID)
 	if err != nil {
-		printer.Errort(tag, err, "Failed to deserialize peer public key")
+		printer.Errort(tag, err, "Failed to get receiver public key")
 		return nil, err
 	}
 
-	encryptedMessage, err := cipher.EncryptContent(pubKey, content)
+	encryptedMessage, err := cipher.EncryptContent(pubKey, content, types.EnvelopeVersionHybrid)
 	if err != nil {
 		printer.Errort(tag, err, "Failed to encrypt message")
 		return nil, err
 	}
 
-	response, err := c.client.SendMessage(ctx, &types.Message{
-		SenderId:   c.cipher.SerializePublicKey(),
-		ReceiverId: c.peer.DeviceID,
-		Data:       encryptedMessage,
-	})
+	message := types.NewMessage(c.cipher.DeviceID(), receiverID, encryptedMessage, types.EnvelopeVersionHybrid)
+
+	if err = c.cipher.SignMessage(message); err != nil {
+		printer.Errort(tag, err, "Failed to sign message envelope")
+		return nil, err
+	}
+
+	response, err := c.client.SendMessage(ctx, message)
 	if err != nil {
 		printer.Errort(tag, err, "Failed to send message")
 		return nil, err
 	}
 
-	content, err = c.cipher.DecryptContent(response.Data)
+	// the receiver is offline, so there is no response until it fetches the message from the mailbox
+	if response.Stored {
+		printer.Infot(tag, "Message is stored in the mailbox of the receiver", "node_id", fmt.Sprintf("%x", response.SenderId))
+		return nil, nil
+	}
+
+	responseContent, err := c.cipher.DecryptContent(response.Data, response.EnvelopeVersion)
 	if err != nil {
 		printer.Errort(tag, err, "Failed to decrypt message")
 		return nil, err
 	}
 
-	if !bytes.Equal(response.ReceiverId, c.cipher.SerializePublicKey()) {
+	if !bytes.Equal(response.ReceiverId, c.cipher.DeviceID()) {
 		err = errors.New("response receiver id is not equal to device id")
 		printer.Errort(tag, err)
 		return nil, err
 	}
 
-	if !bytes.Equal(response.SenderId, c.peer.DeviceID) {
-		err = errors.New("response sender id is not equal to peer id")
+	if !bytes.Equal(response.SenderId, receiverID) {
+		err = errors.New("response sender id is not equal to receiver id")
 		printer.Errort(tag, err)
 		return nil, err
 	}
 
+	if err = verifyResponse(responseContent, content, pubKey); err != nil {
+		printer.Errort(tag, err, "Failed to verify response")
+		return nil, err
+	}
+
+	return responseContent, nil
+}
+
+// FetchMessages fetches the messages kept for the client device in the mailbox of the node,
+// zero limit means all of them. The messages are decrypted and verified, the invalid ones are skipped.
+func (c *Client) FetchMessages(limit uint32) ([]InboxMessage, error) {
+	ctx, cancel := context.WithTimeout(c.ctx, c.config.GRPC.Timeout)
+	defer cancel()
+
+	challenge, err := c.getMailboxChallenge(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	request := &types.FetchMessagesRequest{
+		Challenge: challenge,
+		Limit:     limit,
+	}
+
+	if err = c.cipher.SignFetchMessages(request); err != nil {
+		printer.Errort(tag, err, "Failed to sign fetch messages request")
+		return nil, err
+	}
+
+	printer.Infot(tag, "Fetching messages", "node", c.peer.Name, "address", c.peer.GRPCAddress, "level", c.peer.Level)
+
+	response, err := c.client.FetchMessages(ctx, request)
+	if err != nil {
+		printer.Errort(tag, err, "Failed to fetch messages")
+		return nil, err
+	}
+
+	messages := make([]InboxMessage, 0, len(response.Messages))
+
+	for _, stored := range response.Messages {
+		content, err := c.openMessage(ctx, stored.Message)
+		if err != nil {
+			printer.Errort(tag, err, "Skipping invalid message", "id", fmt.Sprintf("%x", stored.Id))
+			continue
+		}
+
+		messages = append(messages, InboxMessage{
+			ID:         stored.Id,
+			ReceivedAt: time.Unix(stored.ReceivedAt, 0),
+			Content:    content,
+		})
+	}
+
+	return messages, nil
+}
+
+// AckMessages removes the messages with the ids from the mailbox of the node.
+func (c *Client) AckMessages(ids [][]byte) (uint32, error) {
+	ctx, cancel := context.WithTimeout(c.ctx, c.config.GRPC.Timeout)
+	defer cancel()
+
+	challenge, err := c.getMailboxChallenge(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	request := &types.AckMessagesRequest{
+		Challenge:  challenge,
+		MessageIds: ids,
+	}
+
+	if err = c.cipher.SignAckMessages(request); err != nil {
+		printer.Errort(tag, err, "Failed to sign ack messages request")
+		return 0, err
+	}
+
+	response, err := c.client.AckMessages(ctx, request)
+	if err != nil {
+		printer.Errort(tag, err, "Failed to acknowledge messages")
+		return 0, err
+	}
+
+	printer.Infot(tag, "Messages are acknowledged", "removed", response.Removed)
+
+	return response.Removed, nil
+}
+
+// getMailboxChallenge requests the challenge the mailbox request is signed over.
+func (c *Client) getMailboxChallenge(ctx context.Context) ([]byte, error) {
+	response, err := c.client.GetMailboxChallenge(ctx, &types.MailboxChallengeRequest{DeviceId: c.cipher.DeviceID()})
+	if err != nil {
+		printer.Errort(tag, err, "Failed to get mailbox challenge")
+		return nil, err
+	}
+
+	return response.Challenge, nil
+}
+
+// openMessage decrypts the message addressed to the client device and verifies the signature of its sender.
+func (c *Client) openMessage(ctx context.Context, message *types.Message) (*types.Content, error) {
+	if !bytes.Equal(message.ReceiverId, c.cipher.DeviceID()) {
+		return nil, errors.New("message receiver id is not equal to device id")
+	}
+
+	content, err := c.cipher.DecryptContent(message.Data, message.EnvelopeVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(content.SenderId, message.SenderId) || !bytes.Equal(content.ReceiverId, message.ReceiverId) {
+		return nil, errors.New("content is bound to other devices")
+	}
+
+	pubKey, err := c.getPublicKey(ctx, content.SenderId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = cipher.VerifyContent(content, pubKey); err != nil {
+		return nil, err
+	}
+
 	return content, nil
 }
+
+// getPublicKey fetches the public key of the device from the node and checks it against the device id.
+func (c *Client) getPublicKey(ctx context.Context, deviceID []byte) (crypto.PublicKey, error) {
+	response, err := c.client.GetPublicKey(ctx, &types.PublicKeyRequest{DeviceId: deviceID})
+	if err != nil {
+		return nil, err
+	}
+
+	pubKey, err := cipher.DeserializePublicKey(response.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(cipher.Fingerprint(pubKey), deviceID) {
+		return nil, cipher.ErrFingerprintMismatch
+	}
+
+	return pubKey, nil
+}
@@ -5,17 +5,39 @@
 package client
 
 import (
+	"bytes"
 	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"time"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/credentials"
 
+	"authentication-chains/internal/cipher"
+	"authentication-chains/internal/config"
+	"authentication-chains/internal/transport"
 	"authentication-chains/internal/types"
 )
 
-// initClient initializes a new client.
-func initClient(ctx context.Context, address string) (types.NodeClient, error) {
-	conn, err := grpc.DialContext(ctx, address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// responseSkew is the max difference between the timestamp of the response and the clock of the client.
+const responseSkew = time.Minute
+
+// initClient initializes a new client of the node, in the pinned TLS mode the certificate of the client
+// is signed with the current key returned by the identity.
+func initClient(ctx context.Context, cfg config.GRPC, identity transport.Identity) (types.NodeClient, error) {
+	pin, err := transport.PinDeviceID(cfg.TLS.PeerDeviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	creds, err := transport.DialCredentials(cfg.TLS, identity, pin)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.DialContext(ctx, cfg.Address, grpc.WithTransportCredentials(creds))
 	if err != nil {
 		return nil, err
 	}
@@ -27,3 +49,26 @@ func initClient(ctx context.Context, address string) (types.NodeClient, error) {
 
 	return types.NewNodeClient(conn), nil
 }
+
+// verifyNodeIdentity checks that the device id the node claims is the id of its certificate in the pinned TLS mode.
+func verifyNodeIdentity(cfg config.TLS, info credentials.AuthInfo, deviceID []byte) error {
+	if cfg.Mode != config.TLSModePinned || bytes.Equal(transport.DeviceID(info), deviceID) {
+		return nil
+	}
+
+	return fmt.Errorf("node certificate doesn't match its device id %x", deviceID)
+}
+
+// verifyResponse verifies that the response content is signed by the node and made for the request content.
+func verifyResponse(response, request *types.Content, pubKey crypto.PublicKey) error {
+	switch {
+	case !bytes.Equal(response.SenderId, request.ReceiverId) || !bytes.Equal(response.ReceiverId, request.SenderId):
+		return errors.New("response content is made for other devices")
+	case !bytes.Equal(response.ReplyTo, request.Nonce):
+		return errors.New("response content is made for another message")
+	case time.Since(time.Unix(response.Timestamp, 0)).Abs() > responseSkew:
+		return errors.New("response content is stale")
+	}
+
+	return cipher.VerifyContent(response, pubKey)
+}
@@ -4,10 +4,45 @@
 
 package client
 
+import (
+	"time"
+
+	"authentication-chains/internal/types"
+)
+
 type (
 	DeviceAuthenticationRequest struct {
 		DeviceID      string
 		ClusterHeadID string
 		Signature     string
+		Validity      uint64
+	}
+
+	DeviceRevocationRequest struct {
+		DeviceID      string
+		ClusterHeadID string
+		SignerID      string
+		Signature     string
+	}
+
+	DeviceRenewalRequest struct {
+		DeviceID      string
+		PrevBlockHash string
+		Validity      uint64
+		Signature     string
+	}
+
+	DeviceKeyRotationRequest struct {
+		DeviceID      string
+		NewDeviceID   string
+		PrevBlockHash string
+		Validity      uint64
+	}
+
+	// InboxMessage is the verified message fetched from the mailbox of the node.
+	InboxMessage struct {
+		ID         []byte
+		ReceivedAt time.Time
+		Content    *types.Content
 	}
 )
@@ -4,12 +4,17 @@
 
 package config
 
+import (
+	"time"
+)
+
 type (
 	Client struct {
-		Name      string `yaml:"name" validate:"required"`
-		BlockHash string `yaml:"block-hash" validate:"required"`
-		GRPC      GRPC   `yaml:"grpc" validate:"required"`
-		Keys      Keys   `yaml:"keys" validate:"required"`
+		Name      string        `yaml:"name" validate:"required"`
+		BlockHash string        `yaml:"block-hash" validate:"required"`
+		Validity  time.Duration `yaml:"validity"`
+		GRPC      GRPC          `yaml:"grpc" validate:"required"`
+		Keys      Keys          `yaml:"keys" validate:"required"`
 	}
 
 	Keys struct {
@@ -12,6 +12,39 @@ import (
 
 const DefaultPath = "configs/default.yaml"
 
+// Storage engines.
+const (
+	// EngineNutsDB stores buckets in nutsdb.
+	EngineNutsDB = "nutsdb"
+	// EngineBolt stores buckets in bbolt.
+	EngineBolt = "bbolt"
+	// EngineMemory keeps buckets in memory, the data is lost on exit.
+	EngineMemory = "memory"
+)
+
+// Quorum rules of block acceptance.
+const (
+	// QuorumMajority requires more than a half of validators.
+	QuorumMajority = "majority"
+	// QuorumBFT requires 2f+1 of 3f+1 validators, so f faulty validators are tolerated.
+	QuorumBFT = "bft"
+	// QuorumUnanimous requires all validators.
+	QuorumUnanimous = "unanimous"
+)
+
+// Transport security modes of gRPC connections.
+const (
+	// TLSModeDisabled keeps connections in plain text.
+	TLSModeDisabled = "disabled"
+	// TLSModeTLS encrypts connections and authenticates the server by its certificate.
+	TLSModeTLS = "tls"
+	// TLSModeMutual authenticates both the server and the client by certificates of the CA.
+	TLSModeMutual = "mtls"
+	// TLSModePinned authenticates both sides by certificates self-signed with their device keys,
+	// the certificates are pinned against device ids, so no CA is needed.
+	TLSModePinned = "pinned"
+)
+
 type (
 	// Config is a node configuration.
 	Config struct {
@@ -24,27 +57,144 @@ type (
 
 	// Node is a node cluster configuration.
 	Node struct {
-		Name                   string `yaml:"name" validate:"required"`
-		Level                  uint32 `yaml:"level"`
-		GenesisHash            string `yaml:"genesis-hash"`
-		ClusterHeadGRPCAddress string `yaml:"cluster-head-grpc-address"`
-		GRPC                   GRPC   `yaml:"grpc" validate:"required"`
+		Name                   string         `yaml:"name" validate:"required"`
+		Level                  uint32         `yaml:"level"`
+		GenesisHash            string         `yaml:"genesis-hash"`
+		ClusterHeadGRPCAddress string         `yaml:"cluster-head-grpc-address"`
+		GRPC                   GRPC           `yaml:"grpc" validate:"required"`
+		MemPool                MemPool        `yaml:"mem-pool" validate:"required"`
+		Authentication         Authentication `yaml:"authentication"`
+		Registration           Registration   `yaml:"registration" validate:"required"`
+		Replay                 Replay         `yaml:"replay" validate:"required"`
+		Mailbox                Mailbox        `yaml:"mailbox" validate:"required"`
+		Consensus              Consensus      `yaml:"consensus" validate:"required"`
+		Sync                   Sync           `yaml:"sync" validate:"required"`
+		Heartbeat              Heartbeat      `yaml:"heartbeat" validate:"required"`
 	}
 
 	Schedulers struct {
-		Sync    Scheduler `yaml:"sync" validate:"required"`
-		Explore Scheduler `yaml:"explore" validate:"required"`
+		Sync          Scheduler `yaml:"sync" validate:"required"`
+		Explore       Scheduler `yaml:"explore" validate:"required"`
+		BlockProducer Scheduler `yaml:"block-producer" validate:"required"`
+		Renewal       Scheduler `yaml:"renewal" validate:"required"`
+		Heartbeat     Scheduler `yaml:"heartbeat" validate:"required"`
+	}
+
+	// Consensus is a block acceptance configuration.
+	Consensus struct {
+		// Quorum is the rule of how many cluster validators must approve the block.
+		Quorum string `yaml:"quorum" validate:"required,oneof=majority bft unanimous"`
+		// FinalityDepth is the number of blocks on top of the block which make it final,
+		// competing branches below it are pruned.
+		FinalityDepth uint64 `yaml:"finality-depth" validate:"required"`
+	}
+
+	// Sync is a block synchronization configuration.
+	Sync struct {
+		// BatchSize is the number of blocks fetched from one node and written at once.
+		BatchSize int `yaml:"batch-size" validate:"required"`
+	}
+
+	// Heartbeat is a liveness configuration of peers.
+	Heartbeat struct {
+		// Timeout is how long the status of the peer is waited for.
+		Timeout time.Duration `yaml:"timeout" validate:"required"`
+		// SuspectAfter is the number of heartbeats in a row the peer misses to become suspect.
+		SuspectAfter int `yaml:"suspect-after" validate:"required"`
+		// EvictAfter is the number of heartbeats in a row the peer misses to be evicted.
+		EvictAfter int `yaml:"evict-after" validate:"required,gtefield=SuspectAfter"`
+	}
+
+	// Authentication is a policy of device authentications.
+	Authentication struct {
+		// MaxValidity is the max validity period a device can request, zero means unlimited.
+		MaxValidity time.Duration `yaml:"max-validity"`
+		// RenewBefore is how long before expiry the node renews its own authentication.
+		RenewBefore time.Duration `yaml:"renew-before"`
+	}
+
+	// Registration is a policy of node registrations.
+	Registration struct {
+		// NonceTTL is how long the nonce issued to a registering node is valid.
+		NonceTTL time.Duration `yaml:"nonce-ttl" validate:"required"`
+		// MaxNonces is the max number of unexpired nonces issued to one node.
+		MaxNonces int `yaml:"max-nonces" validate:"required"`
+		// JoinToken is the token approved by the operator of the cluster head,
+		// it's presented by the node until the node is authenticated in the cluster.
+		JoinToken string `yaml:"join-token"`
+	}
+
+	// Replay is a replay protection configuration of messages and device verifications.
+	Replay struct {
+		// Skew is the max difference between the timestamp of the request and the clock of the node.
+		Skew time.Duration `yaml:"skew" validate:"required"`
+		// CacheSize is the max number of nonces kept per sender within the skew window.
+		CacheSize int `yaml:"cache-size" validate:"required"`
+	}
+
+	// Mailbox is a configuration of mailboxes which keep messages for offline devices of the cluster.
+	Mailbox struct {
+		// ChallengeTTL is how long the challenge issued to the device for mailbox access is valid.
+		ChallengeTTL time.Duration `yaml:"challenge-ttl" validate:"required"`
+		// MaxChallenges is the max number of unexpired challenges issued to one device.
+		MaxChallenges int `yaml:"max-challenges" validate:"required"`
+		// Default is the policy of the levels which have no own policy.
+		Default MailboxPolicy `yaml:"default" validate:"required"`
+		// Levels are the policies by node levels.
+		Levels map[uint32]MailboxPolicy `yaml:"levels" validate:"dive"`
+	}
+
+	// MailboxPolicy is a quota and expiry of the device mailbox.
+	MailboxPolicy struct {
+		// TTL is how long the message is kept in the mailbox.
+		TTL time.Duration `yaml:"ttl" validate:"required"`
+		// MaxMessages is the max number of messages kept for one device.
+		MaxMessages int `yaml:"max-messages" validate:"required"`
+		// MaxBytes is the max total size of messages kept for one device, zero means unlimited.
+		MaxBytes int `yaml:"max-bytes"`
+		// MaxSenderMessages is the max number of messages of one sender kept for one device, zero means unlimited.
+		MaxSenderMessages int `yaml:"max-sender-messages"`
+	}
+
+	// MemPool is a mem-pool configuration.
+	MemPool struct {
+		// BlockSize is the max number of device authentication requests in a block.
+		// Reaching it in the mem-pool triggers block production immediately.
+		BlockSize int `yaml:"block-size" validate:"required"`
+		// TicketTTL is how long the status of device authentication request is kept.
+		TicketTTL time.Duration `yaml:"ticket-ttl" validate:"required"`
 	}
 
 	// Storage is a node database configuration.
 	Storage struct {
-		Directory string `yaml:"directory" validate:"required"`
+		// Engine is the storage backend, nutsdb is used if it is empty.
+		Engine    string `yaml:"engine" validate:"omitempty,oneof=nutsdb bbolt memory"`
+		Directory string `yaml:"directory" validate:"required_unless=Engine memory"`
 	}
 
 	// GRPC is a node server configuration.
 	GRPC struct {
 		Address string        `yaml:"address" validate:"required"`
 		Timeout time.Duration `yaml:"timeout" validate:"required"`
+		TLS     TLS           `yaml:"tls,omitempty"`
+	}
+
+	// TLS is a transport security configuration of gRPC connections.
+	TLS struct {
+		// Mode is the transport security mode, connections are in plain text if it is empty.
+		Mode string `yaml:"mode,omitempty" validate:"omitempty,oneof=disabled tls mtls pinned"`
+		// CertFile is the PEM certificate presented to the other side in tls and mtls modes.
+		CertFile string `yaml:"cert-file,omitempty" validate:"required_if=Mode mtls"`
+		// KeyFile is the PEM private key of the certificate.
+		KeyFile string `yaml:"key-file,omitempty" validate:"required_with=CertFile"`
+		// CAFile is the PEM bundle the certificates of the other side are verified with,
+		// system roots are used to verify servers if it is empty.
+		CAFile string `yaml:"ca-file,omitempty"`
+		// ServerName overrides the host name the server certificate is verified against.
+		ServerName string `yaml:"server-name,omitempty"`
+		// PeerDeviceID is the hex device id the dialed node is pinned to before its id is known,
+		// that is the cluster head of a node or the node of a client.
+		PeerDeviceID string `yaml:"peer-device-id,omitempty" validate:"omitempty,hexadecimal"`
 	}
 
 	// Scheduler is a scheduler configuration.
@@ -0,0 +1,449 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package node
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"sort"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/proto"
+
+	"authentication-chains/internal/cipher"
+	"authentication-chains/internal/storage"
+	"authentication-chains/internal/types"
+)
+
+// archiveVersion is the version of the node archive format.
+const archiveVersion = 1
+
+type (
+	// archiveWriter writes length-prefixed records of the archive and hashes the written bytes.
+	archiveWriter struct {
+		w       io.Writer
+		hash    hash.Hash
+		records uint64
+	}
+
+	// archiveReader reads length-prefixed records of the archive and hashes the consumed bytes.
+	archiveReader struct {
+		r    *bufio.Reader
+		hash hash.Hash
+	}
+)
+
+// Export writes the chain, the authentication tables, the peers and the public keys of the node to the archive.
+// The archive is a header record, peer, key, block and entry records and a trailer record with the checksum.
+func (n *Node) Export(ctx context.Context, w io.Writer) error {
+	ctx, logger := n.logger.StartTrace(ctx, "export")
+	defer logger.FinishTrace()
+
+	lastBlock := n.chain.GetLastBlock()
+	archive := newArchiveWriter(w)
+
+	if err := archive.write(&types.ArchiveRecord{Record: &types.ArchiveRecord_Header{Header: &types.ArchiveHeader{
+		Version:        archiveVersion,
+		NodeName:       n.cfg.Name,
+		Level:          n.getLevel(),
+		GenesisHash:    n.chain.GetGenesisHash(),
+		LastBlockIndex: lastBlock.Index,
+		CreatedAt:      time.Now().Unix(),
+	}}}); err != nil {
+		return err
+	}
+
+	// cluster head goes first, so the importing node knows its cluster before the other peers
+	var peers []*types.ArchivePeer
+
+	if clusterHead := n.getClusterHead(); clusterHead != nil {
+		peers = append(peers, &types.ArchivePeer{Bucket: types.BucketClusterHead, Peer: clusterHead.ToProto()})
+	}
+
+	if clusterNodes := n.getClusterNodes(); clusterNodes != nil {
+		for _, peer := range clusterNodes.GetAll() {
+			peers = append(peers, &types.ArchivePeer{Bucket: types.BucketClusterNodes, Peer: peer.ToProto()})
+		}
+	}
+
+	if childrenNodes := n.getChildrenNodes(); childrenNodes != nil {
+		for _, peer := range childrenNodes.GetAll() {
+			peers = append(peers, &types.ArchivePeer{Bucket: types.BucketChildrenNodes, Peer: peer.ToProto()})
+		}
+	}
+
+	for _, peer := range peers {
+		if err := archive.write(&types.ArchiveRecord{Record: &types.ArchiveRecord_Peer{Peer: peer}}); err != nil {
+			return err
+		}
+	}
+
+	// keys go before the blocks, so the importing node verifies the votes without asking the network
+	keys, err := n.getArchiveKeys()
+	if err != nil {
+		logger.Errorf("get public keys: %s", err)
+		return err
+	}
+
+	for _, key := range keys {
+		if err = archive.write(&types.ArchiveRecord{Record: &types.ArchiveRecord_Key{Key: key}}); err != nil {
+			return err
+		}
+	}
+
+	batchSize := uint64(n.cfg.Sync.BatchSize)
+
+	for from := uint64(1); from <= lastBlock.Index; from += batchSize {
+		blocks, err := n.chain.GetAllBlocks(from, min(from+batchSize-1, lastBlock.Index))
+		if err != nil {
+			logger.Errorf("get blocks from %d: %s", from, err)
+			return err
+		}
+
+		for _, block := range blocks {
+			if err = archive.write(&types.ArchiveRecord{Record: &types.ArchiveRecord_Block{Block: block}}); err != nil {
+				return err
+			}
+		}
+	}
+
+	table, err := n.getAuthenticationTable(ctx)
+	if err != nil {
+		logger.Errorf("get authentication table: %s", err)
+		return err
+	}
+
+	levels := make([]uint32, 0, len(table))
+	for level := range table {
+		levels = append(levels, level)
+	}
+
+	sort.Slice(levels, func(i, j int) bool { return levels[i] > levels[j] })
+
+	for _, level := range levels {
+		for _, entry := range table[level].Entries {
+			if err = archive.write(&types.ArchiveRecord{Record: &types.ArchiveRecord_Entry{Entry: &types.ArchiveEntry{
+				Level: level,
+				Entry: entry,
+			}}}); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err = archive.close(); err != nil {
+		return err
+	}
+
+	logger.Infof("exported %d blocks and %d records", lastBlock.Index, archive.records)
+
+	return nil
+}
+
+// Import restores the node from the archive into the empty storage. The archive is verified before anything is written.
+// Blocks are added through the same validation as synced ones, so the authentication table of the node level
+// is rebuilt from the blocks and only cross-checked against the archived one.
+func (n *Node) Import(ctx context.Context, r io.ReadSeeker) error {
+	ctx, logger := n.logger.StartTrace(ctx, "import")
+	defer logger.FinishTrace()
+
+	if n.chain.GetLastBlock().Index != 0 {
+		return fmt.Errorf("%w: chain is not empty", ErrInvalidArchive)
+	}
+
+	if err := n.db.View(func(tx storage.Tx) error {
+		return checkEmptyStorage(tx, n.getLevel())
+	}); err != nil {
+		logger.Errorf("check storage: %s", err)
+		return err
+	}
+
+	header, err := readArchive(r, nil)
+	if err != nil {
+		logger.Errorf("verify archive: %s", err)
+		return err
+	}
+
+	if level := n.getLevel(); header.Level != level {
+		return fmt.Errorf("%w: archive level %d differs from node level %d", ErrInvalidArchive, header.Level, level)
+	}
+
+	if _, err = r.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	if err = n.chain.SetGenesisHash(header.GenesisHash); err != nil {
+		logger.Errorf("set genesis hash: %s", err)
+		return err
+	}
+
+	if _, err = readArchive(r, func(record *types.ArchiveRecord) error {
+		switch record := record.Record.(type) {
+		case *types.ArchiveRecord_Peer:
+			return n.importPeer(ctx, record.Peer)
+		case *types.ArchiveRecord_Key:
+			return n.importKey(record.Key)
+		case *types.ArchiveRecord_Block:
+			return n.addBlock(ctx, record.Block)
+		case *types.ArchiveRecord_Entry:
+			return n.importEntry(ctx, record.Entry)
+		}
+
+		return nil
+	}); err != nil {
+		logger.Errorf("import archive: %s", err)
+		return err
+	}
+
+	logger.Infof("imported %d blocks from node %s archive", header.LastBlockIndex, header.NodeName)
+
+	return nil
+}
+
+// checkEmptyStorage checks that the storage keeps nothing the archive restores, so the imported node
+// doesn't mix its state with the archived one. Authentication tables of the level and below are checked.
+func checkEmptyStorage(tx storage.Tx, level uint32) error {
+	buckets := []string{
+		types.BucketClusterHead,
+		types.BucketClusterNodes,
+		types.BucketChildrenNodes,
+		types.BucketKeys,
+		types.BucketForks,
+	}
+
+	for i := int32(level); i >= 0; i-- {
+		buckets = append(buckets, bucketAuthTableLevel(uint32(i)))
+	}
+
+	for _, bucket := range buckets {
+		entries, err := tx.GetAll(bucket)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return err
+		}
+
+		if len(entries) != 0 {
+			return fmt.Errorf("%w: bucket %s is not empty", ErrInvalidArchive, bucket)
+		}
+	}
+
+	return nil
+}
+
+// importPeer adds the archived peer relative to the node, peers which are not related to the node are skipped.
+func (n *Node) importPeer(ctx context.Context, archived *types.ArchivePeer) error {
+	ctx, logger := n.logger.StartTrace(ctx, "import peer")
+	defer logger.FinishTrace()
+
+	if bytes.Equal(archived.Peer.DeviceId, n.deviceID) {
+		return nil
+	}
+
+	client, err := n.initClient(ctx, archived.Peer.GrpcAddress, archived.Peer.DeviceId)
+	if err != nil {
+		return err
+	}
+
+	peer := NewPeer(
+		archived.Peer.Name,
+		archived.Peer.DeviceId,
+		archived.Peer.ClusterHeadId,
+		archived.Peer.GrpcAddress,
+		archived.Peer.Level,
+		client,
+	)
+
+	if archived.Bucket == types.BucketClusterHead && n.getClusterHead() == nil {
+		n.setClusterHead(peer)
+	}
+
+	if err = n.addPeer(ctx, peer); err != nil {
+		logger.Debugw("skip peer", "name", peer.Name, "bucket", archived.Bucket)
+	}
+
+	return nil
+}
+
+// getArchiveKeys returns the public key of the node and the stored public keys of the other devices.
+func (n *Node) getArchiveKeys() ([]*types.ArchiveKey, error) {
+	keys := []*types.ArchiveKey{{
+		DeviceId:  n.deviceID,
+		PublicKey: cipher.SerializePublicKey(n.cipher.GetPublicKey()),
+	}}
+
+	if err := n.db.View(func(tx storage.Tx) error {
+		entries, err := tx.GetAll(types.BucketKeys)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			if !bytes.Equal(entry.Key, n.deviceID) {
+				keys = append(keys, &types.ArchiveKey{DeviceId: entry.Key, PublicKey: entry.Value})
+			}
+		}
+
+		return nil
+	}); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// importKey stores the archived public key, the key must be the key of the device id it's archived with.
+func (n *Node) importKey(archived *types.ArchiveKey) error {
+	publicKey, err := cipher.DeserializePublicKey(archived.PublicKey)
+	if err != nil {
+		return fmt.Errorf("%w: key of device %x: %s", ErrInvalidArchive, archived.DeviceId, err)
+	}
+
+	if !bytes.Equal(cipher.Fingerprint(publicKey), archived.DeviceId) {
+		return fmt.Errorf("%w: key of device %x: %s", ErrInvalidArchive, archived.DeviceId, cipher.ErrFingerprintMismatch)
+	}
+
+	return n.db.Update(func(tx storage.Tx) error {
+		return tx.Put(types.BucketKeys, archived.DeviceId, archived.PublicKey, types.InfinityTTL)
+	})
+}
+
+// importEntry writes the archived entry of the lower level or cross-checks the entry of the node level.
+func (n *Node) importEntry(ctx context.Context, archived *types.ArchiveEntry) error {
+	ctx, logger := n.logger.StartTrace(ctx, "import entry")
+	defer logger.FinishTrace()
+
+	if archived.Level != n.getLevel() {
+		return n.db.Update(func(tx storage.Tx) error {
+			return putEntry(tx, archived.Level, archived.Entry)
+		})
+	}
+
+	entry, err := n.getAuthenticationEntry(ctx, archived.Entry.DeviceId)
+	if err != nil {
+		return fmt.Errorf("%w: entry of device %x has no block", ErrInvalidArchive, archived.Entry.DeviceId)
+	}
+
+	if !proto.Equal(entry, archived.Entry) {
+		return fmt.Errorf("%w: entry of device %x differs from its blocks", ErrInvalidArchive, archived.Entry.DeviceId)
+	}
+
+	return nil
+}
+
+// readArchive reads the archive and passes its records except the trailer to the apply function.
+// It returns the archive header if the archive is complete and its checksum matches.
+func readArchive(r io.Reader, apply func(record *types.ArchiveRecord) error) (*types.ArchiveHeader, error) {
+	archive := &archiveReader{r: bufio.NewReader(r), hash: sha256.New()}
+
+	var (
+		header  *types.ArchiveHeader
+		records uint64
+	)
+
+	for {
+		checksum := archive.hash.Sum(nil)
+
+		var record types.ArchiveRecord
+		if err := protodelim.UnmarshalFrom(archive, &record); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("%w: trailer is missing", ErrInvalidArchive)
+			}
+
+			return nil, fmt.Errorf("%w: %s", ErrInvalidArchive, err)
+		}
+
+		switch value := record.Record.(type) {
+		case *types.ArchiveRecord_Header:
+			if header != nil {
+				return nil, fmt.Errorf("%w: unexpected header", ErrInvalidArchive)
+			}
+
+			if value.Header.Version != archiveVersion {
+				return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidArchive, value.Header.Version)
+			}
+
+			header = value.Header
+
+		case *types.ArchiveRecord_Trailer:
+			switch {
+			case value.Trailer.Records != records:
+				return nil, fmt.Errorf("%w: %d of %d records", ErrInvalidArchive, records, value.Trailer.Records)
+			case !bytes.Equal(value.Trailer.Checksum, checksum):
+				return nil, fmt.Errorf("%w: checksum mismatch", ErrInvalidArchive)
+			}
+
+			if _, err := archive.r.ReadByte(); !errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("%w: data after trailer", ErrInvalidArchive)
+			}
+
+			return header, nil
+
+		default:
+			if header == nil {
+				return nil, fmt.Errorf("%w: header is missing", ErrInvalidArchive)
+			}
+		}
+
+		records++
+
+		if apply != nil {
+			if err := apply(&record); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func newArchiveWriter(w io.Writer) *archiveWriter {
+	hash := sha256.New()
+
+	return &archiveWriter{
+		w:    io.MultiWriter(w, hash),
+		hash: hash,
+	}
+}
+
+// write writes the length-prefixed record.
+func (a *archiveWriter) write(record *types.ArchiveRecord) error {
+	if _, err := protodelim.MarshalTo(a.w, record); err != nil {
+		return err
+	}
+
+	a.records++
+
+	return nil
+}
+
+// close writes the trailer with the number of records and the checksum of the written bytes.
+func (a *archiveWriter) close() error {
+	_, err := protodelim.MarshalTo(a.w, &types.ArchiveRecord{Record: &types.ArchiveRecord_Trailer{Trailer: &types.ArchiveTrailer{
+		Records:  a.records,
+		Checksum: a.hash.Sum(nil),
+	}}})
+
+	return err
+}
+
+func (a *archiveReader) Read(p []byte) (int, error) {
+	n, err := a.r.Read(p)
+	a.hash.Write(p[:n])
+
+	return n, err
+}
+
+func (a *archiveReader) ReadByte() (byte, error) {
+	b, err := a.r.ReadByte()
+	if err == nil {
+		a.hash.Write([]byte{b})
+	}
+
+	return b, err
+}
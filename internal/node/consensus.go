@@ -0,0 +1,334 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package node
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"fmt"
+	"sync"
+
+	"authentication-chains/internal/cipher"
+	"authentication-chains/internal/config"
+	"authentication-chains/internal/types"
+)
+
+// collectVotes sends the block to the validators of the block and collects their signed votes.
+// The votes are attached to the block if the quorum is reached.
+func (n *Node) collectVotes(ctx context.Context, block *types.Block) error {
+	ctx, logger := n.logger.StartTrace(ctx, "collect votes")
+	defer logger.FinishTrace()
+
+	vote, err := n.signVote(block, true)
+	if err != nil {
+		return err
+	}
+
+	var voters []*Peer
+
+	for _, voter := range n.getVoters() {
+		if containsID(block.ValidatorIds, voter.DeviceID) {
+			voters = append(voters, voter)
+		}
+	}
+
+	var (
+		votes = []*types.BlockVote{vote}
+		mutex sync.Mutex
+		group = n.workerPool.Group()
+	)
+
+	for _, voter := range voters {
+		voter := voter
+
+		group.Submit(func() {
+			response, err := voter.Client.SendBlock(ctx, &types.BlockValidationRequest{Block: block})
+			if err != nil {
+				logger.Errorf("send block to node %s: %s", voter.Name, err)
+				return
+			}
+
+			publicKey, err := n.getPublicKey(ctx, voter.DeviceID)
+			if err != nil {
+				logger.Errorf("public key of node %s: %s", voter.Name, err)
+				return
+			}
+
+			if err = verifyVote(response.Vote, voter.DeviceID, block.Hash, publicKey); err != nil {
+				logger.Errorf("vote of node %s: %s", voter.Name, err)
+				return
+			}
+
+			if !response.Vote.IsValid {
+				logger.Errorf("validation by node %s: block %x is not valid", voter.Name, block.Hash)
+			}
+
+			mutex.Lock()
+			votes = append(votes, response.Vote)
+			mutex.Unlock()
+		})
+	}
+
+	group.Wait()
+
+	approvals := countApprovals(votes)
+	required := quorumSize(n.cfg.Consensus.Quorum, len(block.ValidatorIds))
+
+	// validators which didn't answer may approve the block next time, the ones which rejected it won't
+	if unanswered := len(block.ValidatorIds) - countVoters(votes); approvals+unanswered < required {
+		return fmt.Errorf("%w: %s: %d of %d approvals", ErrBlockValidation, ErrQuorumNotReached, approvals, required)
+	}
+
+	if approvals < required {
+		return fmt.Errorf("%w: %d of %d approvals", ErrQuorumNotReached, approvals, required)
+	}
+
+	block.Votes = votes
+
+	return nil
+}
+
+// commitBlock notifies the cluster validators that the block has reached the quorum.
+func (n *Node) commitBlock(ctx context.Context, block *types.Block) {
+	ctx, logger := n.logger.StartTrace(ctx, "commit block")
+	defer logger.FinishTrace()
+
+	group := n.workerPool.Group()
+
+	for _, voter := range n.getVoters() {
+		voter := voter
+
+		group.Submit(func() {
+			if _, err := voter.Client.CommitBlock(ctx, &types.BlockCommitRequest{Block: block}); err != nil {
+				logger.Errorf("commit block to node %s: %s", voter.Name, err)
+			}
+		})
+	}
+
+	group.Wait()
+}
+
+// verifyQuorum verifies that the block is approved by the quorum of the validators the block was mined by.
+// Cluster membership changes since, so history isn't checked against the current peers.
+func (n *Node) verifyQuorum(ctx context.Context, block *types.Block) error {
+	_, err := n.countQuorum(ctx, block, blockValidators(block))
+	return err
+}
+
+// blockValidators returns the validators of the block. Blocks mined before the validators were recorded
+// are counted among the validators which voted for them.
+func blockValidators(block *types.Block) [][]byte {
+	if len(block.ValidatorIds) != 0 {
+		return block.ValidatorIds
+	}
+
+	var validators [][]byte
+
+	for _, vote := range block.Votes {
+		if !containsID(validators, vote.ValidatorId) {
+			validators = append(validators, vote.ValidatorId)
+		}
+	}
+
+	return validators
+}
+
+// checkValidators checks that the validators of the new block are the given current validators,
+// so the validators which vote for the block vouch for its validators as well.
+func checkValidators(block *types.Block, voters [][]byte) error {
+	if len(block.ValidatorIds) != len(voters) {
+		return fmt.Errorf("%w: block has %d validators instead of %d", ErrBlockValidation, len(block.ValidatorIds), len(voters))
+	}
+
+	for _, id := range block.ValidatorIds {
+		if !containsID(voters, id) {
+			return fmt.Errorf("%w: unknown validator %x", ErrBlockValidation, id)
+		}
+	}
+
+	for _, id := range voters {
+		if !containsID(block.ValidatorIds, id) {
+			return fmt.Errorf("%w: validator %x is missing", ErrBlockValidation, id)
+		}
+	}
+
+	return nil
+}
+
+// countQuorum verifies the quorum of the block like verifyQuorum and returns the number of approvals of the validators.
+func (n *Node) countQuorum(ctx context.Context, block *types.Block, voters [][]byte) (uint64, error) {
+	if err := n.verifyVotes(ctx, block); err != nil {
+		return 0, err
+	}
+
+	return n.quorumApprovals(block, voters)
+}
+
+// quorumApprovals counts the approvals of the validators among the verified votes of the block,
+// it's an error if they don't reach the quorum.
+func (n *Node) quorumApprovals(block *types.Block, voters [][]byte) (uint64, error) {
+	var approved [][]byte
+
+	for _, vote := range block.Votes {
+		if !vote.IsValid || !containsID(voters, vote.ValidatorId) || containsID(approved, vote.ValidatorId) {
+			continue
+		}
+
+		approved = append(approved, vote.ValidatorId)
+	}
+
+	required := quorumSize(n.cfg.Consensus.Quorum, len(voters))
+	if len(approved) < required {
+		return 0, fmt.Errorf("%w: %s: %d of %d approvals", ErrBlockValidation, ErrQuorumNotReached, len(approved), required)
+	}
+
+	return uint64(len(approved)), nil
+}
+
+// signVote creates a signed vote of the node for the block.
+func (n *Node) signVote(block *types.Block, isValid bool) (*types.BlockVote, error) {
+	vote := &types.BlockVote{
+		ValidatorId: n.deviceID,
+		BlockHash:   block.Hash,
+		IsValid:     isValid,
+	}
+
+	if err := n.cipher.SignVote(vote); err != nil {
+		return nil, err
+	}
+
+	return vote, nil
+}
+
+// getVoters returns the cluster validators of the node's blocks except the node itself.
+func (n *Node) getVoters() []*Peer {
+	var voters []*Peer
+
+	if clusterNodes := n.getClusterNodes(); clusterNodes != nil {
+		voters = append(voters, clusterNodes.GetAll()...)
+	}
+
+	if clusterHead := n.getClusterHead(); clusterHead != nil {
+		voters = append(voters, clusterHead)
+	}
+
+	return voters
+}
+
+// getClusterVoterIDs returns device ids of the validators of the cluster node's blocks.
+func (n *Node) getClusterVoterIDs() [][]byte {
+	ids := [][]byte{n.deviceID}
+
+	for _, voter := range n.getVoters() {
+		ids = append(ids, voter.DeviceID)
+	}
+
+	return ids
+}
+
+// getChildrenVoterIDs returns device ids of the validators of the children node's blocks.
+func (n *Node) getChildrenVoterIDs() [][]byte {
+	ids := [][]byte{n.deviceID}
+
+	if childrenNodes := n.getChildrenNodes(); childrenNodes != nil {
+		for _, child := range childrenNodes.GetAll() {
+			ids = append(ids, child.DeviceID)
+		}
+	}
+
+	return ids
+}
+
+// verifyVotes verifies signatures of all votes attached to the block.
+func (n *Node) verifyVotes(ctx context.Context, block *types.Block) error {
+	return n.verifyBranchVotes(ctx, []*types.Block{block}, block)
+}
+
+// verifyBranchVotes verifies signatures of all votes attached to the block of the branch,
+// keys of the validators registered by the branch are taken from its blocks.
+func (n *Node) verifyBranchVotes(ctx context.Context, blocks []*types.Block, block *types.Block) error {
+	for _, vote := range block.Votes {
+		publicKey, err := n.getBranchPublicKey(ctx, blocks, vote.ValidatorId)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrBlockValidation, err)
+		}
+
+		if err = verifyVote(vote, vote.ValidatorId, block.Hash, publicKey); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// verifyVote verifies that the vote is signed by the validator for the block.
+func verifyVote(vote *types.BlockVote, validatorID, blockHash []byte, publicKey crypto.PublicKey) error {
+	if vote == nil {
+		return fmt.Errorf("%w: vote is missing", ErrBlockValidation)
+	}
+
+	if !bytes.Equal(vote.ValidatorId, validatorID) {
+		return fmt.Errorf("%w: vote is signed by unexpected validator", ErrBlockValidation)
+	}
+
+	if !bytes.Equal(vote.BlockHash, blockHash) {
+		return fmt.Errorf("%w: vote is for another block %x", ErrBlockValidation, vote.BlockHash)
+	}
+
+	if err := cipher.VerifyVote(vote, publicKey); err != nil {
+		return fmt.Errorf("%w: %s", ErrBlockValidation, err)
+	}
+
+	return nil
+}
+
+// countApprovals counts approving votes of distinct validators.
+func countApprovals(votes []*types.BlockVote) int {
+	var approved [][]byte
+
+	for _, vote := range votes {
+		if vote.IsValid && !containsID(approved, vote.ValidatorId) {
+			approved = append(approved, vote.ValidatorId)
+		}
+	}
+
+	return len(approved)
+}
+
+// countVoters returns the number of distinct validators of the votes.
+func countVoters(votes []*types.BlockVote) int {
+	var voters [][]byte
+
+	for _, vote := range votes {
+		if !containsID(voters, vote.ValidatorId) {
+			voters = append(voters, vote.ValidatorId)
+		}
+	}
+
+	return len(voters)
+}
+
+// quorumSize returns the number of approvals required by the rule for the given number of validators.
+func quorumSize(rule string, validators int) int {
+	switch rule {
+	case config.QuorumUnanimous:
+		return validators
+	case config.QuorumBFT:
+		return 2*validators/3 + 1
+	default:
+		return validators/2 + 1
+	}
+}
+
+func containsID(ids [][]byte, id []byte) bool {
+	for _, v := range ids {
+		if bytes.Equal(v, id) {
+			return true
+		}
+	}
+
+	return false
+}
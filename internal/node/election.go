@@ -0,0 +1,541 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package node
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+
+	"authentication-chains/internal/cipher"
+	"authentication-chains/internal/storage"
+	"authentication-chains/internal/types"
+)
+
+// electClusterHead elects the new cluster head among the alive cluster nodes once the cluster head fails.
+// The node with the highest authentication table wins and ties are broken by the lowest device id,
+// so every cluster node elects the same one. The winner takes over, the others wait for its announcement.
+func (n *Node) electClusterHead(ctx context.Context, failed *Peer) {
+	ctx, logger := n.logger.StartTrace(ctx, "elect cluster head")
+	logger = logger.WithFields("cluster_head", failed.Name)
+	defer logger.FinishTrace()
+
+	n.electionMutex.Lock()
+	defer n.electionMutex.Unlock()
+
+	// the cluster head is already replaced by an announcement
+	if n.getClusterHead() != failed {
+		return
+	}
+
+	winner := n.electionWinner(ctx)
+	if winner != nil {
+		logger.Infof("node %s is elected as cluster head, waiting for its announcement", winner.Name)
+		return
+	}
+
+	if err := n.takeOverClusterHead(ctx, failed); err != nil {
+		logger.Errorf("take over cluster head: %s", err)
+		return
+	}
+
+	logger.Infof("node took over the cluster head at level %d", n.getLevel())
+}
+
+// electionWinner returns the cluster node which outranks the others, it's nil if the node wins itself.
+// Cluster nodes which don't answer can't take over, so they don't take part.
+func (n *Node) electionWinner(ctx context.Context) *Peer {
+	ctx, logger := n.logger.StartTrace(ctx, "election winner")
+	defer logger.FinishTrace()
+
+	clusterNodes := n.getClusterNodes()
+	if clusterNodes == nil {
+		return nil
+	}
+
+	var (
+		winner   *Peer
+		height   = n.authenticationHeight(n.getLevel())
+		deviceID = n.deviceID
+	)
+
+	for _, peer := range clusterNodes.GetAll() {
+		statusCtx, cancel := context.WithTimeout(ctx, n.cfg.Heartbeat.Timeout)
+		status, err := peer.Client.GetStatus(statusCtx, &types.StatusRequest{})
+		cancel()
+
+		if err != nil {
+			logger.Debugw("cluster node doesn't take part in the election", "peer", peer.Name, "error", err)
+			continue
+		}
+
+		if outranks(status.Height, peer.DeviceID, height, deviceID) {
+			winner, height, deviceID = peer, status.Height, peer.DeviceID
+		}
+	}
+
+	return winner
+}
+
+// takeOverClusterHead promotes the node to the level of the failed cluster head. The cluster nodes become
+// children of the node and are informed by the announcement, then the node registers in the upper cluster
+// the failed cluster head belonged to. The node becomes the root if there is no upper cluster.
+// Either way the node starts a new chain of its level, the chain of the cluster stays with the children.
+func (n *Node) takeOverClusterHead(ctx context.Context, failed *Peer) error {
+	ctx, logger := n.logger.StartTrace(ctx, "take over cluster head")
+	defer logger.FinishTrace()
+
+	term, err := n.getTerm()
+	if err != nil {
+		return err
+	}
+
+	term++
+
+	// the height is taken before the promotion, so it's the height the cluster nodes compare with theirs
+	height := n.authenticationHeight(n.getLevel())
+	upper := n.getUpperClusterHead()
+
+	level := make([]byte, 4)
+	binary.BigEndian.PutUint32(level, failed.Level)
+
+	if err = n.db.Update(func(tx storage.Tx) error {
+		if err := tx.Put(types.BucketElection, types.KeyLevel, level, types.InfinityTTL); err != nil {
+			return err
+		}
+
+		// the children keep the chain they share, so the nodes joining them get its genesis hash
+		if err := tx.Put(types.BucketElection, types.KeyChildrenGenesisHash, n.chain.GetGenesisHash(), types.InfinityTTL); err != nil {
+			return err
+		}
+
+		err := tx.Delete(types.BucketClusterHead, types.KeyClusterHead)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return err
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// the chain of the cluster is the chain of the children now, the node keeps their entries
+	// and starts over the chain of the level it's promoted to
+	if err = n.chain.Reset(); err != nil {
+		return err
+	}
+
+	// the level and the peers are replaced at once, so the readers never see the node half promoted
+	n.peersMutex.Lock()
+
+	var siblings []*Peer
+	if n.clusterNodes != nil {
+		siblings = n.clusterNodes.GetAll()
+	}
+
+	n.cfg.Level = failed.Level
+	n.clusterHead = nil
+	n.clusterNodes = nil
+
+	n.peersMutex.Unlock()
+
+	if err = failed.Close(); err != nil {
+		logger.Errorf("close connection to cluster head %s: %s", failed.Name, err)
+	}
+
+	for _, sibling := range siblings {
+		child := *sibling
+		child.ClusterHeadID = n.deviceID
+
+		if err = n.db.Update(func(tx storage.Tx) error {
+			err := tx.Delete(types.BucketClusterNodes, sibling.DeviceID)
+			if err != nil && !errors.Is(err, storage.ErrNotFound) {
+				return err
+			}
+
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		if err = n.addPeer(ctx, &child); err != nil {
+			return err
+		}
+	}
+
+	self := &types.Peer{
+		Name:        n.cfg.Name,
+		Level:       failed.Level,
+		DeviceId:    n.deviceID,
+		GrpcAddress: n.cfg.GRPC.Address,
+	}
+
+	if upper != nil {
+		self.ClusterHeadId = upper.DeviceId
+	}
+
+	announcement := &types.ClusterHeadAnnouncement{
+		ClusterHead:           self,
+		PreviousClusterHeadId: failed.DeviceID,
+		Term:                  term,
+		Height:                height,
+	}
+
+	if err = n.cipher.SignClusterHeadAnnouncement(announcement); err != nil {
+		return err
+	}
+
+	if err = n.putTerm(term); err != nil {
+		return err
+	}
+
+	n.announceClusterHead(ctx, announcement)
+
+	if upper == nil {
+		logger.Infof("cluster head %s had no upper cluster, node is the root now", failed.Name)
+		return n.initMaster(ctx)
+	}
+
+	client, err := n.initClient(ctx, upper.GrpcAddress, upper.DeviceId)
+	if err != nil {
+		return err
+	}
+
+	clusterHead := NewPeer(upper.Name, upper.DeviceId, upper.ClusterHeadId, upper.GrpcAddress, upper.Level, client)
+	n.setClusterHead(clusterHead)
+
+	if err = n.addPeer(ctx, clusterHead); err != nil {
+		return err
+	}
+
+	return n.initPeers(ctx)
+}
+
+// announceClusterHead sends the announcement of the node to its children.
+func (n *Node) announceClusterHead(ctx context.Context, announcement *types.ClusterHeadAnnouncement) {
+	ctx, logger := n.logger.StartTrace(ctx, "announce cluster head")
+	defer logger.FinishTrace()
+
+	childrenNodes := n.getChildrenNodes()
+	if childrenNodes == nil {
+		return
+	}
+
+	group := n.workerPool.Group()
+
+	for _, child := range childrenNodes.GetAll() {
+		child := child
+
+		group.Submit(func() {
+			if _, err := child.Client.AnnounceClusterHead(ctx, announcement); err != nil {
+				logger.Errorf("announce cluster head to node %s: %s", child.Name, err)
+			}
+		})
+	}
+
+	group.Wait()
+}
+
+// acceptClusterHead replaces the failed cluster head by the cluster node which announced the take over.
+func (n *Node) acceptClusterHead(ctx context.Context, announcement *types.ClusterHeadAnnouncement) error {
+	ctx, logger := n.logger.StartTrace(ctx, "accept cluster head")
+	defer logger.FinishTrace()
+
+	n.electionMutex.Lock()
+	defer n.electionMutex.Unlock()
+
+	candidate, err := n.verifyAnnouncement(ctx, announcement)
+	if err != nil {
+		return err
+	}
+
+	failed := n.getClusterHead()
+
+	head := *candidate
+	head.ClusterHeadID = announcement.ClusterHead.ClusterHeadId
+	head.Level = announcement.ClusterHead.Level
+
+	if err = n.db.Update(func(tx storage.Tx) error {
+		err := tx.Delete(types.BucketClusterNodes, candidate.DeviceID)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return err
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	n.getClusterNodes().Remove(candidate.DeviceID)
+
+	if err = n.switchClusterHead(ctx, &head); err != nil {
+		return err
+	}
+
+	if err = n.putTerm(announcement.Term); err != nil {
+		return err
+	}
+
+	logger.Infof("node %s replaced cluster head %s in term %d", head.Name, failed.Name, announcement.Term)
+
+	return nil
+}
+
+// switchClusterHead replaces the cluster head of the node and of its cluster nodes by the given one
+// and closes the connection to the previous cluster head.
+func (n *Node) switchClusterHead(ctx context.Context, head *Peer) error {
+	ctx, logger := n.logger.StartTrace(ctx, "switch cluster head")
+	defer logger.FinishTrace()
+
+	previous := n.getClusterHead()
+
+	// the cluster head of the new cluster head is learned by heartbeats
+	if err := n.db.Update(func(tx storage.Tx) error {
+		err := tx.Delete(types.BucketElection, types.KeyUpperClusterHead)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return err
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if err := n.putPeer(types.BucketClusterHead, types.KeyClusterHead, head); err != nil {
+		return err
+	}
+
+	n.peersMutex.Lock()
+	n.upperClusterHead = nil
+	n.clusterHead = head
+	clusterNodes := n.clusterNodes
+	n.peersMutex.Unlock()
+
+	if clusterNodes != nil {
+		for _, peer := range clusterNodes.GetAll() {
+			updated := *peer
+			updated.ClusterHeadID = head.DeviceID
+
+			if err := n.putPeer(types.BucketClusterNodes, updated.DeviceID, &updated); err != nil {
+				return err
+			}
+
+			clusterNodes.Add(&updated)
+		}
+	}
+
+	if previous != nil {
+		if err := previous.Close(); err != nil {
+			logger.Errorf("close connection to cluster head %s: %s", previous.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// verifyAnnouncement verifies that the announcement is signed by a cluster node which outranks the node
+// for a newer term, and that the cluster head it replaces is failed indeed. The claimed height is checked
+// against the status of the announcer. It returns the announcing cluster node.
+func (n *Node) verifyAnnouncement(ctx context.Context, announcement *types.ClusterHeadAnnouncement) (*Peer, error) {
+	if announcement.ClusterHead == nil {
+		return nil, fmt.Errorf("%w: cluster head is missing", ErrInvalidAnnouncement)
+	}
+
+	term, err := n.getTerm()
+	if err != nil {
+		return nil, err
+	}
+
+	clusterHead := n.getClusterHead()
+
+	var candidate *Peer
+	if clusterNodes := n.getClusterNodes(); clusterNodes != nil {
+		candidate = clusterNodes.Get(announcement.ClusterHead.DeviceId)
+	}
+
+	switch {
+	case announcement.Term <= term:
+		return nil, fmt.Errorf("%w: term %d isn't newer than %d", ErrInvalidAnnouncement, announcement.Term, term)
+	case clusterHead == nil || !n.sameDevice(announcement.PreviousClusterHeadId, clusterHead.DeviceID):
+		return nil, fmt.Errorf("%w: previous cluster head isn't the cluster head of the node", ErrInvalidAnnouncement)
+	case candidate == nil:
+		return nil, fmt.Errorf("%w: device %x isn't a cluster node", ErrInvalidAnnouncement, announcement.ClusterHead.DeviceId)
+	case announcement.ClusterHead.Level != clusterHead.Level:
+		return nil, fmt.Errorf("%w: level %d isn't the cluster head level", ErrInvalidAnnouncement, announcement.ClusterHead.Level)
+	case outranks(n.authenticationHeight(n.getLevel()), n.deviceID, announcement.Height, candidate.DeviceID):
+		return nil, fmt.Errorf("%w: node outranks the announcer", ErrInvalidAnnouncement)
+	}
+
+	statusCtx, cancel := context.WithTimeout(ctx, n.cfg.Heartbeat.Timeout)
+	defer cancel()
+
+	if _, err = clusterHead.Client.GetStatus(statusCtx, &types.StatusRequest{}); err == nil {
+		return nil, fmt.Errorf("%w: cluster head is alive", ErrInvalidAnnouncement)
+	}
+
+	publicKey, err := n.getPublicKey(ctx, candidate.DeviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = cipher.VerifyClusterHeadAnnouncement(announcement, publicKey); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAnnouncement, err)
+	}
+
+	announcerCtx, cancelAnnouncer := context.WithTimeout(ctx, n.cfg.Heartbeat.Timeout)
+	defer cancelAnnouncer()
+
+	// the announcer is promoted already, so the table it claims the height of is the table of its children
+	status, err := candidate.Client.GetStatus(announcerCtx, &types.StatusRequest{})
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("%w: status of the announcer: %s", ErrInvalidAnnouncement, err)
+	case status.GetPeer().GetLevel() != announcement.ClusterHead.Level:
+		return nil, fmt.Errorf("%w: announcer is at level %d", ErrInvalidAnnouncement, status.GetPeer().GetLevel())
+	case status.ChildrenHeight < announcement.Height:
+		return nil, fmt.Errorf("%w: announcer has %d entries, not %d", ErrInvalidAnnouncement, status.ChildrenHeight, announcement.Height)
+	}
+
+	return candidate, nil
+}
+
+// setUpperClusterHead keeps the cluster head of the cluster head, the node registers in its cluster
+// if it takes over the cluster head.
+func (n *Node) setUpperClusterHead(peer *types.Peer) {
+	if peer == nil || proto.Equal(peer, n.getUpperClusterHead()) {
+		return
+	}
+
+	data, err := proto.Marshal(peer)
+	if err != nil {
+		return
+	}
+
+	if err = n.db.Update(func(tx storage.Tx) error {
+		return tx.Put(types.BucketElection, types.KeyUpperClusterHead, data, types.InfinityTTL)
+	}); err != nil {
+		return
+	}
+
+	n.peersMutex.Lock()
+	n.upperClusterHead = peer
+	n.peersMutex.Unlock()
+}
+
+// getUpperClusterHead returns the cluster head of the cluster head, it's nil if it isn't known.
+func (n *Node) getUpperClusterHead() *types.Peer {
+	n.peersMutex.RLock()
+	defer n.peersMutex.RUnlock()
+
+	return n.upperClusterHead
+}
+
+// authenticationHeight returns the number of entries in the authentication table of the level.
+func (n *Node) authenticationHeight(level uint32) uint64 {
+	var height uint64
+
+	_ = n.db.View(func(tx storage.Tx) error {
+		entries, err := tx.GetAll(bucketAuthTableLevel(level))
+		if err != nil {
+			return err
+		}
+
+		height = uint64(len(entries))
+
+		return nil
+	})
+
+	return height
+}
+
+// childrenGenesisHash returns the genesis hash of the chain of the children. It's the authentication block
+// of the node, unless the node took over the cluster head and its children keep the chain they already share.
+// Renewals replace the authentication block, so the genesis hash is kept once it's handed out.
+func (n *Node) childrenGenesisHash() []byte {
+	var hash []byte
+
+	_ = n.db.View(func(tx storage.Tx) error {
+		var err error
+		hash, err = tx.Get(types.BucketElection, types.KeyChildrenGenesisHash)
+
+		return err
+	})
+
+	if len(hash) != 0 {
+		return hash
+	}
+
+	if hash = n.getAuthBlockHash(); len(hash) != 0 {
+		_ = n.db.Update(func(tx storage.Tx) error {
+			return tx.Put(types.BucketElection, types.KeyChildrenGenesisHash, hash, types.InfinityTTL)
+		})
+	}
+
+	return hash
+}
+
+// getTerm returns the term of the last cluster head election the node took part in.
+func (n *Node) getTerm() (uint64, error) {
+	var term uint64
+
+	if err := n.db.View(func(tx storage.Tx) error {
+		data, err := tx.Get(types.BucketElection, types.KeyTerm)
+		if err != nil {
+			return err
+		}
+
+		term = binary.BigEndian.Uint64(data)
+
+		return nil
+	}); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return 0, err
+	}
+
+	return term, nil
+}
+
+// putTerm stores the term of the cluster head election.
+func (n *Node) putTerm(term uint64) error {
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, term)
+
+	return n.db.Update(func(tx storage.Tx) error {
+		return tx.Put(types.BucketElection, types.KeyTerm, data, types.InfinityTTL)
+	})
+}
+
+// loadElection loads the level the node is promoted to and the cluster head of its cluster head.
+func loadElection(db storage.Storage) (level *uint32, upper *types.Peer, err error) {
+	err = db.View(func(tx storage.Tx) error {
+		if data, err := tx.Get(types.BucketElection, types.KeyLevel); err == nil && len(data) == 4 {
+			promoted := binary.BigEndian.Uint32(data)
+			level = &promoted
+		}
+
+		data, err := tx.Get(types.BucketElection, types.KeyUpperClusterHead)
+		if err != nil {
+			return nil
+		}
+
+		upper = new(types.Peer)
+
+		return proto.Unmarshal(data, upper)
+	})
+
+	return level, upper, err
+}
+
+// outranks reports whether the first candidate of the election outranks the second one.
+func outranks(height uint64, deviceID []byte, otherHeight uint64, otherDeviceID []byte) bool {
+	if height != otherHeight {
+		return height > otherHeight
+	}
+
+	return bytes.Compare(deviceID, otherDeviceID) < 0
+}
@@ -12,7 +12,37 @@ var (
 	ErrBlockValidation        = errors.New("block validation failed")
 	ErrVerification           = errors.New("verification failed")
 	ErrInvalidDAR             = errors.New("invalid device authentication request")
-	ErrBlockHasNoDAR          = errors.New("block has no device authentication request")
+	ErrEmptyBlock             = errors.New("block has no transactions")
 	ErrInvalidMessageReceiver = errors.New("invalid message receiver")
+	ErrInvalidMessageSender   = errors.New("invalid message sender")
 	ErrNotFoundBlock          = errors.New("block not found")
+	ErrNotFoundTicket         = errors.New("ticket not found")
+	ErrNotFoundDevice         = errors.New("device not found")
+	ErrDeviceRevoked          = errors.New("device is revoked")
+	ErrInvalidRevocation      = errors.New("invalid device revocation request")
+	ErrInvalidRenewal         = errors.New("invalid device renewal request")
+	ErrInvalidValidity        = errors.New("invalid validity period")
+	ErrAuthenticationExpired  = errors.New("authentication is expired")
+	ErrQuorumNotReached       = errors.New("quorum is not reached")
+	ErrInvalidArchive         = errors.New("invalid archive")
+	ErrNotFoundKey            = errors.New("public key not found")
+	ErrInvalidRotation        = errors.New("invalid device key rotation request")
+	ErrKeyRotated             = errors.New("device key is rotated")
+	ErrUnknownPeer            = errors.New("unknown peer")
+	ErrPeerIdentity           = errors.New("peer certificate doesn't match its device id")
+	ErrInvalidRegistration    = errors.New("invalid node registration request")
+	ErrRegistrationDenied     = errors.New("node is neither authenticated nor approved by a join token")
+	ErrInvalidContent         = errors.New("invalid message content")
+	ErrStaleRequest           = errors.New("request is stale")
+	ErrReplayedRequest        = errors.New("request is replayed")
+	ErrReplayCacheFull        = errors.New("too many requests of the sender")
+	ErrUnreachableReceiver    = errors.New("message receiver is unreachable")
+	ErrHopLimit               = errors.New("message exceeds the hop limit")
+	ErrMailboxFull            = errors.New("mailbox of the receiver is full")
+	ErrInvalidMailboxRequest  = errors.New("invalid mailbox request")
+	ErrTooManyIssued          = errors.New("too many unexpired nonces or challenges of the device")
+	ErrInvalidAnnouncement    = errors.New("invalid cluster head announcement")
+	ErrInvalidDeregistration  = errors.New("invalid node deregistration request")
+	ErrNoChildrenLevel        = errors.New("node of level 0 has no children")
+	ErrTopologyCycle          = errors.New("topology request went through the node already")
 )
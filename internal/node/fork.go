@@ -0,0 +1,533 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package node
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+
+	"authentication-chains/internal/blockchain"
+	"authentication-chains/internal/cipher"
+	"authentication-chains/internal/storage"
+	"authentication-chains/internal/types"
+)
+
+// reorganize switches the chain to the winning competing branch if there is one.
+// Authentication table is rolled back to the fork point and the winning branch is replayed on top of it
+// in the transaction which switches the chain, so a failure leaves both of them untouched.
+func (n *Node) reorganize(ctx context.Context) error {
+	ctx, logger := n.logger.StartTrace(ctx, "reorganize")
+	defer logger.FinishTrace()
+
+	n.reorgMutex.Lock()
+	defer n.reorgMutex.Unlock()
+
+	// fork choice rescans every competing branch, so the ones which can't win anymore are dropped first
+	if err := n.chain.PruneForks(n.cfg.Consensus.FinalityDepth); err != nil {
+		logger.Errorf("prune forks: %s", err)
+		return err
+	}
+
+	// branches are ranked by the validators their blocks were mined by, the membership may have changed since
+	reorg, err := n.chain.ForkChoice(func(block *types.Block) (uint64, error) {
+		return n.countQuorum(ctx, block, blockValidators(block))
+	})
+	if err != nil {
+		logger.Errorf("fork choice: %s", err)
+		return err
+	}
+
+	if reorg == nil {
+		logger.Debug("current branch wins")
+		return nil
+	}
+
+	logger.Infof("reorganize chain: %d blocks removed, %d blocks added", len(reorg.Removed), len(reorg.Added))
+
+	level := n.getLevel()
+
+	previous, err := n.getRenewedBlocks(reorg.Removed)
+	if err != nil {
+		logger.Errorf("get renewed blocks: %s", err)
+		return err
+	}
+
+	// signatures don't depend on authentication table, so they are verified before the chain is locked
+	if invalid, err := n.verifyBranch(ctx, reorg.Added); err != nil {
+		logger.Errorf("verify block %x: %s", reorg.Added[invalid].Hash, err)
+		return n.abortReorganization(ctx, reorg, invalid, err)
+	}
+
+	invalid := len(reorg.Added)
+
+	if err = n.chain.Reorganize(reorg, func(tx storage.Tx) error {
+		for _, block := range reorg.Removed {
+			if err := revertAuthenticationEntry(tx, block, level, previous); err != nil {
+				return fmt.Errorf("revert block %x: %w", block.Hash, err)
+			}
+		}
+
+		for i, block := range reorg.Added {
+			if err := checkBlockState(tx, block, level); err != nil {
+				invalid = i
+				return fmt.Errorf("replay block %x: %w", block.Hash, err)
+			}
+
+			if err := putAuthenticationEntry(tx, block, level); err != nil {
+				return fmt.Errorf("replay block %x: %w", block.Hash, err)
+			}
+		}
+
+		return nil
+	}); err != nil {
+		logger.Errorf("reorganize chain: %s", err)
+		return n.abortReorganization(ctx, reorg, invalid, err)
+	}
+
+	n.restoreMemPool(ctx, reorg)
+
+	if txs := n.abandonedTransactions(reorg); !txs.IsEmpty() {
+		go n.remineTransactions(context.WithoutCancel(ctx), txs)
+	}
+
+	return nil
+}
+
+// abortReorganization discards the blocks of the winning branch starting from the invalid one,
+// the chain and authentication table are kept as they are.
+func (n *Node) abortReorganization(ctx context.Context, reorg *blockchain.Reorganization, invalid int, cause error) error {
+	ctx, logger := n.logger.StartTrace(ctx, "abort reorganization")
+	defer logger.FinishTrace()
+
+	if invalid < len(reorg.Added) {
+		if err := n.chain.DiscardBranch(reorg.Added[invalid:]); err != nil {
+			logger.Errorf("discard branch: %s", err)
+			return err
+		}
+	}
+
+	return fmt.Errorf("%w: %s", ErrBlockValidation, cause)
+}
+
+// verifyBranch verifies the blocks of the branch and the signatures of their transactions,
+// keys of the devices registered by the branch are taken from its blocks. It returns the index of the invalid block.
+func (n *Node) verifyBranch(ctx context.Context, blocks []*types.Block) (int, error) {
+	for i, block := range blocks {
+		if err := n.verifyBranchBlock(ctx, blocks, block); err != nil {
+			return i, err
+		}
+	}
+
+	return 0, nil
+}
+
+// verifyBranchBlock verifies the block of the branch and the signatures of its transactions.
+func (n *Node) verifyBranchBlock(ctx context.Context, blocks []*types.Block, block *types.Block) error {
+	if err := n.verifyBlock(block); err != nil {
+		return err
+	}
+
+	for _, revocation := range block.Revocations {
+		publicKey, err := n.getBranchPublicKey(ctx, blocks, revocation.SignerId)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidRevocation, err)
+		}
+
+		if err = cipher.VerifyRevocation(revocation, publicKey); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidRevocation, err)
+		}
+	}
+
+	for _, renewal := range block.Renewals {
+		if err := n.verifyValidity(renewal.Validity); err != nil {
+			return err
+		}
+
+		publicKey, err := n.getBranchPublicKey(ctx, blocks, renewal.DeviceId)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidRenewal, err)
+		}
+
+		if err = cipher.VerifyRenewal(renewal, publicKey); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidRenewal, err)
+		}
+	}
+
+	for _, rotation := range block.Rotations {
+		if err := n.verifyValidity(rotation.Validity); err != nil {
+			return err
+		}
+
+		publicKey, err := n.getBranchPublicKey(ctx, blocks, rotation.DeviceId)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidRotation, err)
+		}
+
+		if err = cipher.VerifyRotation(rotation, publicKey); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidRotation, err)
+		}
+	}
+
+	return nil
+}
+
+// checkBlockState checks the transactions of the block against authentication table of the level in the transaction,
+// the same way validateBlock does apart from the signatures.
+func checkBlockState(tx storage.Tx, block *types.Block, level uint32) error {
+	sameDevice := func(deviceID, otherID []byte) bool {
+		return sameDeviceTx(tx, deviceID, otherID)
+	}
+
+	for _, dar := range block.Dars {
+		if _, err := getEntry(tx, level, cipher.DeviceID(dar.DeviceId)); err == nil {
+			return fmt.Errorf("%w: device %x is already registered", ErrInvalidDAR, dar.DeviceId)
+		}
+	}
+
+	for _, revocation := range block.Revocations {
+		entry, err := getEntry(tx, level, cipher.DeviceID(revocation.DeviceId))
+		if err != nil {
+			return err
+		}
+
+		if err = checkRevocation(revocation, entry, sameDevice); err != nil {
+			return err
+		}
+	}
+
+	for _, renewal := range block.Renewals {
+		entry, err := getEntry(tx, level, cipher.DeviceID(renewal.DeviceId))
+		if err != nil {
+			return err
+		}
+
+		if err = checkRenewal(renewal, entry, sameDevice); err != nil {
+			return err
+		}
+	}
+
+	for _, rotation := range block.Rotations {
+		entry, err := getEntry(tx, level, cipher.DeviceID(rotation.DeviceId))
+		if err != nil {
+			return err
+		}
+
+		if err = checkRotation(rotation, entry, sameDevice); err != nil {
+			return err
+		}
+
+		if _, err = getEntry(tx, level, rotation.NewDeviceId); err == nil {
+			return fmt.Errorf("%w: new key is already registered", ErrInvalidRotation)
+		}
+	}
+
+	return nil
+}
+
+// getRenewedBlocks returns the blocks the renewals of the blocks point at by hash,
+// the renewals are reverted to the state of these blocks.
+func (n *Node) getRenewedBlocks(blocks []*types.Block) (map[string]*types.Block, error) {
+	previous := make(map[string]*types.Block)
+
+	for _, block := range blocks {
+		for _, renewal := range block.Renewals {
+			prevBlock, err := n.chain.GetBlockByHash(renewal.PrevBlockHash)
+			if err != nil {
+				return nil, fmt.Errorf("revert renewal of device %x: %w", renewal.DeviceId, err)
+			}
+
+			previous[string(renewal.PrevBlockHash)] = prevBlock
+		}
+	}
+
+	return previous, nil
+}
+
+// revertAuthenticationEntry reverts changes of the block in authentication table in the transaction,
+// previous are the blocks the renewals of the block point at by hash.
+func revertAuthenticationEntry(tx storage.Tx, block *types.Block, level uint32, previous map[string]*types.Block) error {
+	for _, rotation := range block.Rotations {
+		if entry, err := getEntry(tx, level, rotation.NewDeviceId); err == nil && bytes.Equal(entry.BlockHash, block.Hash) {
+			if err = tx.Delete(bucketAuthTableLevel(level), entry.DeviceId); err != nil {
+				return err
+			}
+		}
+
+		entry, err := getEntry(tx, level, cipher.DeviceID(rotation.DeviceId))
+		if err != nil {
+			return err
+		}
+
+		if !bytes.Equal(entry.RotationBlockHash, block.Hash) {
+			continue
+		}
+
+		entry.RotatedTo = nil
+		entry.RotationBlockHash = nil
+
+		if err = putEntry(tx, level, entry); err != nil {
+			return err
+		}
+
+		if err = tx.Delete(types.BucketRotations, entry.DeviceId); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return err
+		}
+	}
+
+	for _, renewal := range block.Renewals {
+		entry, err := getEntry(tx, level, cipher.DeviceID(renewal.DeviceId))
+		if err != nil {
+			return err
+		}
+
+		if !bytes.Equal(entry.BlockHash, block.Hash) {
+			continue
+		}
+
+		prevBlock := previous[string(renewal.PrevBlockHash)]
+		clusterHeadID, validity := deviceAuthentication(prevBlock, entry.DeviceId)
+
+		entry.ClusterHeadId = clusterHeadID
+		entry.BlockHash = prevBlock.Hash
+		entry.BlockIndex = prevBlock.Index
+		entry.NotAfter = prevBlock.NotAfter(validity)
+
+		if err = putEntry(tx, level, entry); err != nil {
+			return err
+		}
+	}
+
+	for _, revocation := range block.Revocations {
+		entry, err := getEntry(tx, level, cipher.DeviceID(revocation.DeviceId))
+		if err != nil {
+			return err
+		}
+
+		if !bytes.Equal(entry.RevocationBlockHash, block.Hash) {
+			continue
+		}
+
+		entry.Revoked = false
+		entry.RevocationBlockHash = nil
+
+		if err = putEntry(tx, level, entry); err != nil {
+			return err
+		}
+	}
+
+	// a dar registers a device which had no entry, so reverting it removes the entry
+	for _, dar := range block.Dars {
+		entry, err := getEntry(tx, level, cipher.DeviceID(dar.DeviceId))
+		if err != nil {
+			continue
+		}
+
+		if !bytes.Equal(entry.BlockHash, block.Hash) {
+			continue
+		}
+
+		if err = tx.Delete(bucketAuthTableLevel(level), entry.DeviceId); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// restoreMemPool returns device authentication requests of the abandoned branch
+// that are not included in the winning one back to the mem-pool.
+func (n *Node) restoreMemPool(ctx context.Context, reorg *blockchain.Reorganization) {
+	ctx, logger := n.logger.StartTrace(ctx, "restore mem-pool")
+	defer logger.FinishTrace()
+
+	included := make(map[string]bool)
+
+	for _, block := range reorg.Added {
+		for _, dar := range block.Dars {
+			included[string(dar.DeviceId)] = true
+		}
+	}
+
+	for _, block := range reorg.Removed {
+		for _, dar := range block.Dars {
+			if included[string(dar.DeviceId)] || n.memPool.Add(dar) != nil {
+				continue
+			}
+
+			if err := n.putTicket(ctx, &types.DeviceAuthenticationResponse{
+				Ticket: darTicket(dar),
+				Status: types.DARStatus_DAR_STATUS_PENDING,
+			}); err != nil {
+				logger.Errorf("put ticket: %s", err)
+			}
+		}
+	}
+}
+
+// abandonedTransactions returns the revocations, renewals and rotations of the removed blocks mined by the node
+// which the winning branch doesn't include. They were accepted already, so the node mines them once again.
+func (n *Node) abandonedTransactions(reorg *blockchain.Reorganization) types.Transactions {
+	included := make(map[string]bool)
+
+	for _, block := range reorg.Added {
+		for _, revocation := range block.Revocations {
+			included[string(revocation.Signature)] = true
+		}
+
+		for _, renewal := range block.Renewals {
+			included[string(renewal.Signature)] = true
+		}
+
+		for _, rotation := range block.Rotations {
+			included[string(rotation.Signature)] = true
+		}
+	}
+
+	var txs types.Transactions
+
+	for _, block := range reorg.Removed {
+		// every node of the cluster removes the block, only its miner, the first voter, mines it again
+		if len(block.Votes) == 0 || !bytes.Equal(block.Votes[0].ValidatorId, n.deviceID) {
+			continue
+		}
+
+		for _, revocation := range block.Revocations {
+			if !included[string(revocation.Signature)] {
+				txs.Revocations = append(txs.Revocations, revocation)
+			}
+		}
+
+		for _, renewal := range block.Renewals {
+			if !included[string(renewal.Signature)] {
+				txs.Renewals = append(txs.Renewals, renewal)
+			}
+		}
+
+		for _, rotation := range block.Rotations {
+			if !included[string(rotation.Signature)] {
+				txs.Rotations = append(txs.Rotations, rotation)
+			}
+		}
+	}
+
+	return txs
+}
+
+// remineTransactions verifies the abandoned transactions against the new branch and mines each of them alone,
+// the ones which are not valid anymore are dropped.
+func (n *Node) remineTransactions(ctx context.Context, txs types.Transactions) {
+	ctx, logger := n.logger.StartTrace(ctx, "remine transactions")
+	defer logger.FinishTrace()
+
+	level := n.getLevel()
+
+	for _, revocation := range txs.Revocations {
+		if err := n.verifyRevocation(ctx, revocation, level); err != nil {
+			logger.Errorf("drop revocation of device %x: %s", revocation.DeviceId, err)
+			continue
+		}
+
+		if _, err := n.mineBlock(ctx, types.Transactions{Revocations: []*types.DeviceRevocationRequest{revocation}}); err != nil {
+			logger.Errorf("mine revocation of device %x: %s", revocation.DeviceId, err)
+		}
+	}
+
+	for _, renewal := range txs.Renewals {
+		if err := n.verifyRenewal(ctx, renewal, level); err != nil {
+			logger.Errorf("drop renewal of device %x: %s", renewal.DeviceId, err)
+			continue
+		}
+
+		if _, err := n.mineBlock(ctx, types.Transactions{Renewals: []*types.DeviceRenewalRequest{renewal}}); err != nil {
+			logger.Errorf("mine renewal of device %x: %s", renewal.DeviceId, err)
+		}
+	}
+
+	for _, rotation := range txs.Rotations {
+		if err := n.verifyRotation(ctx, rotation, level); err != nil {
+			logger.Errorf("drop rotation of device %x: %s", rotation.DeviceId, err)
+			continue
+		}
+
+		if _, err := n.mineBlock(ctx, types.Transactions{Rotations: []*types.DeviceKeyRotationRequest{rotation}}); err != nil {
+			logger.Errorf("mine rotation of device %x: %s", rotation.DeviceId, err)
+		}
+	}
+}
+
+// findForkPoint returns the index of the last block shared with the peer.
+func (n *Node) findForkPoint(ctx context.Context, peer *Peer, index uint64) (uint64, error) {
+	ctx, logger := n.logger.StartTrace(ctx, "find fork point with node "+peer.Name)
+	defer logger.FinishTrace()
+
+	for ; index > 0; index-- {
+		block, err := n.chain.GetBlock(index)
+		if err != nil {
+			return 0, err
+		}
+
+		response, err := peer.Client.GetBlock(ctx, &types.BlockRequest{Index: index})
+		if err != nil {
+			logger.Errorf("get block %d from node %s: %s", index, peer.Name, err)
+			return 0, err
+		}
+
+		if bytes.Equal(block.Hash, response.Block.Hash) {
+			return index, nil
+		}
+	}
+
+	return 0, nil
+}
+
+// deviceAuthentication returns the cluster head and the validity period requested by the device in the block.
+func deviceAuthentication(block *types.Block, deviceID []byte) ([]byte, uint64) {
+	for _, dar := range block.Dars {
+		if bytes.Equal(cipher.DeviceID(dar.DeviceId), deviceID) {
+			return cipher.DeviceID(dar.ClusterHeadId), dar.Validity
+		}
+	}
+
+	for _, renewal := range block.Renewals {
+		if bytes.Equal(cipher.DeviceID(renewal.DeviceId), deviceID) {
+			return cipher.DeviceID(renewal.ClusterHeadId), renewal.Validity
+		}
+	}
+
+	for _, rotation := range block.Rotations {
+		if bytes.Equal(rotation.NewDeviceId, deviceID) {
+			return cipher.DeviceID(rotation.ClusterHeadId), rotation.Validity
+		}
+	}
+
+	return nil, 0
+}
+
+func getEntry(tx storage.Tx, level uint32, deviceID []byte) (*types.AuthenticationEntry, error) {
+	data, err := tx.Get(bucketAuthTableLevel(level), deviceID)
+	if err != nil {
+		return nil, fmt.Errorf("device %x: %w", deviceID, ErrNotFoundDevice)
+	}
+
+	var entry types.AuthenticationEntry
+	if err = proto.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+func putEntry(tx storage.Tx, level uint32, entry *types.AuthenticationEntry) error {
+	data, err := proto.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	return tx.Put(bucketAuthTableLevel(level), entry.DeviceId, data, types.InfinityTTL)
+}
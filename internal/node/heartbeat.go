@@ -0,0 +1,138 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package node
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"authentication-chains/internal/storage"
+	"authentication-chains/internal/types"
+)
+
+// Heartbeat checks the liveness of the peers by their statuses. Peers which miss heartbeats in a row
+// become suspect and then are evicted, the cluster head is replaced by the election among the cluster nodes instead.
+func (n *Node) Heartbeat(ctx context.Context) {
+	ctx, logger := n.logger.StartTrace(ctx, "heartbeat")
+	defer logger.FinishTrace()
+
+	group := n.workerPool.Group()
+
+	if clusterHead := n.getClusterHead(); clusterHead != nil {
+		group.Submit(func() { n.checkPeer(ctx, types.BucketClusterHead, clusterHead) })
+	}
+
+	for bucket, peers := range map[string]*Peers{
+		types.BucketClusterNodes:  n.getClusterNodes(),
+		types.BucketChildrenNodes: n.getChildrenNodes(),
+	} {
+		if peers == nil {
+			continue
+		}
+
+		for _, peer := range peers.GetAll() {
+			bucket, peer := bucket, peer
+
+			group.Submit(func() { n.checkPeer(ctx, bucket, peer) })
+		}
+	}
+
+	group.Wait()
+
+	if clusterHead := n.getClusterHead(); clusterHead != nil && clusterHead.Failures() >= n.cfg.Heartbeat.EvictAfter {
+		n.electClusterHead(ctx, clusterHead)
+	}
+}
+
+// checkPeer requests the status of the peer and updates its health, the peer of the bucket is evicted
+// once it misses too many heartbeats.
+func (n *Node) checkPeer(ctx context.Context, bucket string, peer *Peer) {
+	ctx, logger := n.logger.StartTrace(ctx, "check peer")
+	logger = logger.WithFields("peer", peer.Name)
+	defer logger.FinishTrace()
+
+	statusCtx, cancel := context.WithTimeout(ctx, n.cfg.Heartbeat.Timeout)
+	defer cancel()
+
+	start := time.Now()
+
+	if status, err := peer.Client.GetStatus(statusCtx, &types.StatusRequest{}); err == nil {
+		if peer.State() == PeerSuspect {
+			logger.Infof("peer is alive again")
+		}
+
+		peer.RecordSuccess(time.Since(start))
+
+		if bucket == types.BucketClusterHead {
+			n.setUpperClusterHead(status.ClusterHead)
+		}
+
+		return
+	}
+
+	failures := peer.RecordFailure()
+
+	switch {
+	case failures >= n.cfg.Heartbeat.EvictAfter && bucket != types.BucketClusterHead:
+		if err := n.evictPeer(ctx, bucket, peer); err != nil {
+			logger.Errorf("evict peer: %s", err)
+			return
+		}
+
+		logger.Infof("peer is evicted after %d failed heartbeats, last seen %s", failures, formatLastSeen(peer.LastSeen()))
+
+	case failures >= n.cfg.Heartbeat.SuspectAfter && peer.State() == PeerAlive:
+		peer.SetState(PeerSuspect)
+
+		logger.Infof("peer is suspect after %d failed heartbeats", failures)
+
+	default:
+		logger.Debugw("peer missed heartbeat", "failures", failures)
+	}
+}
+
+// evictPeer removes the peer from the known nodes of the bucket and closes its connection.
+func (n *Node) evictPeer(ctx context.Context, bucket string, peer *Peer) error {
+	ctx, logger := n.logger.StartTrace(ctx, "evict peer")
+	defer logger.FinishTrace()
+
+	peers := map[string]*Peers{
+		types.BucketClusterNodes:  n.getClusterNodes(),
+		types.BucketChildrenNodes: n.getChildrenNodes(),
+	}[bucket]
+
+	if err := n.db.Update(func(tx storage.Tx) error {
+		err := tx.Delete(bucket, peer.DeviceID)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return err
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if peers != nil {
+		peers.Remove(peer.DeviceID)
+	}
+
+	peer.SetState(PeerEvicted)
+
+	if err := peer.Close(); err != nil {
+		logger.Errorf("close connection to peer %s: %s", peer.Name, err)
+	}
+
+	return nil
+}
+
+// formatLastSeen formats the time the peer was last seen at.
+func formatLastSeen(lastSeen time.Time) string {
+	if lastSeen.IsZero() {
+		return "never"
+	}
+
+	return lastSeen.UTC().Format(time.DateTime)
+}
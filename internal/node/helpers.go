@@ -9,65 +9,48 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
-	"github.com/nutsdb/nutsdb"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/protobuf/proto"
 
 	"authentication-chains/internal/blockchain"
 	"authentication-chains/internal/cipher"
+	"authentication-chains/internal/config"
+	"authentication-chains/internal/storage"
+	"authentication-chains/internal/transport"
 	"authentication-chains/internal/types"
 )
 
 // mineBlock mines a new block.
-func (n *Node) mineBlock(ctx context.Context, dar *types.DeviceAuthenticationRequest) (*types.Block, error) {
+func (n *Node) mineBlock(ctx context.Context, txs types.Transactions) (*types.Block, error) {
 	ctx, logger := n.logger.StartTrace(ctx, "mine block")
 	defer logger.FinishTrace()
 
-	block, err := n.chain.CreateBlock(dar)
+	n.miningMutex.Lock()
+	defer n.miningMutex.Unlock()
+
+	block, err := n.chain.CreateBlock(txs, n.getClusterVoterIDs())
 	if err != nil {
 		return nil, err
 	}
 
-	if n.clusterNodes != nil {
-		peers := n.clusterNodes.GetAll()
-
-		for _, node := range peers {
-			response, err := node.Client.SendBlock(ctx, &types.BlockValidationRequest{Block: block})
-			if err != nil {
-				logger.Errorf("send block to node %s: %s", node.Name, err)
-				continue
-			}
-
-			if !response.IsValid {
-				logger.Errorf("validation by node %s: block %x is not valid", node.Name, block.Hash)
-				return nil, blockchain.ErrBlockValidation
-			}
-		}
-	}
-
-	if n.clusterHead != nil {
-		response, err := n.clusterHead.Client.SendBlock(ctx, &types.BlockValidationRequest{Block: block})
-		if err != nil {
-			logger.Errorf("send block to cluster head: %s", err)
-			return nil, err
-		}
-
-		if !response.IsValid {
-			logger.Errorf("validation by cluster head %s: block %x is not valid", n.clusterHead.Name, block.Hash)
-			return nil, blockchain.ErrBlockValidation
-		}
+	if err = n.collectVotes(ctx, block); err != nil {
+		logger.Errorf("collect votes: %s", err)
+		return nil, err
 	}
 
 	if err = n.chain.AddBlock(block); err != nil {
 		return nil, err
 	}
 
-	if err = n.addAuthenticationEntry(ctx, block, n.cfg.Level); err != nil {
+	if err = n.addAuthenticationEntry(ctx, block, n.getLevel()); err != nil {
 		return nil, err
 	}
 
+	n.commitBlock(ctx, block)
+
 	return block, nil
 }
 
@@ -77,8 +60,8 @@ func (n *Node) getAuthenticationTable(ctx context.Context) (map[uint32]*types.Au
 
 	table := make(map[uint32]*types.AuthenticationEntries)
 
-	if err := n.db.View(func(tx *nutsdb.Tx) error {
-		for i := int32(n.cfg.Level); i >= 0; i-- {
+	if err := n.db.View(func(tx storage.Tx) error {
+		for i := int32(n.getLevel()); i >= 0; i-- {
 			level := uint32(i)
 
 			entries := make([]*types.AuthenticationEntry, 0)
@@ -109,18 +92,34 @@ func (n *Node) getAuthenticationTable(ctx context.Context) (map[uint32]*types.Au
 }
 
 func (n *Node) getAuthenticationEntry(ctx context.Context, deviceID []byte) (*types.AuthenticationEntry, error) {
+	return n.getLevelAuthenticationEntry(ctx, deviceID, n.getLevel())
+}
+
+// findAuthenticationEntry returns the authentication entry of the device from the tables of the node level and below.
+func (n *Node) findAuthenticationEntry(ctx context.Context, deviceID []byte) (*types.AuthenticationEntry, uint32, error) {
+	for i := int32(n.getLevel()); i >= 0; i-- {
+		if entry, err := n.getLevelAuthenticationEntry(ctx, deviceID, uint32(i)); err == nil {
+			return entry, uint32(i), nil
+		}
+	}
+
+	return nil, 0, ErrNotFoundDevice
+}
+
+// getLevelAuthenticationEntry returns the authentication entry of the device from the table of the level.
+func (n *Node) getLevelAuthenticationEntry(ctx context.Context, deviceID []byte, level uint32) (*types.AuthenticationEntry, error) {
 	ctx, logger := n.logger.StartTrace(ctx, "get authentication entry")
 	defer logger.FinishTrace()
 
 	var auth types.AuthenticationEntry
 
-	if err := n.db.View(func(tx *nutsdb.Tx) error {
-		data, err := tx.Get(bucketAuthTableLevel(n.cfg.Level), deviceID)
+	if err := n.db.View(func(tx storage.Tx) error {
+		data, err := tx.Get(bucketAuthTableLevel(level), deviceID)
 		if err != nil {
 			return err
 		}
 
-		if err = proto.Unmarshal(data.Value, &auth); err != nil {
+		if err = proto.Unmarshal(data, &auth); err != nil {
 			return err
 		}
 
@@ -132,52 +131,319 @@ func (n *Node) getAuthenticationEntry(ctx context.Context, deviceID []byte) (*ty
 	return &auth, nil
 }
 
-// addAuthenticationEntry registers a device in authentication table.
+// getInclusionProof builds the merkle path from the device authentication request to its block header.
+func (n *Node) getInclusionProof(ctx context.Context, deviceID []byte) (*types.InclusionProofResponse, error) {
+	ctx, logger := n.logger.StartTrace(ctx, "get inclusion proof")
+	defer logger.FinishTrace()
+
+	entry, err := n.getAuthenticationEntry(ctx, deviceID)
+	if err != nil {
+		return nil, ErrNotFoundDevice
+	}
+
+	switch {
+	case entry.Revoked:
+		return nil, ErrDeviceRevoked
+	case entry.RotatedTo != nil:
+		return nil, ErrKeyRotated
+	}
+
+	block, err := n.chain.GetBlock(entry.BlockIndex)
+	if err != nil {
+		logger.Errorf("get block %d: %s", entry.BlockIndex, err)
+		return nil, ErrNotFoundBlock
+	}
+
+	for i, dar := range block.Dars {
+		if !bytes.Equal(cipher.DeviceID(dar.DeviceId), deviceID) {
+			continue
+		}
+
+		path, err := cipher.MerkleProof(block, i)
+		if err != nil {
+			return nil, err
+		}
+
+		return &types.InclusionProofResponse{
+			Header: block.Header(),
+			Dar:    dar,
+			Path:   path,
+		}, nil
+	}
+
+	// renewals go after device authentication requests and revocations
+	offset := len(block.Dars) + len(block.Revocations)
+
+	for i, renewal := range block.Renewals {
+		if !bytes.Equal(cipher.DeviceID(renewal.DeviceId), deviceID) {
+			continue
+		}
+
+		path, err := cipher.MerkleProof(block, offset+i)
+		if err != nil {
+			return nil, err
+		}
+
+		return &types.InclusionProofResponse{
+			Header:  block.Header(),
+			Renewal: renewal,
+			Path:    path,
+		}, nil
+	}
+
+	// rotations go last
+	offset += len(block.Renewals)
+
+	for i, rotation := range block.Rotations {
+		if !bytes.Equal(rotation.NewDeviceId, deviceID) {
+			continue
+		}
+
+		path, err := cipher.MerkleProof(block, offset+i)
+		if err != nil {
+			return nil, err
+		}
+
+		return &types.InclusionProofResponse{
+			Header:   block.Header(),
+			Rotation: rotation,
+			Path:     path,
+		}, nil
+	}
+
+	return nil, ErrNotFoundDevice
+}
+
+// addAuthenticationEntry registers devices of the block in authentication table,
+// extends renewed ones, tombstones revoked ones and links rotated ones to their new keys.
+// Public keys of registered devices and new keys of rotated ones are stored in the key bucket.
+// Blocks of the previous versions identify devices by PEM public keys, their entries are keyed by fingerprints too.
 func (n *Node) addAuthenticationEntry(ctx context.Context, block *types.Block, level uint32) error {
 	ctx, logger := n.logger.StartTrace(ctx, "add authentication entry")
 	defer logger.FinishTrace()
 
-	if n.cfg.Level < level {
-		return fmt.Errorf("can't add entry from upper blockchain: node level %d < entry level %d", n.cfg.Level, level)
+	if nodeLevel := n.getLevel(); nodeLevel < level {
+		return fmt.Errorf("can't add entry from upper blockchain: node level %d < entry level %d", nodeLevel, level)
+	}
+
+	return n.db.Update(func(tx storage.Tx) error {
+		return putAuthenticationEntry(tx, block, level)
+	})
+}
+
+// putAuthenticationEntry applies the changes of the block to authentication table of the level in the transaction.
+func putAuthenticationEntry(tx storage.Tx, block *types.Block, level uint32) error {
+	for _, dar := range block.Dars {
+		if err := putDARKey(tx, dar); err != nil {
+			return err
+		}
+
+		// an entry is never overwritten, it would bring a revoked device back
+		if _, err := getEntry(tx, level, cipher.DeviceID(dar.DeviceId)); err == nil {
+			return fmt.Errorf("%w: device %x is already registered", ErrInvalidDAR, dar.DeviceId)
+		}
+
+		entry := &types.AuthenticationEntry{
+			DeviceId:      cipher.DeviceID(dar.DeviceId),
+			ClusterHeadId: cipher.DeviceID(dar.ClusterHeadId),
+			BlockHash:     block.Hash,
+			BlockIndex:    block.Index,
+			NotAfter:      block.NotAfter(dar.Validity),
+		}
+
+		data, err := proto.Marshal(entry)
+		if err != nil {
+			return err
+		}
+
+		if err = tx.Put(bucketAuthTableLevel(level), entry.DeviceId, data, types.InfinityTTL); err != nil {
+			return err
+		}
+	}
+
+	for _, revocation := range block.Revocations {
+		var entry types.AuthenticationEntry
+
+		data, err := tx.Get(bucketAuthTableLevel(level), cipher.DeviceID(revocation.DeviceId))
+		if err != nil {
+			return fmt.Errorf("revoke device: %w", ErrNotFoundDevice)
+		}
+
+		if err = proto.Unmarshal(data, &entry); err != nil {
+			return err
+		}
+
+		entry.Revoked = true
+		entry.RevocationBlockHash = block.Hash
+
+		value, err := proto.Marshal(&entry)
+		if err != nil {
+			return err
+		}
+
+		if err = tx.Put(bucketAuthTableLevel(level), entry.DeviceId, value, types.InfinityTTL); err != nil {
+			return err
+		}
+	}
+
+	for _, renewal := range block.Renewals {
+		var entry types.AuthenticationEntry
+
+		data, err := tx.Get(bucketAuthTableLevel(level), cipher.DeviceID(renewal.DeviceId))
+		if err != nil {
+			return fmt.Errorf("renew device: %w", ErrNotFoundDevice)
+		}
+
+		if err = proto.Unmarshal(data, &entry); err != nil {
+			return err
+		}
+
+		entry.ClusterHeadId = cipher.DeviceID(renewal.ClusterHeadId)
+		entry.BlockHash = block.Hash
+		entry.BlockIndex = block.Index
+		entry.NotAfter = block.NotAfter(renewal.Validity)
+
+		value, err := proto.Marshal(&entry)
+		if err != nil {
+			return err
+		}
+
+		if err = tx.Put(bucketAuthTableLevel(level), entry.DeviceId, value, types.InfinityTTL); err != nil {
+			return err
+		}
 	}
 
-	entry := &types.AuthenticationEntry{
-		DeviceId:      block.Dar.DeviceId,
-		ClusterHeadId: block.Dar.ClusterHeadId,
-		BlockHash:     block.Hash,
-		BlockIndex:    block.Index,
+	for _, rotation := range block.Rotations {
+		if err := putRotation(tx, rotation); err != nil {
+			return err
+		}
+
+		entry, err := getEntry(tx, level, cipher.DeviceID(rotation.DeviceId))
+		if err != nil {
+			return fmt.Errorf("rotate device key: %w", err)
+		}
+
+		entry.RotatedTo = rotation.NewDeviceId
+		entry.RotationBlockHash = block.Hash
+
+		if err = putEntry(tx, level, entry); err != nil {
+			return err
+		}
+
+		if err = putEntry(tx, level, &types.AuthenticationEntry{
+			DeviceId:      rotation.NewDeviceId,
+			ClusterHeadId: cipher.DeviceID(rotation.ClusterHeadId),
+			BlockHash:     block.Hash,
+			BlockIndex:    block.Index,
+			NotAfter:      block.NotAfter(rotation.Validity),
+			RotatedFrom:   entry.DeviceId,
+		}); err != nil {
+			return err
+		}
 	}
 
-	data, err := proto.Marshal(entry)
+	return nil
+}
+
+// putTicket stores the status of device authentication request.
+func (n *Node) putTicket(ctx context.Context, ticket *types.DeviceAuthenticationResponse) error {
+	ctx, logger := n.logger.StartTrace(ctx, "put ticket")
+	defer logger.FinishTrace()
+
+	data, err := proto.Marshal(ticket)
 	if err != nil {
 		return err
 	}
 
-	if err = n.db.Update(func(tx *nutsdb.Tx) error {
-		return tx.Put(bucketAuthTableLevel(level), entry.DeviceId, data, types.InfinityTTL)
+	return n.db.Update(func(tx storage.Tx) error {
+		return tx.Put(types.BucketTickets, ticket.Ticket, data, uint32(n.cfg.MemPool.TicketTTL.Seconds()))
+	})
+}
+
+// getTicket returns the status of device authentication request by ticket.
+func (n *Node) getTicket(ctx context.Context, ticket []byte) (*types.DeviceAuthenticationResponse, error) {
+	ctx, logger := n.logger.StartTrace(ctx, "get ticket")
+	defer logger.FinishTrace()
+
+	var response types.DeviceAuthenticationResponse
+
+	if err := n.db.View(func(tx storage.Tx) error {
+		data, err := tx.Get(types.BucketTickets, ticket)
+		if err != nil {
+			return ErrNotFoundTicket
+		}
+
+		return proto.Unmarshal(data, &response)
 	}); err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return &response, nil
+}
+
+// failOrphanedTickets rejects the pending tickets left by the previous run. The mem-pool lives in memory,
+// so their requests are lost with it and the devices have to send them once again.
+func failOrphanedTickets(db storage.Storage, ttl time.Duration) error {
+	return db.Update(func(tx storage.Tx) error {
+		entries, err := tx.GetAll(types.BucketTickets)
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			var ticket types.DeviceAuthenticationResponse
+			if err = proto.Unmarshal(entry.Value, &ticket); err != nil {
+				return err
+			}
+
+			if ticket.Status != types.DARStatus_DAR_STATUS_PENDING {
+				continue
+			}
+
+			ticket.Status = types.DARStatus_DAR_STATUS_REJECTED
+
+			data, err := proto.Marshal(&ticket)
+			if err != nil {
+				return err
+			}
+
+			if err = tx.Put(types.BucketTickets, entry.Key, data, uint32(ttl.Seconds())); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// darTicket returns the ticket of device authentication request.
+func darTicket(dar *types.DeviceAuthenticationRequest) []byte {
+	return cipher.Hash(dar.Signature)
 }
 
-// verifyAuthentication verifies the authentication of the device by authentication table.
-func (n *Node) verifyAuthentication(ctx context.Context, deviceID, blockHash []byte) error {
+// verifyAuthentication verifies the authentication of the device by authentication table and returns the hops
+// the device is verified through, the last hop is made by the node. Blocks of the lower levels are fetched
+// from the child cluster of the device, devices unknown to the node are verified by the cluster head.
+func (n *Node) verifyAuthentication(ctx context.Context, deviceID, blockHash []byte) ([]*types.VerificationHop, error) {
 	ctx, logger := n.logger.StartTrace(ctx, "verify authentication")
 	defer logger.FinishTrace()
 
 	var (
 		entry types.AuthenticationEntry
 		level uint32
+		hops  []*types.VerificationHop
 	)
 
-	if err := n.db.View(func(tx *nutsdb.Tx) error {
-		for i := int32(n.cfg.Level); i >= 0; i-- {
+	if err := n.db.View(func(tx storage.Tx) error {
+		for i := int32(n.getLevel()); i >= 0; i-- {
 			level = uint32(i)
 			data, err := tx.Get(bucketAuthTableLevel(level), deviceID)
 			if data != nil {
-				if err = proto.Unmarshal(data.Value, &entry); err != nil {
+				if err = proto.Unmarshal(data, &entry); err != nil {
 					return err
 				}
 
@@ -187,63 +453,89 @@ func (n *Node) verifyAuthentication(ctx context.Context, deviceID, blockHash []b
 
 		return nil
 	}); err != nil {
-		return err
+		return nil, err
+	}
+
+	if entry.RotatedFrom != nil {
+		logger.Debugw("device key is rotated", "lineage", fmt.Sprintf("%x", n.getLineage(ctx, &entry, level)))
 	}
 
 	switch {
-	case entry.BlockHash != nil && bytes.Equal(entry.BlockHash, blockHash) && level == n.cfg.Level:
+	case entry.Revoked:
+		return nil, fmt.Errorf("%w: %s", ErrVerification, ErrDeviceRevoked)
+
+	case entry.RotatedTo != nil:
+		return nil, fmt.Errorf("%w: %s to %x", ErrVerification, ErrKeyRotated, entry.RotatedTo)
+
+	case entry.NotAfter != 0 && time.Now().Unix() > entry.NotAfter:
+		return nil, fmt.Errorf("%w: %s", ErrVerification, ErrAuthenticationExpired)
+
+	case entry.BlockHash != nil && bytes.Equal(entry.BlockHash, blockHash) && level == n.getLevel():
 		block, err := n.chain.GetBlock(entry.BlockIndex)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		if !bytes.Equal(block.Hash, blockHash) {
-			return fmt.Errorf("%w: block hash mismatch", ErrVerification)
+			return nil, fmt.Errorf("%w: block hash mismatch", ErrVerification)
 		}
 
 	case entry.BlockHash != nil && bytes.Equal(entry.BlockHash, blockHash):
-		return nil
+		// the entry is committed by the child cluster, so its block is fetched from there instead of being trusted
+		_, found, err := n.findChildBlock(ctx, deviceID, &entry)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrVerification, err)
+		}
 
-		// for _, peer := range n.childrenNodes.GetAll() {
-		// 	response, err := peer.Client.FindBlock(ctx, &types.FindBlockRequest{Index: entry.BlockIndex, Hash: blockHash})
-		// 	if err != nil {
-		// 		logger.Errorf("get block from node %s: %s", peer.Name, err)
-		// 		continue
-		// 	}
-		//
-		// 	if !bytes.Equal(response.Block.Hash, blockHash) {
-		// 		return fmt.Errorf("%w: block hash mismatch", ErrVerification)
-		// 	}
-		// }
+		hops = found
 
 	case entry.BlockHash != nil && !bytes.Equal(entry.BlockHash, blockHash):
-		return fmt.Errorf("%w: block hash mismatch", ErrVerification)
+		return nil, fmt.Errorf("%w: block hash mismatch", ErrVerification)
 
-	case entry.BlockHash == nil && n.clusterHead != nil:
-		verifyResponse, err := n.clusterHead.Client.VerifyDevice(ctx, &types.VerifyDeviceRequest{
-			DeviceId:  deviceID,
-			BlockHash: blockHash,
-		})
+	case entry.BlockHash == nil && n.getClusterHead() != nil:
+		verifyResponse, err := n.verifyDeviceByClusterHead(ctx, deviceID, blockHash)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		if !verifyResponse.IsVerified {
-			return fmt.Errorf("%w: device is not registered", ErrVerification)
+			return nil, fmt.Errorf("%w: device is not registered", ErrVerification)
 		}
 
-		return nil
+		hops = verifyResponse.Hops
+		entry.BlockHash, entry.BlockIndex = blockHash, hops[0].BlockIndex
 
 	default:
-		return fmt.Errorf("%w: device is not registered", ErrVerification)
+		return nil, fmt.Errorf("%w: device is not registered", ErrVerification)
 	}
 
-	return nil
+	hop, err := n.newVerificationHop(deviceID, entry.BlockHash, entry.BlockIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(hops, hop), nil
+}
+
+// initClient initializes a new client of the node with the device id.
+// In the pinned TLS mode the connection is pinned to the device id, any node is accepted if it's empty.
+func (n *Node) initClient(ctx context.Context, address string, deviceID []byte) (types.NodeClient, error) {
+	var pin transport.Pin
+	if len(deviceID) != 0 {
+		pin = func(id []byte) bool { return n.sameDevice(deviceID, id) }
+	}
+
+	return n.dial(ctx, address, pin)
 }
 
-// initClient initializes a new client.
-func initClient(ctx context.Context, address string) (types.NodeClient, error) {
-	conn, err := grpc.DialContext(ctx, address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// dial connects to the node at the address with the transport credentials of the node.
+func (n *Node) dial(ctx context.Context, address string, pin transport.Pin) (types.NodeClient, error) {
+	creds, err := transport.DialCredentials(n.cfg.GRPC.TLS, n.Cipher, pin)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.DialContext(ctx, address, grpc.WithTransportCredentials(creds))
 	if err != nil {
 		return nil, err
 	}
@@ -253,22 +545,33 @@ func initClient(ctx context.Context, address string) (types.NodeClient, error) {
 		_ = conn.Close()
 	}()
 
-	return types.NewNodeClient(conn), nil
+	return &nodeClient{NodeClient: types.NewNodeClient(conn), conn: conn}, nil
 }
 
 func (n *Node) getClusterHeadDeviceID() []byte {
 	var clusterHeadID []byte
-	if n.clusterHead != nil {
-		clusterHeadID = n.clusterHead.DeviceID
+	if clusterHead := n.getClusterHead(); clusterHead != nil {
+		clusterHeadID = clusterHead.DeviceID
 	}
 
 	return clusterHeadID
 }
 
+// childrenLevel returns the level of the children of the node, the level is unsigned, so a node of level 0 has none.
+func (n *Node) childrenLevel() (uint32, error) {
+	level := n.getLevel()
+	if level == 0 {
+		return 0, ErrNoChildrenLevel
+	}
+
+	return level - 1, nil
+}
+
 func (n *Node) createDAR() (*types.DeviceAuthenticationRequest, error) {
 	dar := &types.DeviceAuthenticationRequest{
 		DeviceId:      n.deviceID,
 		ClusterHeadId: n.getClusterHeadDeviceID(),
+		Validity:      uint64(n.cfg.Authentication.MaxValidity.Seconds()),
 	}
 
 	if err := n.cipher.SignDAR(dar); err != nil {
@@ -284,7 +587,7 @@ func (n *Node) initMaster(ctx context.Context) error {
 
 	auth, err := n.getAuthenticationEntry(ctx, n.deviceID)
 	if err == nil {
-		n.authBlockHash = auth.BlockHash
+		n.setAuthBlockHash(auth.BlockHash)
 		return nil
 	}
 
@@ -294,15 +597,18 @@ func (n *Node) initMaster(ctx context.Context) error {
 		return err
 	}
 
-	n.chain.SetGenesisHash([]byte(n.cfg.GenesisHash))
+	if err = n.chain.SetGenesisHash([]byte(n.cfg.GenesisHash)); err != nil {
+		logger.Errorf("set genesis hash: %s", err)
+		return err
+	}
 
-	block, err := n.mineBlock(ctx, dar)
+	block, err := n.mineBlock(ctx, types.Transactions{DARs: []*types.DeviceAuthenticationRequest{dar}})
 	if err != nil {
 		logger.Errorf("mine block: %s", err)
 		return err
 	}
 
-	n.authBlockHash = block.Hash
+	n.setAuthBlockHash(block.Hash)
 
 	return nil
 }
@@ -312,20 +618,33 @@ func (n *Node) initPeers(ctx context.Context) error {
 	ctx, logger := n.logger.StartTrace(ctx, "init peers")
 	defer logger.FinishTrace()
 
-	if n.clusterHead == nil {
-		client, err := initClient(ctx, n.cfg.ClusterHeadGRPCAddress)
+	if n.getClusterHead() == nil {
+		pin, err := transport.PinDeviceID(n.cfg.GRPC.TLS.PeerDeviceID)
+		if err != nil {
+			return err
+		}
+
+		client, err := n.dial(ctx, n.cfg.ClusterHeadGRPCAddress, pin)
 		if err != nil {
 			logger.Errorf("init cluster head client: %s", err)
 			return err
 		}
 
-		status, err := client.GetStatus(ctx, &types.StatusRequest{})
+		var p peer.Peer
+
+		status, err := client.GetStatus(ctx, &types.StatusRequest{}, grpc.Peer(&p))
 		if err != nil {
 			logger.Errorf("get cluster head status: %s", err)
 			return err
 		}
 
-		n.clusterHead = NewPeer(
+		// the cluster head is dialed before its id is known, so the id it claims is checked against its certificate
+		if n.cfg.GRPC.TLS.Mode == config.TLSModePinned && !bytes.Equal(transport.DeviceID(p.AuthInfo), status.Peer.DeviceId) {
+			logger.Errorf("cluster head %s: %s", status.Peer.Name, ErrPeerIdentity)
+			return ErrPeerIdentity
+		}
+
+		clusterHead := NewPeer(
 			status.Peer.Name,
 			status.Peer.DeviceId,
 			status.Peer.ClusterHeadId,
@@ -334,7 +653,9 @@ func (n *Node) initPeers(ctx context.Context) error {
 			client,
 		)
 
-		if err = n.addPeer(ctx, n.clusterHead); err != nil {
+		n.setClusterHead(clusterHead)
+
+		if err = n.addPeer(ctx, clusterHead); err != nil {
 			return err
 		}
 	}
@@ -347,7 +668,7 @@ func (n *Node) initPeers(ctx context.Context) error {
 
 	auth, err := n.getAuthenticationEntry(ctx, n.deviceID)
 	if err == nil {
-		n.authBlockHash = auth.BlockHash
+		n.setAuthBlockHash(auth.BlockHash)
 		return nil
 	}
 
@@ -357,13 +678,13 @@ func (n *Node) initPeers(ctx context.Context) error {
 		return err
 	}
 
-	block, err := n.mineBlock(ctx, dar)
+	block, err := n.mineBlock(ctx, types.Transactions{DARs: []*types.DeviceAuthenticationRequest{dar}})
 	if err != nil {
 		logger.Errorf("mine block: %s", err)
 		return err
 	}
 
-	n.authBlockHash = block.Hash
+	n.setAuthBlockHash(block.Hash)
 
 	return nil
 }
@@ -372,28 +693,34 @@ func (n *Node) registerNode(ctx context.Context) error {
 	ctx, logger := n.logger.StartTrace(ctx, "register node")
 	defer logger.FinishTrace()
 
-	registerResponse, err := n.clusterHead.Client.RegisterNode(ctx, &types.NodeRegistrationRequest{
-		Node: &types.Peer{
-			Name:          n.cfg.Name,
-			Level:         n.cfg.Level,
-			DeviceId:      n.deviceID,
-			ClusterHeadId: n.clusterHead.DeviceID,
-			GrpcAddress:   n.cfg.GRPC.Address,
-		},
-	})
+	request, err := n.newRegistration(ctx)
+	if err != nil {
+		logger.Errorf("create registration: %s", err)
+		return err
+	}
+
+	clusterHead := n.getClusterHead()
+	if clusterHead == nil {
+		return fmt.Errorf("%w: cluster head", ErrUnknownPeer)
+	}
+
+	registerResponse, err := clusterHead.Client.RegisterNode(ctx, request)
 	if err != nil {
 		logger.Errorf("register node: %s", err)
 		return ErrInvalidDAR
 	}
 
-	n.chain.SetGenesisHash(registerResponse.GenesisHash)
+	if err = n.chain.SetGenesisHash(registerResponse.GenesisHash); err != nil {
+		logger.Errorf("set genesis hash: %s", err)
+		return err
+	}
 
 	for _, peer := range registerResponse.Peers {
 		if bytes.Equal(peer.DeviceId, n.deviceID) {
 			continue
 		}
 
-		client, err := initClient(ctx, peer.GrpcAddress)
+		client, err := n.initClient(ctx, peer.GrpcAddress, peer.DeviceId)
 		if err != nil {
 			logger.Errorf("init peer client: %s", err)
 			return err
@@ -413,34 +740,36 @@ func (n *Node) registerNode(ctx context.Context) error {
 	return nil
 }
 
-// syncBlocks syncs blocks from the node.
-func (n *Node) syncBlocks(ctx context.Context, peer *Peer, from, to uint64) error {
-	ctx, logger := n.logger.StartTrace(ctx, "sync blocks from node "+peer.Name)
+func (n *Node) addBlock(ctx context.Context, block *types.Block) error {
+	ctx, logger := n.logger.StartTrace(ctx, "add block")
 	defer logger.FinishTrace()
 
-	response, err := peer.Client.GetBlocks(ctx, &types.BlocksRequest{
-		From: from,
-		To:   to,
-	})
-	if err != nil {
-		logger.Errorf("get blocks from node %s: %s", peer.Name, err)
+	if err := n.verifyBlock(block); err != nil {
+		logger.Errorf("verify block %x: %s", block.Hash, err)
 		return err
 	}
 
-	for _, block := range response.Blocks {
-		if err = n.addBlock(ctx, block); err != nil {
-			return err
-		}
+	// every path the block comes by (commit, sync, import) needs the approvals of the validators the block was mined by
+	if err := n.verifyQuorum(ctx, block); err != nil {
+		logger.Errorf("verify quorum of block %x: %s", block.Hash, err)
+		return err
 	}
 
-	return nil
-}
+	// a competing block is validated against authentication table only when its branch wins
+	if err := n.chain.ValidateBlock(block); errors.Is(err, blockchain.ErrForkDetected) {
+		if err = n.chain.AddBlock(block); !errors.Is(err, blockchain.ErrForkDetected) {
+			logger.Errorf("add competing block %x: %v", block.Hash, err)
+			return fmt.Errorf("%w: competing block %x", ErrBlockValidation, block.Hash)
+		}
 
-func (n *Node) addBlock(ctx context.Context, block *types.Block) error {
-	ctx, logger := n.logger.StartTrace(ctx, "add block")
-	defer logger.FinishTrace()
+		logger.Infof("fork detected at block %d %x", block.Index, block.Hash)
+
+		return n.reorganize(ctx)
+	}
 
-	if err := n.validateBlock(ctx, block); err != nil {
+	level := n.getLevel()
+
+	if err := n.validateBlock(ctx, block, level); err != nil {
 		logger.Errorf("validate block %x: %s", block.Hash, err)
 		return err
 	}
@@ -450,7 +779,7 @@ func (n *Node) addBlock(ctx context.Context, block *types.Block) error {
 		return err
 	}
 
-	if err := n.addAuthenticationEntry(ctx, block, n.cfg.Level); err != nil {
+	if err := n.addAuthenticationEntry(ctx, block, level); err != nil {
 		logger.Errorf("add authentication entry: %s", err)
 		return err
 	}
@@ -473,14 +802,52 @@ func (n *Node) addBlock(ctx context.Context, block *types.Block) error {
 // 	return true, nil
 // }
 
-// validateBlock validates the block.
-func (n *Node) validateBlock(ctx context.Context, block *types.Block) error {
+// validateBlock validates the block against authentication table of the level.
+func (n *Node) validateBlock(ctx context.Context, block *types.Block, level uint32) error {
 	ctx, logger := n.logger.StartTrace(ctx, "validate block")
 	defer logger.FinishTrace()
 
+	if err := n.verifyBlock(block); err != nil {
+		return err
+	}
+
+	// revoked and rotated devices keep their entries, so a device id is registered once
+	for _, dar := range block.Dars {
+		if _, err := n.getLevelAuthenticationEntry(ctx, cipher.DeviceID(dar.DeviceId), level); err == nil {
+			return fmt.Errorf("%w: %s: device %x is already registered", ErrBlockValidation, ErrInvalidDAR, dar.DeviceId)
+		}
+	}
+
+	for _, revocation := range block.Revocations {
+		if err := n.verifyRevocation(ctx, revocation, level); err != nil {
+			return fmt.Errorf("%w: %s", ErrBlockValidation, err)
+		}
+	}
+
+	for _, renewal := range block.Renewals {
+		if err := n.verifyRenewal(ctx, renewal, level); err != nil {
+			return fmt.Errorf("%w: %s", ErrBlockValidation, err)
+		}
+	}
+
+	for _, rotation := range block.Rotations {
+		if err := n.verifyRotation(ctx, rotation, level); err != nil {
+			return fmt.Errorf("%w: %s", ErrBlockValidation, err)
+		}
+	}
+
+	return nil
+}
+
+// verifyBlock verifies the block contents which don't depend on authentication table.
+func (n *Node) verifyBlock(block *types.Block) error {
+	if block.IsEmpty() {
+		return fmt.Errorf("%w: %s", ErrBlockValidation, ErrEmptyBlock)
+	}
+
 	switch {
-	case bytes.Equal(block.Dar.ClusterHeadId, n.deviceID):
-	case bytes.Equal(block.Dar.ClusterHeadId, n.getClusterHeadDeviceID()):
+	case bytes.Equal(block.ClusterHeadID(), n.deviceID):
+	case bytes.Equal(block.ClusterHeadID(), n.getClusterHeadDeviceID()):
 	default:
 		return fmt.Errorf("%w: invalid cluster head", ErrBlockValidation)
 	}
@@ -494,8 +861,169 @@ func (n *Node) validateBlock(ctx context.Context, block *types.Block) error {
 		return fmt.Errorf("%w: hash mismatch", ErrBlockValidation)
 	}
 
-	if err = cipher.VerifyDAR(block.Dar); err != nil {
-		return fmt.Errorf("%w: invalid dar", ErrBlockValidation)
+	root, err := cipher.MerkleRoot(block)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(root, block.MerkleRoot) {
+		return fmt.Errorf("%w: merkle root mismatch", ErrBlockValidation)
+	}
+
+	registered := make(map[string]bool, len(block.Dars))
+
+	for _, dar := range block.Dars {
+		if !bytes.Equal(dar.ClusterHeadId, block.ClusterHeadID()) {
+			return fmt.Errorf("%w: dars have different cluster heads", ErrBlockValidation)
+		}
+
+		deviceID := string(cipher.DeviceID(dar.DeviceId))
+		if registered[deviceID] {
+			return fmt.Errorf("%w: device %x is registered twice", ErrBlockValidation, dar.DeviceId)
+		}
+
+		registered[deviceID] = true
+
+		if err = cipher.VerifyDAR(dar); err != nil {
+			return fmt.Errorf("%w: invalid dar", ErrBlockValidation)
+		}
+
+		if err = n.verifyValidity(dar.Validity); err != nil {
+			return fmt.Errorf("%w: %s", ErrBlockValidation, err)
+		}
+	}
+
+	for _, revocation := range block.Revocations {
+		if !bytes.Equal(revocation.ClusterHeadId, block.ClusterHeadID()) {
+			return fmt.Errorf("%w: transactions have different cluster heads", ErrBlockValidation)
+		}
+	}
+
+	for _, renewal := range block.Renewals {
+		if !bytes.Equal(renewal.ClusterHeadId, block.ClusterHeadID()) {
+			return fmt.Errorf("%w: transactions have different cluster heads", ErrBlockValidation)
+		}
+	}
+
+	for _, rotation := range block.Rotations {
+		if !bytes.Equal(rotation.ClusterHeadId, block.ClusterHeadID()) {
+			return fmt.Errorf("%w: transactions have different cluster heads", ErrBlockValidation)
+		}
+
+		if _, err = cipher.RotationPublicKey(rotation); err != nil {
+			return fmt.Errorf("%w: invalid rotation", ErrBlockValidation)
+		}
+	}
+
+	return nil
+}
+
+// verifyRevocation verifies that the revocation is signed by the device itself or by its cluster head.
+func (n *Node) verifyRevocation(ctx context.Context, revocation *types.DeviceRevocationRequest, level uint32) error {
+	ctx, logger := n.logger.StartTrace(ctx, "verify revocation")
+	defer logger.FinishTrace()
+
+	entry, err := n.getLevelAuthenticationEntry(ctx, revocation.DeviceId, level)
+	if err != nil {
+		return ErrNotFoundDevice
+	}
+
+	if err = checkRevocation(revocation, entry, n.sameDevice); err != nil {
+		return err
+	}
+
+	publicKey, err := n.getPublicKey(ctx, revocation.SignerId)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidRevocation, err)
+	}
+
+	if err = cipher.VerifyRevocation(revocation, publicKey); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidRevocation, err)
+	}
+
+	return nil
+}
+
+// checkRevocation checks the revocation against the authentication entry of the device,
+// sameDevice tells if the ids belong to the same device.
+func checkRevocation(revocation *types.DeviceRevocationRequest, entry *types.AuthenticationEntry, sameDevice func(deviceID, otherID []byte) bool) error {
+	switch {
+	case entry.Revoked:
+		return ErrDeviceRevoked
+	case entry.RotatedTo != nil:
+		return ErrKeyRotated
+	case !sameDevice(revocation.ClusterHeadId, entry.ClusterHeadId):
+		return fmt.Errorf("%w: cluster head mismatch", ErrInvalidRevocation)
+	}
+
+	switch {
+	case bytes.Equal(revocation.SignerId, entry.DeviceId):
+	case sameDevice(revocation.SignerId, entry.ClusterHeadId):
+	default:
+		return fmt.Errorf("%w: signer is neither device nor its cluster head", ErrInvalidRevocation)
+	}
+
+	return nil
+}
+
+// verifyRenewal verifies that the renewal points at the current authentication block of the device.
+func (n *Node) verifyRenewal(ctx context.Context, renewal *types.DeviceRenewalRequest, level uint32) error {
+	ctx, logger := n.logger.StartTrace(ctx, "verify renewal")
+	defer logger.FinishTrace()
+
+	entry, err := n.getLevelAuthenticationEntry(ctx, renewal.DeviceId, level)
+	if err != nil {
+		return ErrNotFoundDevice
+	}
+
+	if err = checkRenewal(renewal, entry, n.sameDevice); err != nil {
+		return err
+	}
+
+	if err = n.verifyValidity(renewal.Validity); err != nil {
+		return err
+	}
+
+	publicKey, err := n.getPublicKey(ctx, renewal.DeviceId)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidRenewal, err)
+	}
+
+	if err = cipher.VerifyRenewal(renewal, publicKey); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidRenewal, err)
+	}
+
+	return nil
+}
+
+// checkRenewal checks that the renewal points at the current authentication block of the device entry,
+// sameDevice tells if the ids belong to the same device.
+func checkRenewal(renewal *types.DeviceRenewalRequest, entry *types.AuthenticationEntry, sameDevice func(deviceID, otherID []byte) bool) error {
+	switch {
+	case entry.Revoked:
+		return ErrDeviceRevoked
+	case entry.RotatedTo != nil:
+		return ErrKeyRotated
+	case !sameDevice(renewal.ClusterHeadId, entry.ClusterHeadId):
+		return fmt.Errorf("%w: cluster head mismatch", ErrInvalidRenewal)
+	case !bytes.Equal(renewal.PrevBlockHash, entry.BlockHash):
+		return fmt.Errorf("%w: previous block hash mismatch", ErrInvalidRenewal)
+	}
+
+	return nil
+}
+
+// verifyValidity verifies the requested validity period against the policy maximum.
+func (n *Node) verifyValidity(validity uint64) error {
+	maxValidity := uint64(n.cfg.Authentication.MaxValidity.Seconds())
+
+	switch {
+	case maxValidity == 0:
+		return nil
+	case validity == 0:
+		return fmt.Errorf("%w: unlimited validity is not allowed", ErrInvalidValidity)
+	case validity > maxValidity:
+		return fmt.Errorf("%w: %d seconds exceeds policy maximum of %d seconds", ErrInvalidValidity, validity, maxValidity)
 	}
 
 	return nil
@@ -505,9 +1033,11 @@ func (n *Node) addPeer(ctx context.Context, peer *Peer) error {
 	ctx, logger := n.logger.StartTrace(ctx, "add peer")
 	defer logger.FinishTrace()
 
+	clusterHeadID := n.getClusterHeadDeviceID()
+
 	switch {
 	case bytes.Equal(peer.ClusterHeadID, n.deviceID):
-		if err := n.db.Update(func(tx *nutsdb.Tx) error {
+		if err := n.db.Update(func(tx storage.Tx) error {
 			data, err := proto.Marshal(peer.ToProto())
 			if err != nil {
 				return err
@@ -519,14 +1049,16 @@ func (n *Node) addPeer(ctx context.Context, peer *Peer) error {
 			return err
 		}
 
+		n.peersMutex.Lock()
 		if n.childrenNodes == nil {
 			n.childrenNodes = NewPeers(peer)
 		} else {
 			n.childrenNodes.Add(peer)
 		}
+		n.peersMutex.Unlock()
 
-	case bytes.Equal(peer.DeviceID, n.getClusterHeadDeviceID()):
-		if err := n.db.Update(func(tx *nutsdb.Tx) error {
+	case bytes.Equal(peer.DeviceID, clusterHeadID):
+		if err := n.db.Update(func(tx storage.Tx) error {
 			data, err := proto.Marshal(peer.ToProto())
 			if err != nil {
 				return err
@@ -538,10 +1070,10 @@ func (n *Node) addPeer(ctx context.Context, peer *Peer) error {
 			return err
 		}
 
-		n.clusterHead = peer
+		n.setClusterHead(peer)
 
-	case bytes.Equal(peer.ClusterHeadID, n.getClusterHeadDeviceID()):
-		if err := n.db.Update(func(tx *nutsdb.Tx) error {
+	case bytes.Equal(peer.ClusterHeadID, clusterHeadID):
+		if err := n.db.Update(func(tx storage.Tx) error {
 			data, err := proto.Marshal(peer.ToProto())
 			if err != nil {
 				return err
@@ -553,11 +1085,13 @@ func (n *Node) addPeer(ctx context.Context, peer *Peer) error {
 			return err
 		}
 
+		n.peersMutex.Lock()
 		if n.clusterNodes == nil {
 			n.clusterNodes = NewPeers(peer)
 		} else {
 			n.clusterNodes.Add(peer)
 		}
+		n.peersMutex.Unlock()
 
 	default:
 		logger.Errorf("invalid peer %s", peer.Name)
@@ -567,17 +1101,17 @@ func (n *Node) addPeer(ctx context.Context, peer *Peer) error {
 	return nil
 }
 
-// initPeer initializes a peer from the database.
-func initPeer(ctx context.Context, db *nutsdb.DB, bucket string, key []byte) (*Peer, error) {
+// loadPeer loads a peer from the database and initializes its client.
+func (n *Node) loadPeer(ctx context.Context, bucket string, key []byte) (*Peer, error) {
 	var peer types.Peer
 
-	if err := db.View(func(tx *nutsdb.Tx) error {
+	if err := n.db.View(func(tx storage.Tx) error {
 		data, err := tx.Get(bucket, key)
 		if err != nil {
 			return err
 		}
 
-		if err = proto.Unmarshal(data.Value, &peer); err != nil {
+		if err = proto.Unmarshal(data, &peer); err != nil {
 			return err
 		}
 
@@ -586,7 +1120,7 @@ func initPeer(ctx context.Context, db *nutsdb.DB, bucket string, key []byte) (*P
 		return nil, err
 	}
 
-	client, err := initClient(ctx, peer.GrpcAddress)
+	client, err := n.initClient(ctx, peer.GrpcAddress, peer.DeviceId)
 	if err != nil {
 		return nil, err
 	}
@@ -594,11 +1128,11 @@ func initPeer(ctx context.Context, db *nutsdb.DB, bucket string, key []byte) (*P
 	return NewPeer(peer.Name, peer.DeviceId, peer.ClusterHeadId, peer.GrpcAddress, peer.Level, client), nil
 }
 
-// initPeers initializes peers from the database.
-func initPeers(ctx context.Context, db *nutsdb.DB, bucket string) (*Peers, error) {
+// loadPeers loads peers from the database and initializes their clients.
+func (n *Node) loadPeers(ctx context.Context, bucket string) (*Peers, error) {
 	peers := NewPeers()
 
-	if err := db.View(func(tx *nutsdb.Tx) error {
+	if err := n.db.View(func(tx storage.Tx) error {
 		entries, err := tx.GetAll(bucket)
 		if err != nil {
 			return err
@@ -610,7 +1144,7 @@ func initPeers(ctx context.Context, db *nutsdb.DB, bucket string) (*Peers, error
 				return err
 			}
 
-			client, err := initClient(ctx, peer.GrpcAddress)
+			client, err := n.initClient(ctx, peer.GrpcAddress, peer.DeviceId)
 			if err != nil {
 				return err
 			}
@@ -628,5 +1162,5 @@ func initPeers(ctx context.Context, db *nutsdb.DB, bucket string) (*Peers, error
 
 // bucketAuthTableLevel returns the name of the bucket that will store authentication table by level.
 func bucketAuthTableLevel(level uint32) string {
-	return fmt.Sprintf("%s %d", types.BucketAuthenticationTable, level)
+	return types.BucketAuthenticationTableLevel(level)
 }
@@ -0,0 +1,151 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package node
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"fmt"
+
+	"authentication-chains/internal/cipher"
+	"authentication-chains/internal/storage"
+	"authentication-chains/internal/types"
+)
+
+// getPublicKey returns the public key of the device from the key bucket. A key which is not known yet is requested
+// from the peer with the device id or from the cluster head, the fingerprint guarantees that they can't substitute the key.
+func (n *Node) getPublicKey(ctx context.Context, deviceID []byte) (crypto.PublicKey, error) {
+	ctx, logger := n.logger.StartTrace(ctx, "get public key")
+	defer logger.FinishTrace()
+
+	if bytes.Equal(deviceID, n.deviceID) {
+		return n.cipher.GetPublicKey(), nil
+	}
+
+	// ids of the previous versions are public keys themselves
+	if publicKey, err := cipher.DeserializePublicKey(deviceID); err == nil {
+		return publicKey, nil
+	}
+
+	if publicKey, err := n.getStoredPublicKey(deviceID); err == nil {
+		return publicKey, nil
+	}
+
+	peer := n.getPeer(deviceID)
+	if peer == nil {
+		peer = n.getClusterHead()
+	}
+
+	if peer == nil {
+		return nil, fmt.Errorf("device %x: %w", deviceID, ErrNotFoundKey)
+	}
+
+	response, err := peer.Client.GetPublicKey(ctx, &types.PublicKeyRequest{DeviceId: deviceID})
+	if err != nil {
+		logger.Errorf("get public key from node %s: %s", peer.Name, err)
+		return nil, fmt.Errorf("device %x: %w", deviceID, ErrNotFoundKey)
+	}
+
+	publicKey, err := cipher.DeserializePublicKey(response.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(cipher.Fingerprint(publicKey), deviceID) {
+		return nil, fmt.Errorf("device %x: %w", deviceID, cipher.ErrFingerprintMismatch)
+	}
+
+	if err = n.db.Update(func(tx storage.Tx) error {
+		return tx.Put(types.BucketKeys, deviceID, response.PublicKey, types.InfinityTTL)
+	}); err != nil {
+		logger.Errorf("put public key: %s", err)
+	}
+
+	return publicKey, nil
+}
+
+// getBlockPublicKey returns the public key of the device carried by the DARs of the block
+// or falls back to the known keys.
+func (n *Node) getBlockPublicKey(ctx context.Context, block *types.Block, deviceID []byte) (crypto.PublicKey, error) {
+	return n.getBranchPublicKey(ctx, []*types.Block{block}, deviceID)
+}
+
+// getBranchPublicKey returns the public key of the device carried by the DARs or the rotations of the blocks
+// or falls back to the known keys, so devices registered by the branch are known before it's applied.
+func (n *Node) getBranchPublicKey(ctx context.Context, blocks []*types.Block, deviceID []byte) (crypto.PublicKey, error) {
+	for _, block := range blocks {
+		for _, dar := range block.Dars {
+			if !bytes.Equal(dar.DeviceId, deviceID) && !bytes.Equal(cipher.DeviceID(dar.DeviceId), deviceID) {
+				continue
+			}
+
+			if publicKey, err := cipher.DARPublicKey(dar); err == nil {
+				return publicKey, nil
+			}
+		}
+
+		for _, rotation := range block.Rotations {
+			if !bytes.Equal(rotation.NewDeviceId, deviceID) {
+				continue
+			}
+
+			if publicKey, err := cipher.RotationPublicKey(rotation); err == nil {
+				return publicKey, nil
+			}
+		}
+	}
+
+	return n.getPublicKey(ctx, deviceID)
+}
+
+// getStoredPublicKey returns the public key of the device from the key bucket.
+func (n *Node) getStoredPublicKey(deviceID []byte) (crypto.PublicKey, error) {
+	var publicKey crypto.PublicKey
+
+	if err := n.db.View(func(tx storage.Tx) error {
+		data, err := tx.Get(types.BucketKeys, deviceID)
+		if err != nil {
+			return err
+		}
+
+		publicKey, err = cipher.DeserializePublicKey(data)
+
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return publicKey, nil
+}
+
+// getPeer returns the known peer with the device id or nil.
+func (n *Node) getPeer(deviceID []byte) *Peer {
+	if clusterHead := n.getClusterHead(); clusterHead != nil && bytes.Equal(clusterHead.DeviceID, deviceID) {
+		return clusterHead
+	}
+
+	for _, peers := range []*Peers{n.getClusterNodes(), n.getChildrenNodes()} {
+		if peers == nil {
+			continue
+		}
+
+		if peer := peers.Get(deviceID); peer != nil {
+			return peer
+		}
+	}
+
+	return nil
+}
+
+// putDARKey stores the public key carried by the DAR in the key bucket.
+func putDARKey(tx storage.Tx, dar *types.DeviceAuthenticationRequest) error {
+	publicKey, err := cipher.DARPublicKey(dar)
+	if err != nil {
+		return err
+	}
+
+	return tx.Put(types.BucketKeys, cipher.Fingerprint(publicKey), cipher.SerializePublicKey(publicKey), types.InfinityTTL)
+}
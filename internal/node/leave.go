@@ -0,0 +1,468 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package node
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"authentication-chains/internal/cipher"
+	"authentication-chains/internal/storage"
+	"authentication-chains/internal/types"
+)
+
+// Leave deregisters the stopped node from the network. The children of the node are handed off to the first
+// cluster node which answers, it becomes their cluster head, and the other peers forget the node.
+// The children are told about the successor only once it adopted them, otherwise they elect their cluster head.
+// The authentication of the node is revoked first if requested, while its cluster still votes for it.
+func (n *Node) Leave(ctx context.Context, revoke bool) error {
+	ctx, logger := n.logger.StartTrace(ctx, "leave")
+	defer logger.FinishTrace()
+
+	if revoke {
+		if err := n.revokeNode(ctx); err != nil {
+			logger.Errorf("revoke node: %s", err)
+			return err
+		}
+	}
+
+	successor := n.findSuccessor(ctx)
+
+	request, err := n.newDeregistration(ctx, successor)
+	if err != nil {
+		logger.Errorf("create deregistration: %s", err)
+		return err
+	}
+
+	// the successor adopts the children before they switch to it
+	if successor != nil {
+		if err = n.deregisterFrom(ctx, successor, request); err != nil {
+			logger.Errorf("hand off children to node %s: %s", successor.Name, err)
+
+			if request, err = n.newDeregistration(ctx, nil); err != nil {
+				logger.Errorf("create deregistration: %s", err)
+				return err
+			}
+
+			successor = nil
+		}
+	}
+
+	var peers []*Peer
+
+	childrenNodes := n.getChildrenNodes()
+
+	if childrenNodes != nil && successor != nil {
+		peers = append(peers, childrenNodes.GetAll()...)
+	}
+
+	if clusterHead := n.getClusterHead(); clusterHead != nil {
+		peers = append(peers, clusterHead)
+	}
+
+	if clusterNodes := n.getClusterNodes(); clusterNodes != nil {
+		for _, peer := range clusterNodes.GetAll() {
+			if peer != successor {
+				peers = append(peers, peer)
+			}
+		}
+	}
+
+	for _, peer := range peers {
+		if err = n.deregisterFrom(ctx, peer, request); err != nil {
+			logger.Errorf("deregister from node %s: %s", peer.Name, err)
+		}
+	}
+
+	if childrenNodes != nil && successor == nil {
+		logger.Infof("there is no successor, children elect their cluster head once the node stops answering")
+	}
+
+	logger.Infof("node left the network")
+
+	return nil
+}
+
+// deregisterFrom sends the deregistration to the peer and closes the connection to it once the peer accepted it.
+func (n *Node) deregisterFrom(ctx context.Context, peer *Peer, request *types.NodeDeregistrationRequest) error {
+	ctx, logger := n.logger.StartTrace(ctx, "deregister from node "+peer.Name)
+	defer logger.FinishTrace()
+
+	if _, err := peer.Client.DeregisterNode(ctx, request); err != nil {
+		return err
+	}
+
+	if err := peer.Close(); err != nil {
+		logger.Errorf("close connection: %s", err)
+	}
+
+	return nil
+}
+
+// revokeNode mines the revocation of the authentication of the node signed by the node itself.
+func (n *Node) revokeNode(ctx context.Context) error {
+	revocation := &types.DeviceRevocationRequest{
+		DeviceId:      n.deviceID,
+		ClusterHeadId: n.getClusterHeadDeviceID(),
+	}
+
+	if err := n.cipher.SignRevocation(revocation); err != nil {
+		return err
+	}
+
+	if err := n.verifyRevocation(ctx, revocation, n.getLevel()); err != nil {
+		return err
+	}
+
+	_, err := n.mineBlock(ctx, types.Transactions{Revocations: []*types.DeviceRevocationRequest{revocation}})
+
+	return err
+}
+
+// findSuccessor returns the first cluster node which answers, it's nil if the node has no children to hand off.
+func (n *Node) findSuccessor(ctx context.Context) *Peer {
+	ctx, logger := n.logger.StartTrace(ctx, "find successor")
+	defer logger.FinishTrace()
+
+	childrenNodes, clusterNodes := n.getChildrenNodes(), n.getClusterNodes()
+	if childrenNodes == nil || len(childrenNodes.GetAll()) == 0 || clusterNodes == nil {
+		return nil
+	}
+
+	for _, peer := range clusterNodes.GetAll() {
+		statusCtx, cancel := context.WithTimeout(ctx, n.cfg.Heartbeat.Timeout)
+		_, err := peer.Client.GetStatus(statusCtx, &types.StatusRequest{})
+		cancel()
+
+		if err != nil {
+			logger.Debugw("cluster node can't be the successor", "peer", peer.Name, "error", err)
+			continue
+		}
+
+		return peer
+	}
+
+	return nil
+}
+
+// newDeregistration creates the deregistration request of the node which hands off its children to the successor.
+func (n *Node) newDeregistration(ctx context.Context, successor *Peer) (*types.NodeDeregistrationRequest, error) {
+	nonce := make([]byte, verificationNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	request := &types.NodeDeregistrationRequest{
+		Nonce:     nonce,
+		Timestamp: time.Now().Unix(),
+	}
+
+	if successor != nil {
+		request.Successor = successor.ToProto()
+		request.Children = n.getChildrenNodes().ToProto()
+
+		entries, err := n.getChildrenEntries(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		request.ChildrenEntries = entries
+		request.ChildrenGenesisHash = n.childrenGenesisHash()
+	}
+
+	if err := n.cipher.SignDeregistration(request); err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// getChildrenEntries returns the authentication entries of the level of the children.
+func (n *Node) getChildrenEntries(ctx context.Context) ([]*types.AuthenticationEntry, error) {
+	// a node of level 0 has no children to hand off
+	level, err := n.childrenLevel()
+	if err != nil {
+		return nil, nil
+	}
+
+	var entries []*types.AuthenticationEntry
+
+	if err = n.db.View(func(tx storage.Tx) error {
+		data, err := tx.GetAll(bucketAuthTableLevel(level))
+		if err != nil {
+			return err
+		}
+
+		for _, entryData := range data {
+			var entry types.AuthenticationEntry
+			if err = proto.Unmarshal(entryData.Value, &entry); err != nil {
+				return err
+			}
+
+			entries = append(entries, &entry)
+		}
+
+		return nil
+	}); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// deregisterNode forgets the leaving peer. Children of the leaving cluster head switch to its successor
+// and the successor adopts the children of the leaving cluster node.
+func (n *Node) deregisterNode(ctx context.Context, request *types.NodeDeregistrationRequest) error {
+	ctx, logger := n.logger.StartTrace(ctx, "deregister node")
+	defer logger.FinishTrace()
+
+	bucket, peer := n.getPeerBucket(request.DeviceId)
+	if peer == nil {
+		return fmt.Errorf("device %x: %w", request.DeviceId, ErrUnknownPeer)
+	}
+
+	publicKey, err := n.getPublicKey(ctx, request.DeviceId)
+	if err != nil {
+		return err
+	}
+
+	if err = cipher.VerifyDeregistration(request, publicKey); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidDeregistration, err)
+	}
+
+	if err = n.replay.check(request.DeviceId, request.Nonce, request.Timestamp); err != nil {
+		return err
+	}
+
+	switch bucket {
+	case types.BucketClusterHead:
+		if request.Successor == nil {
+			logger.Infof("cluster head %s leaves without successor, it's replaced by the election", peer.Name)
+			return nil
+		}
+
+		if request.Successor.Level != peer.Level {
+			return fmt.Errorf("%w: successor level %d isn't the cluster head level", ErrInvalidDeregistration, request.Successor.Level)
+		}
+
+		client, err := n.initClient(ctx, request.Successor.GrpcAddress, request.Successor.DeviceId)
+		if err != nil {
+			return err
+		}
+
+		head := NewPeer(
+			request.Successor.Name,
+			request.Successor.DeviceId,
+			request.Successor.ClusterHeadId,
+			request.Successor.GrpcAddress,
+			request.Successor.Level,
+			client,
+		)
+
+		if err = n.switchClusterHead(ctx, head); err != nil {
+			return err
+		}
+
+		logger.Infof("cluster head %s left, node %s is the cluster head now", peer.Name, head.Name)
+
+	default:
+		if err = n.evictPeer(ctx, bucket, peer); err != nil {
+			return err
+		}
+
+		logger.Infof("peer %s left", peer.Name)
+
+		if bucket == types.BucketClusterNodes && request.Successor != nil && n.sameDevice(request.Successor.DeviceId, n.deviceID) {
+			return n.adoptChildren(ctx, request)
+		}
+	}
+
+	return nil
+}
+
+// adoptChildren makes the children of the leaving cluster node the children of the node
+// and keeps their authentication entries, the entries known to the node win.
+// The entries must match the ones replayed from the chain of the children.
+func (n *Node) adoptChildren(ctx context.Context, request *types.NodeDeregistrationRequest) error {
+	ctx, logger := n.logger.StartTrace(ctx, "adopt children")
+	defer logger.FinishTrace()
+
+	level, err := n.childrenLevel()
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidDeregistration, err)
+	}
+
+	children := make([]*Peer, 0, len(request.Children))
+
+	for _, child := range request.Children {
+		client, err := n.initClient(ctx, child.GrpcAddress, child.DeviceId)
+		if err != nil {
+			logger.Errorf("init child client: %s", err)
+			return err
+		}
+
+		children = append(children, NewPeer(child.Name, child.DeviceId, n.deviceID, child.GrpcAddress, child.Level, client))
+	}
+
+	if err = n.checkChildrenEntries(ctx, request, children, level); err != nil {
+		logger.Errorf("check children entries: %s", err)
+		return err
+	}
+
+	bucket := bucketAuthTableLevel(level)
+
+	if err = n.db.Update(func(tx storage.Tx) error {
+		for _, entry := range request.ChildrenEntries {
+			if _, err := tx.Get(bucket, entry.DeviceId); err == nil {
+				continue
+			}
+
+			data, err := proto.Marshal(entry)
+			if err != nil {
+				return err
+			}
+
+			if err = tx.Put(bucket, entry.DeviceId, data, types.InfinityTTL); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		logger.Errorf("put children entries: %s", err)
+		return err
+	}
+
+	for _, child := range children {
+		if err = n.addPeer(ctx, child); err != nil {
+			return err
+		}
+	}
+
+	logger.Infof("%d children and %d entries are adopted", len(request.Children), len(request.ChildrenEntries))
+
+	return nil
+}
+
+// checkChildrenEntries replays the chain of the children into the memory storage
+// and checks that the entries are the same as the replayed ones. The chain must start from the genesis hash
+// the leaving node signed and its blocks must be approved by the leaving node and its children.
+func (n *Node) checkChildrenEntries(ctx context.Context, request *types.NodeDeregistrationRequest, children []*Peer, level uint32) error {
+	entries := request.ChildrenEntries
+	if len(entries) == 0 {
+		return nil
+	}
+
+	if len(children) == 0 {
+		return fmt.Errorf("%w: entries without children", ErrInvalidDeregistration)
+	}
+
+	status, err := children[0].Client.GetStatus(ctx, &types.StatusRequest{})
+	if err != nil {
+		return err
+	}
+
+	if status.LastBlockIndex == 0 {
+		return fmt.Errorf("%w: children chain is empty", ErrInvalidDeregistration)
+	}
+
+	blocks, err := n.fetchBlocks(ctx, children, 0, 1, status.LastBlockIndex)
+	if err != nil {
+		return err
+	}
+
+	// the validators are the leaving node and its children, like the ones of the children blocks the node commits
+	voters := [][]byte{request.DeviceId}
+	for _, child := range children {
+		voters = append(voters, child.DeviceID)
+	}
+
+	return storage.NewMemory().Update(func(tx storage.Tx) error {
+		for i, block := range blocks {
+			hash, err := cipher.HashBlock(block)
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case !bytes.Equal(hash, block.Hash):
+				return fmt.Errorf("%w: hash mismatch of children block %d", ErrInvalidDeregistration, block.Index)
+			case i == 0 && !bytes.Equal(block.PrevHash, request.ChildrenGenesisHash):
+				return fmt.Errorf("%w: children chain doesn't start from the children genesis hash", ErrInvalidDeregistration)
+			case i > 0 && !bytes.Equal(block.PrevHash, blocks[i-1].Hash):
+				return fmt.Errorf("%w: children block %d doesn't follow the previous one", ErrInvalidDeregistration, block.Index)
+			}
+
+			if err = n.verifyChildrenQuorum(ctx, blocks[:i+1], block, voters); err != nil {
+				return fmt.Errorf("%w: children block %d: %s", ErrInvalidDeregistration, block.Index, err)
+			}
+
+			if err = putAuthenticationEntry(tx, block, level); err != nil {
+				return err
+			}
+		}
+
+		for _, entry := range entries {
+			replayed, err := getEntry(tx, level, entry.DeviceId)
+			if err != nil {
+				return fmt.Errorf("%w: entry of device %x has no block", ErrInvalidDeregistration, entry.DeviceId)
+			}
+
+			if !proto.Equal(replayed, entry) {
+				return fmt.Errorf("%w: entry of device %x differs from its blocks", ErrInvalidDeregistration, entry.DeviceId)
+			}
+		}
+
+		return nil
+	})
+}
+
+// verifyChildrenQuorum verifies that the children block is approved by the quorum of its validators
+// and the validators are among the voters. Keys of the children registered by the chain are taken from its blocks.
+func (n *Node) verifyChildrenQuorum(ctx context.Context, blocks []*types.Block, block *types.Block, voters [][]byte) error {
+	validators := blockValidators(block)
+
+	for _, id := range validators {
+		if !containsID(voters, id) {
+			return fmt.Errorf("%w: unknown validator %x", ErrBlockValidation, id)
+		}
+	}
+
+	if err := n.verifyBranchVotes(ctx, blocks, block); err != nil {
+		return err
+	}
+
+	_, err := n.quorumApprovals(block, validators)
+
+	return err
+}
+
+// getPeerBucket returns the known peer of the device and the bucket it's stored in.
+func (n *Node) getPeerBucket(deviceID []byte) (string, *Peer) {
+	if clusterHead := n.getClusterHead(); clusterHead != nil && bytes.Equal(clusterHead.DeviceID, deviceID) {
+		return types.BucketClusterHead, clusterHead
+	}
+
+	for bucket, peers := range map[string]*Peers{
+		types.BucketClusterNodes:  n.getClusterNodes(),
+		types.BucketChildrenNodes: n.getChildrenNodes(),
+	} {
+		if peers == nil {
+			continue
+		}
+
+		if peer := peers.Get(deviceID); peer != nil {
+			return bucket, peer
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,323 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package node
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"authentication-chains/internal/cipher"
+	"authentication-chains/internal/config"
+	"authentication-chains/internal/storage"
+	"authentication-chains/internal/types"
+)
+
+// Sizes of the random values of mailboxes.
+const (
+	mailboxChallengeSize = 32
+	mailboxMessageIDSize = 16
+)
+
+// mailboxPolicy returns the mailbox policy of the node level.
+func (n *Node) mailboxPolicy() config.MailboxPolicy {
+	if policy, ok := n.cfg.Mailbox.Levels[n.getLevel()]; ok {
+		return policy
+	}
+
+	return n.cfg.Mailbox.Default
+}
+
+// storeMessage keeps the message in the mailbox of the receiver, the sender and the receiver must be authenticated
+// devices and the receiver must be of the cluster of the node. The message stays encrypted for the receiver.
+func (n *Node) storeMessage(ctx context.Context, message *types.Message) (*types.Message, error) {
+	ctx, logger := n.logger.StartTrace(ctx, "store message")
+	logger = logger.WithFields("receiver_id", fmt.Sprintf("%x", message.ReceiverId))
+	defer logger.FinishTrace()
+
+	if !n.isAuthenticated(ctx, message.SenderId) {
+		return nil, fmt.Errorf("%w: device %x isn't authenticated", ErrInvalidMessageSender, message.SenderId)
+	}
+
+	if !n.isAuthenticated(ctx, message.ReceiverId) {
+		return nil, fmt.Errorf("%w: device %x isn't authenticated", ErrUnreachableReceiver, message.ReceiverId)
+	}
+
+	now := time.Now()
+
+	// ids start with the receiving time, so the messages of the mailbox are ordered by it
+	id := make([]byte, mailboxMessageIDSize)
+	binary.BigEndian.PutUint64(id, uint64(now.UnixNano()))
+
+	if _, err := rand.Read(id[8:]); err != nil {
+		return nil, err
+	}
+
+	value, err := proto.Marshal(&types.MailboxMessage{
+		Id: id,
+		Message: &types.Message{
+			SenderId:        message.SenderId,
+			ReceiverId:      message.ReceiverId,
+			Data:            message.Data,
+			EnvelopeVersion: message.EnvelopeVersion,
+			Signature:       message.Signature,
+		},
+		ReceivedAt: now.Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	policy := n.mailboxPolicy()
+
+	if err = n.db.Update(func(tx storage.Tx) error {
+		stored, err := mailboxEntries(tx, message.ReceiverId)
+		if err != nil {
+			return err
+		}
+
+		size := len(value)
+		for _, entry := range stored {
+			size += len(entry.Value)
+		}
+
+		fromSender, err := countSenderMessages(stored, message.SenderId)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case len(stored) >= policy.MaxMessages:
+			return fmt.Errorf("%w: %d messages", ErrMailboxFull, len(stored))
+		case policy.MaxBytes > 0 && size > policy.MaxBytes:
+			return fmt.Errorf("%w: %d bytes exceed %d", ErrMailboxFull, size, policy.MaxBytes)
+		case policy.MaxSenderMessages > 0 && fromSender >= policy.MaxSenderMessages:
+			return fmt.Errorf("%w: %d messages of sender %x", ErrMailboxFull, fromSender, message.SenderId)
+		}
+
+		return tx.Put(types.BucketMailbox, mailboxKey(message.ReceiverId, id), value, uint32(policy.TTL.Seconds()))
+	}); err != nil {
+		logger.Errorf("put message: %s", err)
+		return nil, err
+	}
+
+	logger.Infof("message is stored in the mailbox, ttl %s", policy.TTL)
+
+	return &types.Message{
+		SenderId:   n.deviceID,
+		ReceiverId: message.SenderId,
+		Stored:     true,
+	}, nil
+}
+
+// mailboxHolder returns the peer the mailbox requests of the device are forwarded to. Messages to the device
+// go the way of nextHop to the node which keeps them, so the mailbox requests follow the same way.
+// It's nil if the node keeps the mailbox itself, the messages to a peer are never kept.
+func (n *Node) mailboxHolder(ctx context.Context, deviceID []byte) (*Peer, error) {
+	peer, err := n.nextHop(ctx, deviceID)
+	if err != nil || peer == nil || n.sameDevice(peer.DeviceID, deviceID) {
+		return nil, err
+	}
+
+	return peer, nil
+}
+
+// verifyMessageSender verifies that the sender of the message is an authenticated device which signed its envelope.
+func (n *Node) verifyMessageSender(ctx context.Context, message *types.Message) error {
+	if !n.isAuthenticated(ctx, message.SenderId) {
+		return fmt.Errorf("%w: device %x isn't authenticated", ErrInvalidMessageSender, message.SenderId)
+	}
+
+	publicKey, err := n.getPublicKey(ctx, message.SenderId)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidMessageSender, err)
+	}
+
+	if err = cipher.VerifyMessage(message, publicKey); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidMessageSender, err)
+	}
+
+	return nil
+}
+
+// issueMailboxChallenge issues the challenge the device signs its mailbox request over.
+// Challenges are stored, so they're issued to authenticated devices only and their number is bounded.
+func (n *Node) issueMailboxChallenge(ctx context.Context, deviceID []byte) (*types.MailboxChallengeResponse, error) {
+	if !n.isAuthenticated(ctx, deviceID) {
+		return nil, fmt.Errorf("%w: device %x isn't authenticated", ErrInvalidMailboxRequest, deviceID)
+	}
+
+	if err := n.challenges.take(deviceID); err != nil {
+		return nil, err
+	}
+
+	challenge := make([]byte, mailboxChallengeSize)
+	if _, err := rand.Read(challenge); err != nil {
+		return nil, err
+	}
+
+	ttl := n.cfg.Mailbox.ChallengeTTL
+
+	if err := n.db.Update(func(tx storage.Tx) error {
+		return tx.Put(types.BucketChallenges, challenge, deviceID, uint32(ttl.Seconds()))
+	}); err != nil {
+		return nil, err
+	}
+
+	return &types.MailboxChallengeResponse{
+		Challenge: challenge,
+		ExpiresAt: time.Now().Add(ttl).Unix(),
+	}, nil
+}
+
+// fetchMessages returns the messages of the mailbox of the device which signed the request.
+func (n *Node) fetchMessages(ctx context.Context, request *types.FetchMessagesRequest) ([]*types.MailboxMessage, error) {
+	err := n.verifyMailboxRequest(ctx, request.DeviceId, request.Challenge, func(publicKey crypto.PublicKey) error {
+		return cipher.VerifyFetchMessages(request, publicKey)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []*types.MailboxMessage
+
+	if err = n.db.View(func(tx storage.Tx) error {
+		entries, err := mailboxEntries(tx, request.DeviceId)
+		if err != nil {
+			return err
+		}
+
+		if request.Limit > 0 && len(entries) > int(request.Limit) {
+			entries = entries[:request.Limit]
+		}
+
+		messages = make([]*types.MailboxMessage, 0, len(entries))
+
+		for _, entry := range entries {
+			message := new(types.MailboxMessage)
+			if err = proto.Unmarshal(entry.Value, message); err != nil {
+				return err
+			}
+
+			messages = append(messages, message)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// ackMessages removes the messages from the mailbox of the device which signed the request
+// and returns the number of removed messages. Unknown and expired messages are skipped.
+func (n *Node) ackMessages(ctx context.Context, request *types.AckMessagesRequest) (uint32, error) {
+	err := n.verifyMailboxRequest(ctx, request.DeviceId, request.Challenge, func(publicKey crypto.PublicKey) error {
+		return cipher.VerifyAckMessages(request, publicKey)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var removed uint32
+
+	if err = n.db.Update(func(tx storage.Tx) error {
+		for _, id := range request.MessageIds {
+			if len(id) != mailboxMessageIDSize {
+				return fmt.Errorf("%w: invalid message id %x", ErrInvalidMailboxRequest, id)
+			}
+
+			if err := tx.Delete(types.BucketMailbox, mailboxKey(request.DeviceId, id)); err != nil {
+				if errors.Is(err, storage.ErrNotFound) {
+					continue
+				}
+
+				return err
+			}
+
+			removed++
+		}
+
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	return removed, nil
+}
+
+// verifyMailboxRequest verifies that the mailbox request is signed by the authenticated device over the challenge
+// issued to it. The challenge is used up.
+func (n *Node) verifyMailboxRequest(ctx context.Context, deviceID, challenge []byte, verify func(crypto.PublicKey) error) error {
+	if !n.isAuthenticated(ctx, deviceID) {
+		return fmt.Errorf("%w: device %x isn't authenticated", ErrInvalidMailboxRequest, deviceID)
+	}
+
+	publicKey, err := n.getPublicKey(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+
+	if err = verify(publicKey); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidMailboxRequest, err)
+	}
+
+	return n.db.Update(func(tx storage.Tx) error {
+		owner, err := tx.Get(types.BucketChallenges, challenge)
+		if err != nil || !bytes.Equal(owner, deviceID) {
+			return fmt.Errorf("%w: unknown or expired challenge", ErrInvalidMailboxRequest)
+		}
+
+		return tx.Delete(types.BucketChallenges, challenge)
+	})
+}
+
+// mailboxEntries returns the stored messages of the device ordered by the receiving time.
+func mailboxEntries(tx storage.Tx, deviceID []byte) ([]storage.Entry, error) {
+	entries, err := tx.RangeScan(
+		types.BucketMailbox,
+		mailboxKey(deviceID, bytes.Repeat([]byte{0x00}, mailboxMessageIDSize)),
+		mailboxKey(deviceID, bytes.Repeat([]byte{0xff}, mailboxMessageIDSize)),
+	)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil
+	}
+
+	return entries, err
+}
+
+// countSenderMessages returns the number of the stored messages sent by the device.
+func countSenderMessages(entries []storage.Entry, senderID []byte) (int, error) {
+	var count int
+
+	for _, entry := range entries {
+		var message types.MailboxMessage
+		if err := proto.Unmarshal(entry.Value, &message); err != nil {
+			return 0, err
+		}
+
+		if bytes.Equal(message.Message.GetSenderId(), senderID) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// mailboxKey returns the key of the message in the mailbox bucket, the keys of the device share its id as a prefix.
+func mailboxKey(deviceID, messageID []byte) []byte {
+	key := make([]byte, 0, len(deviceID)+len(messageID))
+	key = append(key, deviceID...)
+
+	return append(key, messageID...)
+}
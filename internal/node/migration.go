@@ -0,0 +1,131 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package node
+
+import (
+	"errors"
+
+	"google.golang.org/protobuf/proto"
+
+	"authentication-chains/internal/cipher"
+	"authentication-chains/internal/storage"
+	"authentication-chains/internal/types"
+)
+
+// migrateDeviceIDs rekeys authentication tables and peers stored by PEM public keys of the previous versions
+// to fingerprints and moves the public keys to the key bucket. It runs once, the migration is marked in the indexes.
+func migrateDeviceIDs(db storage.Storage, level uint32) error {
+	return db.Update(func(tx storage.Tx) error {
+		if _, err := tx.Get(types.BucketIndexes, types.KeyDeviceIDs); err == nil {
+			return nil
+		}
+
+		for i := int32(level); i >= 0; i-- {
+			if err := migrateEntries(tx, uint32(i)); err != nil {
+				return err
+			}
+		}
+
+		for _, bucket := range []string{types.BucketClusterHead, types.BucketClusterNodes, types.BucketChildrenNodes} {
+			if err := migratePeers(tx, bucket); err != nil {
+				return err
+			}
+		}
+
+		return tx.Put(types.BucketIndexes, types.KeyDeviceIDs, []byte{1}, types.InfinityTTL)
+	})
+}
+
+// migrateEntries rekeys authentication entries of the level.
+func migrateEntries(tx storage.Tx, level uint32) error {
+	records, err := tx.GetAll(bucketAuthTableLevel(level))
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		publicKey, err := cipher.DeserializePublicKey(record.Key)
+		if err != nil {
+			continue
+		}
+
+		var entry types.AuthenticationEntry
+		if err = proto.Unmarshal(record.Value, &entry); err != nil {
+			return err
+		}
+
+		entry.DeviceId = cipher.Fingerprint(publicKey)
+		entry.ClusterHeadId = cipher.DeviceID(entry.ClusterHeadId)
+
+		if err = tx.Put(types.BucketKeys, entry.DeviceId, record.Key, types.InfinityTTL); err != nil {
+			return err
+		}
+
+		if err = tx.Delete(bucketAuthTableLevel(level), record.Key); err != nil {
+			return err
+		}
+
+		if err = putEntry(tx, level, &entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// migratePeers rewrites device ids of the peers of the bucket, peers stored by device ids are rekeyed.
+func migratePeers(tx storage.Tx, bucket string) error {
+	records, err := tx.GetAll(bucket)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		var peer types.Peer
+		if err = proto.Unmarshal(record.Value, &peer); err != nil {
+			return err
+		}
+
+		publicKey, err := cipher.DeserializePublicKey(peer.DeviceId)
+		if err != nil {
+			continue
+		}
+
+		if err = tx.Put(types.BucketKeys, cipher.Fingerprint(publicKey), peer.DeviceId, types.InfinityTTL); err != nil {
+			return err
+		}
+
+		key := record.Key
+		if bucket != types.BucketClusterHead {
+			if err = tx.Delete(bucket, record.Key); err != nil {
+				return err
+			}
+
+			key = cipher.Fingerprint(publicKey)
+		}
+
+		peer.DeviceId = cipher.Fingerprint(publicKey)
+		peer.ClusterHeadId = cipher.DeviceID(peer.ClusterHeadId)
+
+		data, err := proto.Marshal(&peer)
+		if err != nil {
+			return err
+		}
+
+		if err = tx.Put(bucket, key, data, types.InfinityTTL); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -5,15 +5,20 @@
 package node
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/DirusK/utils/log"
 	"github.com/alitto/pond"
-	"github.com/nutsdb/nutsdb"
 
 	"authentication-chains/internal/blockchain"
 	"authentication-chains/internal/cipher"
 	"authentication-chains/internal/config"
+	"authentication-chains/internal/storage"
 	"authentication-chains/internal/types"
 )
 
@@ -25,49 +30,161 @@ type (
 		cfg        config.Node
 		cipher     cipher.Cipher
 		chain      blockchain.Blockchain
-		db         *nutsdb.DB
+		memPool    blockchain.MemPool
+		db         storage.Storage
 		logger     log.Logger
 		workerPool *pond.WorkerPool
+		replay     *replayCache
+		challenges *issueLimiter
+		nonces     *issueLimiter
 
-		deviceID []byte
+		deviceID   []byte
+		passphrase []byte
 
 		genesisBlockHash []byte
-		authBlockHash    []byte
 
+		// authMutex guards the hash of the block which authenticates the node,
+		// it's replaced once the node renews its authentication or rotates its key.
+		authMutex     sync.RWMutex
+		authBlockHash []byte
+
+		// peersMutex guards the level, the cluster head and the peer sets of the node,
+		// they are replaced once the node takes over the failed cluster head or switches to a new one.
+		peersMutex    sync.RWMutex
 		clusterHead   *Peer
 		clusterNodes  *Peers
 		childrenNodes *Peers
+
+		// upperClusterHead is the cluster head of the cluster head, the node registers in its cluster
+		// if it takes over the failed cluster head.
+		upperClusterHead *types.Peer
+
+		miningMutex   sync.Mutex
+		producerMutex sync.Mutex
+		// producing is set while the block production triggered by the full mem-pool is pending.
+		producing     atomic.Bool
+		reorgMutex    sync.Mutex
+		electionMutex sync.Mutex
 	}
 )
 
-// New creates a new node instance.
-func New(ctx context.Context, cfg config.Node, db *nutsdb.DB, workerPool *pond.WorkerPool, logger log.Logger) (*Node, error) {
+// New creates a new node instance, the passphrase encrypts the node private key in the storage.
+func New(ctx context.Context, cfg config.Node, db storage.Storage, passphrase []byte, workerPool *pond.WorkerPool, logger log.Logger) (*Node, error) {
 	chain, err := blockchain.New(db)
 	if err != nil {
 		return nil, err
 	}
 
-	cipher, err := cipher.New(db)
+	cipher, err := cipher.New(db, passphrase)
 	if err != nil {
 		return nil, err
 	}
 
-	clusterHead, _ := initPeer(ctx, db, types.BucketClusterHead, types.KeyClusterHead)
-	clusterNodes, _ := initPeers(ctx, db, types.BucketClusterNodes)
-	childrenNodes, _ := initPeers(ctx, db, types.BucketChildrenNodes)
-
-	return &Node{
-		cfg:           cfg,
-		cipher:        cipher,
-		chain:         chain,
-		db:            db,
-		logger:        logger,
-		workerPool:    workerPool,
-		deviceID:      cipher.SerializePublicKey(),
-		clusterHead:   clusterHead,
-		clusterNodes:  clusterNodes,
-		childrenNodes: childrenNodes,
-	}, nil
+	if cipher, err = loadRotatedCipher(db, cipher, passphrase); err != nil {
+		return nil, err
+	}
+
+	// the level of the node is promoted once it takes over the failed cluster head
+	level, upperClusterHead, err := loadElection(db)
+	if err != nil {
+		return nil, err
+	}
+
+	if level != nil {
+		cfg.Level = *level
+	}
+
+	if err = migrateDeviceIDs(db, cfg.Level); err != nil {
+		return nil, err
+	}
+
+	if err = failOrphanedTickets(db, cfg.MemPool.TicketTTL); err != nil {
+		return nil, err
+	}
+
+	n := &Node{
+		cfg:        cfg,
+		cipher:     cipher,
+		chain:      chain,
+		memPool:    blockchain.NewMemPool(),
+		db:         db,
+		logger:     logger,
+		workerPool: workerPool,
+		replay:     newReplayCache(cfg.Replay.Skew, cfg.Replay.CacheSize),
+		challenges: newIssueLimiter(cfg.Mailbox.ChallengeTTL, cfg.Mailbox.MaxChallenges),
+		nonces:     newIssueLimiter(cfg.Registration.NonceTTL, cfg.Registration.MaxNonces),
+		deviceID:   cipher.DeviceID(),
+		passphrase: passphrase,
+
+		upperClusterHead: upperClusterHead,
+	}
+
+	n.clusterHead, _ = n.loadPeer(ctx, types.BucketClusterHead, types.KeyClusterHead)
+	n.clusterNodes, _ = n.loadPeers(ctx, types.BucketClusterNodes)
+	n.childrenNodes, _ = n.loadPeers(ctx, types.BucketChildrenNodes)
+
+	return n, nil
+}
+
+// Cipher returns the current key of the node, it signs the certificate of the node in the pinned TLS mode.
+func (n *Node) Cipher() cipher.Cipher {
+	return n.cipher
+}
+
+// getLevel returns the level of the node, it's promoted once the node takes over the cluster head.
+func (n *Node) getLevel() uint32 {
+	n.peersMutex.RLock()
+	defer n.peersMutex.RUnlock()
+
+	return n.cfg.Level
+}
+
+// getClusterHead returns the cluster head of the node, it's nil if the node is the root.
+func (n *Node) getClusterHead() *Peer {
+	n.peersMutex.RLock()
+	defer n.peersMutex.RUnlock()
+
+	return n.clusterHead
+}
+
+// setClusterHead replaces the cluster head of the node.
+func (n *Node) setClusterHead(peer *Peer) {
+	n.peersMutex.Lock()
+	defer n.peersMutex.Unlock()
+
+	n.clusterHead = peer
+}
+
+// getAuthBlockHash returns the hash of the block which authenticates the node.
+func (n *Node) getAuthBlockHash() []byte {
+	n.authMutex.RLock()
+	defer n.authMutex.RUnlock()
+
+	return n.authBlockHash
+}
+
+// setAuthBlockHash replaces the hash of the block which authenticates the node.
+func (n *Node) setAuthBlockHash(hash []byte) {
+	n.authMutex.Lock()
+	defer n.authMutex.Unlock()
+
+	n.authBlockHash = hash
+}
+
+// getClusterNodes returns the cluster nodes of the node, it's nil if the node has no cluster nodes yet.
+func (n *Node) getClusterNodes() *Peers {
+	n.peersMutex.RLock()
+	defer n.peersMutex.RUnlock()
+
+	return n.clusterNodes
+}
+
+// getChildrenNodes returns the children of the node, it's nil if the node has no children yet.
+func (n *Node) getChildrenNodes() *Peers {
+	n.peersMutex.RLock()
+	defer n.peersMutex.RUnlock()
+
+	return n.childrenNodes
 }
 
 // Init initializes the node.
@@ -98,27 +215,56 @@ func (n *Node) Sync(ctx context.Context) {
 	ctx, logger := n.logger.StartTrace(ctx, "sync")
 	defer logger.FinishTrace()
 
-	if n.clusterNodes == nil {
+	clusterNodes := n.getClusterNodes()
+	if clusterNodes == nil {
 		return
 	}
 
 	lastBlock := n.chain.GetLastBlock()
 
-	for _, peer := range n.clusterNodes.GetAll() {
+	// peers which have the longest chain are the sources of blocks
+	var (
+		target  *types.StatusResponse
+		sources []*Peer
+		peers   = clusterNodes.GetAll()
+	)
+
+	// the healthiest peers go first, so blocks are fetched from them first
+	SortByScore(peers)
+
+	for _, peer := range peers {
 		status, err := peer.Client.GetStatus(ctx, &types.StatusRequest{})
 		if err != nil {
 			logger.Errorf("get status from peer %s: %s", peer.Name, err)
 			continue
 		}
 
-		if status.LastBlockIndex > lastBlock.Index {
-			logger.Infof("node sync with peer %s from %d block to %d block", peer.Name, lastBlock.Index+1, status.LastBlockIndex)
-
-			if err = n.syncBlocks(ctx, peer, lastBlock.Index+1, status.LastBlockIndex); err != nil {
-				continue
-			}
+		switch {
+		case target == nil || status.LastBlockIndex > target.LastBlockIndex:
+			target, sources = status, []*Peer{peer}
+		case status.LastBlockIndex == target.LastBlockIndex && bytes.Equal(status.LastBlockHash, target.LastBlockHash):
+			sources = append(sources, peer)
 		}
 	}
+
+	if target == nil || target.LastBlockIndex < lastBlock.Index ||
+		target.LastBlockIndex == lastBlock.Index && bytes.Equal(target.LastBlockHash, lastBlock.Hash) {
+		return
+	}
+
+	// the peers may have mined a competing branch, so blocks are synced from the last shared one
+	forkPoint, err := n.findForkPoint(ctx, sources[0], min(lastBlock.Index, target.LastBlockIndex))
+	if err != nil {
+		logger.Errorf("find fork point with peer %s: %s", sources[0].Name, err)
+		return
+	}
+
+	// an interrupted sync kept the written blocks, so the fork point is the last of them
+	logger.Infof("node sync with %d peers from %d block to %d block", len(sources), forkPoint+1, target.LastBlockIndex)
+
+	if err = n.syncBlocks(ctx, sources, forkPoint+1, target.LastBlockIndex); err != nil {
+		logger.Errorf("sync blocks: %s", err)
+	}
 }
 
 // Explore explores the cluster for new nodes.
@@ -126,11 +272,12 @@ func (n *Node) Explore(ctx context.Context) {
 	ctx, logger := n.logger.StartTrace(ctx, "explore")
 	defer logger.FinishTrace()
 
-	if n.clusterHead == nil {
+	clusterHead := n.getClusterHead()
+	if clusterHead == nil {
 		return
 	}
 
-	peers, err := n.clusterHead.Client.GetPeers(ctx, &types.PeersRequest{Level: n.cfg.Level})
+	peers, err := clusterHead.Client.GetPeers(ctx, &types.PeersRequest{Level: n.getLevel()})
 	if err != nil {
 		logger.Errorf("get peers from cluster head: %s", err)
 		return
@@ -141,7 +288,12 @@ func (n *Node) Explore(ctx context.Context) {
 			continue
 		}
 
-		client, err := initClient(ctx, peer.GrpcAddress)
+		// the known peer keeps its connection and health
+		if known := n.getPeer(peer.DeviceId); known != nil && known.GRPCAddress == peer.GrpcAddress {
+			continue
+		}
+
+		client, err := n.initClient(ctx, peer.GrpcAddress, peer.DeviceId)
 		if err != nil {
 			logger.Errorf("init client for %s: %s", peer.Name, err)
 			continue
@@ -154,3 +306,116 @@ func (n *Node) Explore(ctx context.Context) {
 		}
 	}
 }
+
+// ProduceBlock mines a block from device authentication requests waiting in the mem-pool.
+func (n *Node) ProduceBlock(ctx context.Context) {
+	ctx, logger := n.logger.StartTrace(ctx, "produce block")
+	defer logger.FinishTrace()
+
+	n.producerMutex.Lock()
+	defer n.producerMutex.Unlock()
+
+	dars := n.memPool.GetBatch(n.cfg.MemPool.BlockSize)
+	if len(dars) == 0 {
+		return
+	}
+
+	logger.Infof("mine block with %d device authentication requests", len(dars))
+
+	block, err := n.mineBlock(ctx, types.Transactions{DARs: dars})
+	switch {
+	case err == nil:
+		for _, dar := range dars {
+			n.resolveDAR(ctx, dar, block)
+		}
+
+		return
+	case !errors.Is(err, ErrBlockValidation):
+		// the validators didn't reject the block, so the requests wait in the mem-pool for the next block
+		logger.Errorf("mine block: %s, requests stay pending", err)
+		return
+	case len(dars) == 1:
+		logger.Errorf("mine block: %s", err)
+		n.resolveDAR(ctx, dars[0], nil)
+
+		return
+	}
+
+	// one invalid request fails the whole block, so the requests are mined one by one to reject only the invalid ones
+	logger.Errorf("mine block: %s, mining requests one by one", err)
+
+	for _, dar := range dars {
+		block, err = n.mineBlock(ctx, types.Transactions{DARs: []*types.DeviceAuthenticationRequest{dar}})
+		if err != nil && !errors.Is(err, ErrBlockValidation) {
+			logger.Errorf("mine block of device %x: %s, request stays pending", dar.DeviceId, err)
+			continue
+		}
+
+		if err != nil {
+			logger.Errorf("mine block of device %x: %s", dar.DeviceId, err)
+		}
+
+		n.resolveDAR(ctx, dar, block)
+	}
+}
+
+// resolveDAR removes the device authentication request from the mem-pool and updates its ticket,
+// the request is accepted if it's mined in the block and rejected if the block is nil.
+func (n *Node) resolveDAR(ctx context.Context, dar *types.DeviceAuthenticationRequest, block *types.Block) {
+	ctx, logger := n.logger.StartTrace(ctx, "resolve dar")
+	defer logger.FinishTrace()
+
+	n.memPool.Remove(dar)
+
+	ticket := &types.DeviceAuthenticationResponse{
+		Ticket: darTicket(dar),
+		Status: types.DARStatus_DAR_STATUS_REJECTED,
+	}
+
+	if block != nil {
+		ticket.Status = types.DARStatus_DAR_STATUS_ACCEPTED
+		ticket.BlockHash = block.Hash
+	}
+
+	if err := n.putTicket(ctx, ticket); err != nil {
+		logger.Errorf("put ticket %x: %s", ticket.Ticket, err)
+	}
+}
+
+// RenewAuthentication renews the node authentication before it expires.
+func (n *Node) RenewAuthentication(ctx context.Context) {
+	ctx, logger := n.logger.StartTrace(ctx, "renew authentication")
+	defer logger.FinishTrace()
+
+	entry, err := n.getAuthenticationEntry(ctx, n.deviceID)
+	if err != nil {
+		logger.Errorf("get authentication entry: %s", err)
+		return
+	}
+
+	if entry.NotAfter == 0 || time.Until(time.Unix(entry.NotAfter, 0)) > n.cfg.Authentication.RenewBefore {
+		return
+	}
+
+	renewal := &types.DeviceRenewalRequest{
+		DeviceId:      n.deviceID,
+		ClusterHeadId: entry.ClusterHeadId,
+		PrevBlockHash: entry.BlockHash,
+		Validity:      uint64(n.cfg.Authentication.MaxValidity.Seconds()),
+	}
+
+	if err = n.cipher.SignRenewal(renewal); err != nil {
+		logger.Errorf("sign renewal: %s", err)
+		return
+	}
+
+	block, err := n.mineBlock(ctx, types.Transactions{Renewals: []*types.DeviceRenewalRequest{renewal}})
+	if err != nil {
+		logger.Errorf("mine block: %s", err)
+		return
+	}
+
+	n.setAuthBlockHash(block.Hash)
+
+	logger.Infof("authentication is renewed till %s", time.Unix(block.NotAfter(renewal.Validity), 0))
+}
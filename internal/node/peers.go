@@ -5,11 +5,27 @@
 package node
 
 import (
+	"bytes"
+	"io"
+	"sort"
 	"sync"
+	"time"
+
+	"google.golang.org/grpc"
 
 	"authentication-chains/internal/types"
 )
 
+// Liveness states of peers.
+const (
+	// PeerAlive is the state of the peer which answers heartbeats.
+	PeerAlive PeerState = "alive"
+	// PeerSuspect is the state of the peer which missed several heartbeats in a row.
+	PeerSuspect PeerState = "suspect"
+	// PeerEvicted is the state of the peer which is removed from the known nodes.
+	PeerEvicted PeerState = "evicted"
+)
+
 type (
 	// Peers is a list of known nodes.
 	Peers struct {
@@ -25,6 +41,28 @@ type (
 		ClusterHeadID []byte
 		Level         uint32
 		Client        types.NodeClient
+
+		// health is shared by the copies of the peer, so a peer with a rotated key keeps its history.
+		health *peerHealth
+	}
+
+	// nodeClient is the client of the node which owns its connection, so the connection is closed
+	// when the peer is evicted.
+	nodeClient struct {
+		types.NodeClient
+		conn *grpc.ClientConn
+	}
+
+	// PeerState is the liveness state of the peer.
+	PeerState string
+
+	// peerHealth is the liveness of the peer tracked by heartbeats.
+	peerHealth struct {
+		mutex    sync.Mutex
+		state    PeerState
+		lastSeen time.Time
+		latency  time.Duration
+		failures int
 	}
 )
 
@@ -37,9 +75,99 @@ func NewPeer(name string, deviceID, clusterHeadID []byte, GRPCAddress string, le
 		Client:        client,
 		GRPCAddress:   GRPCAddress,
 		Level:         level,
+		health:        &peerHealth{state: PeerAlive},
 	}
 }
 
+// RecordSuccess records the heartbeat answered by the peer with the latency, the peer is alive again.
+func (p *Peer) RecordSuccess(latency time.Duration) {
+	p.health.mutex.Lock()
+	defer p.health.mutex.Unlock()
+
+	p.health.state = PeerAlive
+	p.health.lastSeen = time.Now()
+	p.health.latency = latency
+	p.health.failures = 0
+}
+
+// RecordFailure records the heartbeat missed by the peer and returns the number of failures in a row.
+func (p *Peer) RecordFailure() int {
+	p.health.mutex.Lock()
+	defer p.health.mutex.Unlock()
+
+	p.health.failures++
+
+	return p.health.failures
+}
+
+// Failures returns the number of heartbeats missed by the peer in a row.
+func (p *Peer) Failures() int {
+	p.health.mutex.Lock()
+	defer p.health.mutex.Unlock()
+
+	return p.health.failures
+}
+
+// SetState sets the liveness state of the peer.
+func (p *Peer) SetState(state PeerState) {
+	p.health.mutex.Lock()
+	defer p.health.mutex.Unlock()
+
+	p.health.state = state
+}
+
+// State returns the liveness state of the peer.
+func (p *Peer) State() PeerState {
+	p.health.mutex.Lock()
+	defer p.health.mutex.Unlock()
+
+	return p.health.state
+}
+
+// LastSeen returns the time of the last heartbeat answered by the peer, it's zero if there is no such heartbeat.
+func (p *Peer) LastSeen() time.Time {
+	p.health.mutex.Lock()
+	defer p.health.mutex.Unlock()
+
+	return p.health.lastSeen
+}
+
+// Score ranks the peer by its health. It goes from 1 for an alive peer which answers instantly
+// down to 0 with latency and failures, suspect peers are halved.
+func (p *Peer) Score() float64 {
+	p.health.mutex.Lock()
+	defer p.health.mutex.Unlock()
+
+	score := 1 / (1 + p.health.latency.Seconds()) / float64(1+p.health.failures)
+
+	if p.health.state != PeerAlive {
+		score /= 2
+	}
+
+	return score
+}
+
+// Close closes the connection to the peer.
+func (p *Peer) Close() error {
+	if closer, ok := p.Client.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+// Close closes the connection to the node.
+func (c *nodeClient) Close() error {
+	return c.conn.Close()
+}
+
+// SortByScore sorts the peers from the healthiest one.
+func SortByScore(peers []*Peer) {
+	sort.SliceStable(peers, func(i, j int) bool {
+		return peers[i].Score() > peers[j].Score()
+	})
+}
+
 func (p Peer) ToProto() *types.Peer {
 	return &types.Peer{
 		Name:          p.Name,
@@ -94,6 +222,35 @@ func (p *Peers) ToProto() []*types.Peer {
 	return peers
 }
 
+// Remove removes the peer with the device id from the list and returns it, it's nil if there is no such peer.
+func (p *Peers) Remove(deviceID []byte) *Peer {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for i, peer := range p.Peers {
+		if bytes.Equal(peer.DeviceID, deviceID) {
+			p.Peers = append(p.Peers[:i], p.Peers[i+1:]...)
+			return peer
+		}
+	}
+
+	return nil
+}
+
+// Get returns the peer with the device id or nil if there is no such peer.
+func (p *Peers) Get(deviceID []byte) *Peer {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	for _, peer := range p.Peers {
+		if bytes.Equal(peer.DeviceID, deviceID) {
+			return peer
+		}
+	}
+
+	return nil
+}
+
 // Exists checks if a peer exists in the list.
 func (p *Peers) Exists(peer *Peer) bool {
 	p.mutex.RLock()
@@ -0,0 +1,172 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package node
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"authentication-chains/internal/cipher"
+	"authentication-chains/internal/storage"
+	"authentication-chains/internal/types"
+)
+
+// Sizes of the random values issued for node registrations.
+const (
+	registrationNonceSize = 32
+	joinTokenSize         = 16
+)
+
+// CreateJoinToken creates the join token which approves the registration of a new node.
+// The token is valid for one registration during the ttl, zero ttl means the token doesn't expire.
+func (n *Node) CreateJoinToken(ctx context.Context, ttl time.Duration) (string, error) {
+	ctx, logger := n.logger.StartTrace(ctx, "create join token")
+	defer logger.FinishTrace()
+
+	token := make([]byte, joinTokenSize)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+
+	encoded := hex.EncodeToString(token)
+
+	// only the hash is stored, so the token can't be taken from the storage
+	if err := n.db.Update(func(tx storage.Tx) error {
+		return tx.Put(types.BucketJoinTokens, cipher.Hash([]byte(encoded)), []byte(time.Now().UTC().Format(time.RFC3339)), uint32(ttl.Seconds()))
+	}); err != nil {
+		logger.Errorf("put join token: %s", err)
+		return "", err
+	}
+
+	logger.Infof("join token is created, ttl %s", ttl)
+
+	return encoded, nil
+}
+
+// issueRegistrationNonce issues the nonce the registering node signs its request over.
+// The registering node isn't known yet, so the number of nonces stored for it is bounded.
+func (n *Node) issueRegistrationNonce(deviceID []byte) (*types.RegistrationNonceResponse, error) {
+	if err := n.nonces.take(deviceID); err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, registrationNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	ttl := n.cfg.Registration.NonceTTL
+
+	if err := n.db.Update(func(tx storage.Tx) error {
+		return tx.Put(types.BucketNonces, nonce, deviceID, uint32(ttl.Seconds()))
+	}); err != nil {
+		return nil, err
+	}
+
+	return &types.RegistrationNonceResponse{
+		Nonce:     nonce,
+		ExpiresAt: time.Now().Add(ttl).Unix(),
+	}, nil
+}
+
+// verifyRegistration verifies that the registration is signed by the key of the node over the nonce issued to it
+// and the node is either authenticated in the cluster or approved by a join token. The nonce and the token are used up.
+func (n *Node) verifyRegistration(ctx context.Context, request *types.NodeRegistrationRequest) error {
+	ctx, logger := n.logger.StartTrace(ctx, "verify registration")
+	defer logger.FinishTrace()
+
+	if err := cipher.VerifyRegistration(request); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidRegistration, err)
+	}
+
+	if !n.sameDevice(request.Node.ClusterHeadId, n.deviceID) {
+		return fmt.Errorf("%w: cluster head mismatch", ErrInvalidRegistration)
+	}
+
+	if err := n.db.Update(func(tx storage.Tx) error {
+		deviceID, err := tx.Get(types.BucketNonces, request.Nonce)
+		if err != nil || !bytes.Equal(deviceID, request.Node.DeviceId) {
+			return fmt.Errorf("%w: unknown or expired nonce", ErrInvalidRegistration)
+		}
+
+		return tx.Delete(types.BucketNonces, request.Nonce)
+	}); err != nil {
+		return err
+	}
+
+	if n.isAuthenticated(ctx, request.Node.DeviceId) {
+		return nil
+	}
+
+	if request.JoinToken == "" {
+		return ErrRegistrationDenied
+	}
+
+	if err := n.db.Update(func(tx storage.Tx) error {
+		key := cipher.Hash([]byte(request.JoinToken))
+
+		if _, err := tx.Get(types.BucketJoinTokens, key); err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				return fmt.Errorf("%w: unknown or expired join token", ErrRegistrationDenied)
+			}
+
+			return err
+		}
+
+		return tx.Delete(types.BucketJoinTokens, key)
+	}); err != nil {
+		return err
+	}
+
+	logger.Infof("join token is used by node %s", request.Node.Name)
+
+	return nil
+}
+
+// isAuthenticated checks if the device has a valid entry in the authentication tables of the node level and below.
+func (n *Node) isAuthenticated(ctx context.Context, deviceID []byte) bool {
+	entry, _, err := n.findAuthenticationEntry(ctx, deviceID)
+	if err != nil {
+		return false
+	}
+
+	return !entry.Revoked && entry.RotatedTo == nil && (entry.NotAfter == 0 || time.Now().Unix() <= entry.NotAfter)
+}
+
+// newRegistration creates the registration request of the node signed over the nonce issued by the cluster head.
+func (n *Node) newRegistration(ctx context.Context) (*types.NodeRegistrationRequest, error) {
+	clusterHead := n.getClusterHead()
+	if clusterHead == nil {
+		return nil, fmt.Errorf("%w: cluster head", ErrUnknownPeer)
+	}
+
+	nonce, err := clusterHead.Client.GetRegistrationNonce(ctx, &types.RegistrationNonceRequest{DeviceId: n.deviceID})
+	if err != nil {
+		return nil, err
+	}
+
+	request := &types.NodeRegistrationRequest{
+		Node: &types.Peer{
+			Name:          n.cfg.Name,
+			Level:         n.getLevel(),
+			DeviceId:      n.deviceID,
+			ClusterHeadId: clusterHead.DeviceID,
+			GrpcAddress:   n.cfg.GRPC.Address,
+		},
+		Nonce:     nonce.Nonce,
+		JoinToken: n.cfg.Registration.JoinToken,
+	}
+
+	if err = n.cipher.SignRegistration(request); err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
@@ -0,0 +1,235 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package node
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"fmt"
+	"sync"
+	"time"
+
+	"authentication-chains/internal/cipher"
+	"authentication-chains/internal/types"
+)
+
+// verificationNonceSize is the size of the nonce of the device verification request.
+const verificationNonceSize = 16
+
+type (
+	// replayCache keeps the nonces of the recent requests of every sender within the skew window.
+	// Older requests are rejected by their timestamps, so their nonces are dropped.
+	replayCache struct {
+		mutex   sync.Mutex
+		skew    time.Duration
+		size    int
+		senders map[string][]seenNonce
+	}
+
+	// seenNonce is the nonce of the request and the timestamp of the request.
+	seenNonce struct {
+		nonce     []byte
+		timestamp time.Time
+	}
+
+	// issueLimiter bounds the number of unexpired nonces and challenges issued to every device,
+	// so the requests for them don't fill the storage.
+	issueLimiter struct {
+		mutex   sync.Mutex
+		ttl     time.Duration
+		size    int
+		devices map[string][]time.Time
+	}
+)
+
+func newReplayCache(skew time.Duration, size int) *replayCache {
+	return &replayCache{
+		skew:    skew,
+		size:    size,
+		senders: make(map[string][]seenNonce),
+	}
+}
+
+// check verifies that the timestamp is within the skew window and the nonce of the sender isn't seen yet,
+// then the nonce is remembered. The cache of the sender is bounded, so it's an error to exceed it.
+func (r *replayCache) check(senderID, nonce []byte, timestamp int64) error {
+	now := time.Now()
+	at := time.Unix(timestamp, 0)
+
+	switch {
+	case len(nonce) == 0:
+		return fmt.Errorf("%w: nonce is missing", ErrReplayedRequest)
+	case at.Before(now.Add(-r.skew)) || at.After(now.Add(r.skew)):
+		return fmt.Errorf("%w: timestamp %s is out of the %s window", ErrStaleRequest, at.UTC().Format(time.DateTime), r.skew)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	seen := r.senders[string(senderID)]
+
+	for _, s := range seen {
+		if bytes.Equal(s.nonce, nonce) {
+			return ErrReplayedRequest
+		}
+	}
+
+	fresh := r.expire(seen, now)
+	if len(fresh) >= r.size {
+		return fmt.Errorf("%w: %d requests within the %s window", ErrReplayCacheFull, len(fresh), r.skew)
+	}
+
+	r.senders[string(senderID)] = append(fresh, seenNonce{nonce: nonce, timestamp: at})
+
+	// senders which are silent for the window are dropped once there are too many of them
+	if len(r.senders) > r.size {
+		for sender, nonces := range r.senders {
+			if nonces = r.expire(nonces, now); len(nonces) == 0 {
+				delete(r.senders, sender)
+			} else {
+				r.senders[sender] = nonces
+			}
+		}
+	}
+
+	return nil
+}
+
+// expire drops the nonces of the requests out of the skew window.
+func (r *replayCache) expire(seen []seenNonce, now time.Time) []seenNonce {
+	fresh := seen[:0]
+
+	for _, s := range seen {
+		if !s.timestamp.Before(now.Add(-r.skew)) {
+			fresh = append(fresh, s)
+		}
+	}
+
+	return fresh
+}
+
+func newIssueLimiter(ttl time.Duration, size int) *issueLimiter {
+	return &issueLimiter{
+		ttl:     ttl,
+		size:    size,
+		devices: make(map[string][]time.Time),
+	}
+}
+
+// take counts the issue to the device, it's an error if the device has too many unexpired ones.
+func (l *issueLimiter) take(deviceID []byte) error {
+	now := time.Now()
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	issued := l.expire(l.devices[string(deviceID)], now)
+	if len(issued) >= l.size {
+		return fmt.Errorf("%w: %d issued within %s", ErrTooManyIssued, len(issued), l.ttl)
+	}
+
+	l.devices[string(deviceID)] = append(issued, now)
+
+	// devices which have nothing unexpired are dropped once there are too many of them
+	if len(l.devices) > l.size {
+		for device, issued := range l.devices {
+			if issued = l.expire(issued, now); len(issued) == 0 {
+				delete(l.devices, device)
+			} else {
+				l.devices[device] = issued
+			}
+		}
+	}
+
+	return nil
+}
+
+// expire drops the issues which are expired.
+func (l *issueLimiter) expire(issued []time.Time, now time.Time) []time.Time {
+	fresh := issued[:0]
+
+	for _, at := range issued {
+		if now.Sub(at) < l.ttl {
+			fresh = append(fresh, at)
+		}
+	}
+
+	return fresh
+}
+
+// verifyContent verifies that the content is signed by the sender of the message, bound to the devices
+// of the message and not replayed.
+func (n *Node) verifyContent(content *types.Content, message *types.Message, publicKey crypto.PublicKey) error {
+	switch {
+	case !bytes.Equal(content.SenderId, message.SenderId):
+		return fmt.Errorf("%w: sender mismatch", ErrInvalidContent)
+	case !bytes.Equal(content.ReceiverId, n.deviceID):
+		return fmt.Errorf("%w: receiver mismatch", ErrInvalidContent)
+	}
+
+	if err := cipher.VerifyContent(content, publicKey); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidContent, err)
+	}
+
+	return n.replay.check(content.SenderId, content.Nonce, content.Timestamp)
+}
+
+// verifyDeviceByClusterHead asks the cluster head to verify the device. The request is signed by the node
+// and the response must be signed by the cluster head for the nonce of the request, so it can't be replayed.
+func (n *Node) verifyDeviceByClusterHead(ctx context.Context, deviceID, blockHash []byte) (*types.VerifyDeviceResponse, error) {
+	clusterHead := n.getClusterHead()
+	if clusterHead == nil {
+		return nil, fmt.Errorf("%w: node has no cluster head", ErrVerification)
+	}
+
+	nonce := make([]byte, verificationNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	request := &types.VerifyDeviceRequest{
+		DeviceId:  deviceID,
+		BlockHash: blockHash,
+		Timestamp: time.Now().Unix(),
+		Nonce:     nonce,
+	}
+
+	if err := n.cipher.SignDeviceVerification(request); err != nil {
+		return nil, err
+	}
+
+	response, err := clusterHead.Client.VerifyDevice(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case !bytes.Equal(response.ReplyTo, nonce):
+		return nil, fmt.Errorf("%w: response is made for another request", ErrVerification)
+	case !bytes.Equal(response.DeviceId, deviceID) || !bytes.Equal(response.BlockHash, blockHash):
+		return nil, fmt.Errorf("%w: response is made for another device", ErrVerification)
+	case !n.sameDevice(response.SignerId, clusterHead.DeviceID):
+		return nil, fmt.Errorf("%w: response isn't signed by the cluster head", ErrVerification)
+	}
+
+	publicKey, err := n.getPublicKey(ctx, response.SignerId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = cipher.VerifyDeviceVerificationResult(response, publicKey); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrVerification, err)
+	}
+
+	if response.IsVerified {
+		if err = n.auditHops(ctx, response.Hops, deviceID, blockHash, response.SignerId); err != nil {
+			return nil, err
+		}
+	}
+
+	return response, nil
+}
@@ -0,0 +1,343 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package node
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+
+	"authentication-chains/internal/cipher"
+	"authentication-chains/internal/storage"
+	"authentication-chains/internal/types"
+)
+
+// maxLineage limits the walk over the rotated keys of the device.
+const maxLineage = 64
+
+// RotateNodeKey replaces the key of the node with a new one of the algorithm. The new key is stored as pending,
+// the rotation is mined as a block signed by both keys, then the pending key replaces the stored one
+// and the peers are notified to update the device id of the node.
+func (n *Node) RotateNodeKey(ctx context.Context, algorithm cipher.Algorithm) error {
+	ctx, logger := n.logger.StartTrace(ctx, "rotate node key")
+	defer logger.FinishTrace()
+
+	entry, err := n.getAuthenticationEntry(ctx, n.deviceID)
+	if err != nil {
+		logger.Errorf("get authentication entry: %s", err)
+		return err
+	}
+
+	next, err := cipher.Generate(algorithm)
+	if err != nil {
+		return err
+	}
+
+	rotation := &types.DeviceKeyRotationRequest{
+		ClusterHeadId: n.getClusterHeadDeviceID(),
+		PrevBlockHash: entry.BlockHash,
+		Validity:      uint64(n.cfg.Authentication.MaxValidity.Seconds()),
+	}
+
+	if err = n.cipher.SignRotation(rotation, next); err != nil {
+		logger.Errorf("sign rotation: %s", err)
+		return err
+	}
+
+	if err = n.verifyRotation(ctx, rotation, n.getLevel()); err != nil {
+		logger.Errorf("verify rotation: %s", err)
+		return err
+	}
+
+	// the new key is kept before the rotation is mined, so it isn't lost if the node stops once the block is accepted
+	if err = cipher.SavePending(n.db, next, n.passphrase); err != nil {
+		logger.Errorf("save pending cipher: %s", err)
+		return err
+	}
+
+	block, err := n.mineBlock(ctx, types.Transactions{Rotations: []*types.DeviceKeyRotationRequest{rotation}})
+	if err != nil {
+		logger.Errorf("mine block: %s", err)
+		return err
+	}
+
+	if err = cipher.PromotePending(n.db); err != nil {
+		logger.Errorf("promote pending cipher: %s", err)
+		return err
+	}
+
+	n.cipher = next
+	n.deviceID = next.DeviceID()
+	n.setAuthBlockHash(block.Hash)
+
+	n.notifyKeyRotation(ctx, rotation)
+
+	logger.Infof("key is rotated from %x to %x", rotation.DeviceId, rotation.NewDeviceId)
+
+	return nil
+}
+
+// loadRotatedCipher returns the pending key of the node if the chain has rotated the current key to it
+// while the node stopped before storing the new key, the pending key replaces the stored one then.
+func loadRotatedCipher(db storage.Storage, current cipher.Cipher, passphrase []byte) (cipher.Cipher, error) {
+	pending, err := cipher.LoadPending(db, passphrase)
+	if errors.Is(err, storage.ErrNotFound) {
+		return current, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var rotated bool
+
+	if err = db.View(func(tx storage.Tx) error {
+		next, err := tx.Get(types.BucketRotations, current.DeviceID())
+		rotated = err == nil && bytes.Equal(next, pending.DeviceID())
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if !rotated {
+		return current, nil
+	}
+
+	if err = cipher.PromotePending(db); err != nil {
+		return nil, err
+	}
+
+	return pending, nil
+}
+
+// notifyKeyRotation sends the rotation of the node key to all its peers.
+func (n *Node) notifyKeyRotation(ctx context.Context, rotation *types.DeviceKeyRotationRequest) {
+	ctx, logger := n.logger.StartTrace(ctx, "notify key rotation")
+	defer logger.FinishTrace()
+
+	var peers []*Peer
+
+	if clusterHead := n.getClusterHead(); clusterHead != nil {
+		peers = append(peers, clusterHead)
+	}
+
+	for _, nodes := range []*Peers{n.getClusterNodes(), n.getChildrenNodes()} {
+		if nodes != nil {
+			peers = append(peers, nodes.GetAll()...)
+		}
+	}
+
+	for _, peer := range peers {
+		if _, err := peer.Client.RotatePeerKey(ctx, rotation); err != nil {
+			logger.Errorf("notify node %s: %s", peer.Name, err)
+		}
+	}
+}
+
+// verifyRotation verifies that the rotation points at the current authentication block of the device,
+// the new key is not registered yet and the rotation is signed by both keys.
+func (n *Node) verifyRotation(ctx context.Context, rotation *types.DeviceKeyRotationRequest, level uint32) error {
+	ctx, logger := n.logger.StartTrace(ctx, "verify rotation")
+	defer logger.FinishTrace()
+
+	entry, err := n.getLevelAuthenticationEntry(ctx, rotation.DeviceId, level)
+	if err != nil {
+		return ErrNotFoundDevice
+	}
+
+	if err = checkRotation(rotation, entry, n.sameDevice); err != nil {
+		return err
+	}
+
+	if _, err = n.getLevelAuthenticationEntry(ctx, rotation.NewDeviceId, level); err == nil {
+		return fmt.Errorf("%w: new key is already registered", ErrInvalidRotation)
+	}
+
+	if err = n.verifyValidity(rotation.Validity); err != nil {
+		return err
+	}
+
+	publicKey, err := n.getPublicKey(ctx, rotation.DeviceId)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidRotation, err)
+	}
+
+	if err = cipher.VerifyRotation(rotation, publicKey); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidRotation, err)
+	}
+
+	return nil
+}
+
+// checkRotation checks that the rotation points at the current authentication block of the device entry,
+// sameDevice tells if the ids belong to the same device.
+func checkRotation(rotation *types.DeviceKeyRotationRequest, entry *types.AuthenticationEntry, sameDevice func(deviceID, otherID []byte) bool) error {
+	switch {
+	case entry.Revoked:
+		return ErrDeviceRevoked
+	case entry.RotatedTo != nil:
+		return ErrKeyRotated
+	case !sameDevice(rotation.ClusterHeadId, entry.ClusterHeadId):
+		return fmt.Errorf("%w: cluster head mismatch", ErrInvalidRotation)
+	case !bytes.Equal(rotation.PrevBlockHash, entry.BlockHash):
+		return fmt.Errorf("%w: previous block hash mismatch", ErrInvalidRotation)
+	}
+
+	return nil
+}
+
+// rotatePeerKey replaces the rotated device id of the peer and the cluster head id of the peers in its cluster.
+func (n *Node) rotatePeerKey(ctx context.Context, deviceID, newDeviceID []byte) error {
+	ctx, logger := n.logger.StartTrace(ctx, "rotate peer key")
+	defer logger.FinishTrace()
+
+	rotate := func(peer *Peer) (*Peer, bool) {
+		updated := *peer
+
+		if bytes.Equal(updated.DeviceID, deviceID) {
+			updated.DeviceID = newDeviceID
+		}
+
+		if bytes.Equal(updated.ClusterHeadID, deviceID) {
+			updated.ClusterHeadID = newDeviceID
+		}
+
+		return &updated, !bytes.Equal(updated.DeviceID, peer.DeviceID) || !bytes.Equal(updated.ClusterHeadID, peer.ClusterHeadID)
+	}
+
+	if clusterHead := n.getClusterHead(); clusterHead != nil {
+		if peer, ok := rotate(clusterHead); ok {
+			if err := n.putPeer(types.BucketClusterHead, types.KeyClusterHead, peer); err != nil {
+				logger.Errorf("put cluster head %s: %s", peer.Name, err)
+				return err
+			}
+
+			n.setClusterHead(peer)
+		}
+	}
+
+	for bucket, peers := range map[string]*Peers{
+		types.BucketClusterNodes:  n.getClusterNodes(),
+		types.BucketChildrenNodes: n.getChildrenNodes(),
+	} {
+		if peers == nil {
+			continue
+		}
+
+		for _, peer := range peers.GetAll() {
+			updated, ok := rotate(peer)
+			if !ok {
+				continue
+			}
+
+			if err := n.db.Update(func(tx storage.Tx) error {
+				err := tx.Delete(bucket, peer.DeviceID)
+				if err != nil && !errors.Is(err, storage.ErrNotFound) {
+					return err
+				}
+
+				return nil
+			}); err != nil {
+				logger.Errorf("delete peer %s: %s", peer.Name, err)
+				return err
+			}
+
+			if err := n.putPeer(bucket, updated.DeviceID, updated); err != nil {
+				logger.Errorf("put peer %s: %s", peer.Name, err)
+				return err
+			}
+
+			peers.Add(updated)
+		}
+	}
+
+	return nil
+}
+
+// putPeer stores the peer in the bucket under the key.
+func (n *Node) putPeer(bucket string, key []byte, peer *Peer) error {
+	data, err := proto.Marshal(peer.ToProto())
+	if err != nil {
+		return err
+	}
+
+	return n.db.Update(func(tx storage.Tx) error {
+		return tx.Put(bucket, key, data, types.InfinityTTL)
+	})
+}
+
+// currentDeviceID follows the rotations of the device key and returns the id of its current key.
+func (n *Node) currentDeviceID(deviceID []byte) []byte {
+	if len(deviceID) == 0 {
+		return deviceID
+	}
+
+	_ = n.db.View(func(tx storage.Tx) error {
+		deviceID = followRotations(tx, deviceID)
+		return nil
+	})
+
+	return deviceID
+}
+
+// followRotations follows the rotations of the device key in the transaction and returns the id of its current key.
+func followRotations(tx storage.Tx, deviceID []byte) []byte {
+	for i := 0; i < maxLineage; i++ {
+		next, err := tx.Get(types.BucketRotations, deviceID)
+		if err != nil {
+			break
+		}
+
+		deviceID = next
+	}
+
+	return deviceID
+}
+
+// sameDevice checks if the ids belong to the same device, one of them may be rotated to the other one.
+func (n *Node) sameDevice(deviceID, otherID []byte) bool {
+	return bytes.Equal(deviceID, otherID) || bytes.Equal(n.currentDeviceID(deviceID), n.currentDeviceID(otherID))
+}
+
+// sameDeviceTx checks if the ids belong to the same device like sameDevice, the rotations are read in the transaction.
+func sameDeviceTx(tx storage.Tx, deviceID, otherID []byte) bool {
+	return bytes.Equal(deviceID, otherID) || bytes.Equal(followRotations(tx, deviceID), followRotations(tx, otherID))
+}
+
+// getLineage returns the ids the device key is rotated from, starting from the latest one.
+func (n *Node) getLineage(ctx context.Context, entry *types.AuthenticationEntry, level uint32) [][]byte {
+	var lineage [][]byte
+
+	for deviceID := entry.RotatedFrom; deviceID != nil && len(lineage) < maxLineage; {
+		lineage = append(lineage, deviceID)
+
+		previous, err := n.getLevelAuthenticationEntry(ctx, deviceID, level)
+		if err != nil {
+			break
+		}
+
+		deviceID = previous.RotatedFrom
+	}
+
+	return lineage
+}
+
+// putRotation stores the new public key of the rotation in the key bucket and maps the rotated device id to the new one.
+func putRotation(tx storage.Tx, rotation *types.DeviceKeyRotationRequest) error {
+	publicKey, err := cipher.RotationPublicKey(rotation)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Put(types.BucketKeys, rotation.NewDeviceId, cipher.SerializePublicKey(publicKey), types.InfinityTTL); err != nil {
+		return err
+	}
+
+	return tx.Put(types.BucketRotations, cipher.DeviceID(rotation.DeviceId), rotation.NewDeviceId, types.InfinityTTL)
+}
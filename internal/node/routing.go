@@ -0,0 +1,103 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package node
+
+import (
+	"context"
+	"fmt"
+
+	"authentication-chains/internal/types"
+)
+
+// maxMessageHops limits the number of nodes the message is forwarded through.
+const maxMessageHops = 16
+
+// routeMessage forwards the message to the next node on the way to its receiver and returns the response
+// of the receiver. The content is encrypted for the receiver, so the node sees only the envelope.
+func (n *Node) routeMessage(ctx context.Context, message *types.Message) (*types.Message, error) {
+	ctx, logger := n.logger.StartTrace(ctx, "route message")
+	logger = logger.WithFields("receiver_id", fmt.Sprintf("%x", message.ReceiverId))
+	defer logger.FinishTrace()
+
+	if message.Hops >= maxMessageHops {
+		return nil, fmt.Errorf("%w: %d hops", ErrHopLimit, message.Hops)
+	}
+
+	peer, err := n.nextHop(ctx, message.ReceiverId)
+	if err != nil {
+		logger.Errorf("find next hop: %s", err)
+		return nil, err
+	}
+
+	if peer == nil {
+		return n.storeMessage(ctx, message)
+	}
+
+	logger.Debugw("forward message", "peer", peer.Name, "hops", message.Hops)
+
+	response, err := peer.Client.SendMessage(ctx, &types.Message{
+		SenderId:        message.SenderId,
+		ReceiverId:      message.ReceiverId,
+		Data:            message.Data,
+		EnvelopeVersion: message.EnvelopeVersion,
+		Hops:            message.Hops + 1,
+		Signature:       message.Signature,
+	})
+	if err != nil {
+		logger.Errorf("forward message to node %s: %s", peer.Name, err)
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// nextHop returns the peer the message to the device is forwarded to. Cluster heads of the device are followed
+// through the authentication tables down to the one which is a peer of the node.
+// The message climbs to the cluster head of the node if the device isn't found below.
+// No peer is returned for a device of the cluster of the node which isn't a node, its messages are kept in the mailbox.
+// A device registered at the top cluster has no cluster head, it belongs to the cluster of the node if its entry is
+// of the node level.
+func (n *Node) nextHop(ctx context.Context, deviceID []byte) (*Peer, error) {
+	for id, i := deviceID, 0; i < maxLineage; i++ {
+		if peer := n.getPeer(n.currentDeviceID(id)); peer != nil {
+			return peer, nil
+		}
+
+		entry, level, err := n.findAuthenticationEntry(ctx, id)
+		if err != nil {
+			break
+		}
+
+		if len(entry.ClusterHeadId) == 0 {
+			if level != n.getLevel() {
+				break
+			}
+
+			if i == 0 {
+				return nil, nil
+			}
+
+			return nil, fmt.Errorf("%w: node %x isn't a peer", ErrUnreachableReceiver, id)
+		}
+
+		if n.sameDevice(entry.ClusterHeadId, n.deviceID) {
+			if i == 0 {
+				return nil, nil
+			}
+
+			// the node of the device is in the cluster of the node, but it isn't a peer to forward the message to
+			return nil, fmt.Errorf("%w: node %x isn't a peer", ErrUnreachableReceiver, id)
+		}
+
+		id = entry.ClusterHeadId
+	}
+
+	clusterHead := n.getClusterHead()
+	if clusterHead == nil {
+		return nil, fmt.Errorf("device %x: %w", deviceID, ErrNotFoundDevice)
+	}
+
+	return clusterHead, nil
+}
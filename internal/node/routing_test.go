@@ -0,0 +1,83 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package node
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DirusK/utils/log"
+
+	"authentication-chains/internal/config"
+	"authentication-chains/internal/storage"
+	"authentication-chains/internal/types"
+)
+
+func TestNextHopOfRootNode(t *testing.T) {
+	var (
+		rootID  = []byte("alice")
+		level   = uint32(1)
+		entries = map[uint32][]*types.AuthenticationEntry{
+			// devices registered at the root node have no cluster head
+			level: {
+				{DeviceId: []byte("finn")},
+				{DeviceId: []byte("jake"), ClusterHeadId: rootID},
+			},
+			level - 1: {
+				{DeviceId: []byte("marceline")},
+			},
+		}
+	)
+
+	db := storage.NewMemory()
+
+	if err := db.Update(func(tx storage.Tx) error {
+		for level, levelEntries := range entries {
+			for _, entry := range levelEntries {
+				if err := putEntry(tx, level, entry); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}); err != nil {
+		t.Fatalf("put entries: %s", err)
+	}
+
+	n := &Node{
+		cfg:      config.Node{Level: level},
+		db:       db,
+		logger:   log.New(),
+		deviceID: rootID,
+	}
+
+	tests := []struct {
+		name     string
+		deviceID []byte
+		wantErr  error
+	}{
+		{name: "device without cluster head at the node level", deviceID: []byte("finn")},
+		{name: "device of the node", deviceID: []byte("jake")},
+		{name: "device without cluster head below the node level", deviceID: []byte("marceline"), wantErr: ErrNotFoundDevice},
+		{name: "unknown device", deviceID: []byte("bubblegum"), wantErr: ErrNotFoundDevice},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			peer, err := n.nextHop(context.Background(), test.deviceID)
+
+			switch {
+			case test.wantErr != nil && !errors.Is(err, test.wantErr):
+				t.Fatalf("got error %v, want %v", err, test.wantErr)
+			case test.wantErr == nil && err != nil:
+				t.Fatalf("got error %v, want the message to be kept by the node", err)
+			case peer != nil:
+				t.Fatalf("got peer %s, want no peer", peer.Name)
+			}
+		})
+	}
+}
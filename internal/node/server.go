@@ -10,7 +10,11 @@ import (
 	"errors"
 	"fmt"
 
+	"authentication-chains/internal/blockchain"
 	"authentication-chains/internal/cipher"
+	"authentication-chains/internal/config"
+	"authentication-chains/internal/storage"
+	"authentication-chains/internal/transport"
 	"authentication-chains/internal/types"
 )
 
@@ -24,15 +28,34 @@ func (n *Node) GetStatus(ctx context.Context, _ *types.StatusRequest) (*types.St
 
 	lastBlock := n.chain.GetLastBlock()
 
+	var (
+		clusterHead   *types.Peer
+		clusterHeadID []byte
+	)
+
+	if peer := n.getClusterHead(); peer != nil {
+		clusterHead = peer.ToProto()
+		clusterHeadID = peer.DeviceID
+	}
+
+	var childrenHeight uint64
+	if level, err := n.childrenLevel(); err == nil {
+		childrenHeight = n.authenticationHeight(level)
+	}
+
 	return &types.StatusResponse{
 		Peer: &types.Peer{
 			Name:          n.cfg.Name,
-			Level:         n.cfg.Level,
+			Level:         n.getLevel(),
 			DeviceId:      n.deviceID,
-			ClusterHeadId: n.getClusterHeadDeviceID(),
+			ClusterHeadId: clusterHeadID,
 			GrpcAddress:   n.cfg.GRPC.Address,
 		},
 		LastBlockIndex: lastBlock.Index,
+		LastBlockHash:  lastBlock.Hash,
+		ClusterHead:    clusterHead,
+		Height:         n.authenticationHeight(n.getLevel()),
+		ChildrenHeight: childrenHeight,
 	}, nil
 }
 
@@ -52,6 +75,25 @@ func (n *Node) GetBlock(ctx context.Context, request *types.BlockRequest) (*type
 	}, nil
 }
 
+func (n *Node) FindBlock(ctx context.Context, request *types.FindBlockRequest) (*types.FindBlockResponse, error) {
+	ctx, logger := n.logger.StartTrace(ctx, "find block")
+	logger = logger.WithFields("device_id", fmt.Sprintf("%x", request.DeviceId))
+	defer logger.FinishTrace()
+
+	logger.Debugw("received find block request", "index", request.Index, "hash", fmt.Sprintf("%x", request.Hash))
+
+	block, hops, err := n.findBlock(ctx, request)
+	if err != nil {
+		logger.Errorf("find block: %s", err)
+		return nil, err
+	}
+
+	return &types.FindBlockResponse{
+		Block: block,
+		Hops:  hops,
+	}, nil
+}
+
 func (n *Node) GetBlocks(ctx context.Context, request *types.BlocksRequest) (*types.BlocksResponse, error) {
 	ctx, logger := n.logger.StartTrace(ctx, "get blocks")
 	defer logger.FinishTrace()
@@ -68,33 +110,77 @@ func (n *Node) GetBlocks(ctx context.Context, request *types.BlocksRequest) (*ty
 	}, nil
 }
 
+func (n *Node) StreamBlocks(request *types.BlocksRequest, stream types.Node_StreamBlocksServer) error {
+	ctx, logger := n.logger.StartTrace(stream.Context(), "stream blocks")
+	defer logger.FinishTrace()
+
+	logger.Debugw("received stream blocks request", "from", request.From, "to", request.To)
+
+	batchSize := uint64(n.cfg.Sync.BatchSize)
+
+	for from := request.From; from <= request.To; from += batchSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		blocks, err := n.chain.GetAllBlocks(from, min(from+batchSize-1, request.To))
+		if err != nil {
+			logger.Errorf("get blocks from %d: %s", from, err)
+			return err
+		}
+
+		for _, block := range blocks {
+			if err = stream.Send(block); err != nil {
+				logger.Errorf("send block %d: %s", block.Index, err)
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 func (n *Node) GetPeers(ctx context.Context, request *types.PeersRequest) (*types.PeersResponse, error) {
 	ctx, logger := n.logger.StartTrace(ctx, "get peers")
 	defer logger.FinishTrace()
 
 	logger.Debugw("received get peers request", "level", request.Level)
 
-	if request.Level != n.cfg.Level && request.Level != n.cfg.Level-1 {
-		return nil, fmt.Errorf("level %d is not supported", request.Level)
-	}
+	childrenLevel, childrenErr := n.childrenLevel()
 
 	var peers []*types.Peer
 
 	switch {
-	case request.Level == n.cfg.Level:
-		if n.clusterNodes != nil {
-			peers = n.clusterNodes.ToProto()
+	case request.Level == n.getLevel():
+		if clusterNodes := n.getClusterNodes(); clusterNodes != nil {
+			peers = clusterNodes.ToProto()
 		}
-	case request.Level == n.cfg.Level-1:
-		if n.childrenNodes != nil {
-			peers = n.childrenNodes.ToProto()
+	case childrenErr == nil && request.Level == childrenLevel:
+		if childrenNodes := n.getChildrenNodes(); childrenNodes != nil {
+			peers = childrenNodes.ToProto()
 		}
+	default:
+		return nil, fmt.Errorf("level %d is not supported", request.Level)
 	}
 
 	return &types.PeersResponse{
 		Peers: peers,
 	}, nil
+}
+
+func (n *Node) GetTopology(ctx context.Context, request *types.TopologyRequest) (*types.TopologyResponse, error) {
+	ctx, logger := n.logger.StartTrace(ctx, "get topology")
+	defer logger.FinishTrace()
 
+	logger.Debugw("received get topology request", "depth", request.Depth, "subtree", request.Subtree, "visited", len(request.Visited))
+
+	response, err := n.getTopology(ctx, request)
+	if err != nil {
+		logger.Errorf("get topology: %s", err)
+		return nil, err
+	}
+
+	return response, nil
 }
 
 func (n *Node) SendBlock(ctx context.Context, request *types.BlockValidationRequest) (*types.BlockValidationResponse, error) {
@@ -104,46 +190,110 @@ func (n *Node) SendBlock(ctx context.Context, request *types.BlockValidationRequ
 
 	logger.Debugw("received send block request")
 
-	response := &types.BlockValidationResponse{}
+	if request.Block.IsEmpty() {
+		return &types.BlockValidationResponse{}, ErrEmptyBlock
+	}
+
+	var validationErr error
 
 	switch {
-	case request.Block.Dar == nil:
-		return response, ErrBlockHasNoDAR
-
-	// if block from children node -> validate and add auth entry
-	case bytes.Equal(request.Block.Dar.ClusterHeadId, n.deviceID):
-		if err := n.validateBlock(ctx, request.Block); err != nil {
-			if errors.Is(err, ErrBlockValidation) {
-				response.IsValid = false
-				logger.Debugw("block is invalid")
-				return response, nil
-			}
+	// if block from children node -> validate against the children level
+	case bytes.Equal(request.Block.ClusterHeadID(), n.deviceID):
+		level, err := n.childrenLevel()
+		if err != nil {
+			validationErr = fmt.Errorf("%w: %s", ErrBlockValidation, err)
+			break
+		}
+
+		if validationErr = checkValidators(request.Block, n.getChildrenVoterIDs()); validationErr == nil {
+			validationErr = n.validateBlock(ctx, request.Block, level)
+		}
+
+	// if block from cluster node -> validate against the own level and chain
+	default:
+		if validationErr = checkValidators(request.Block, n.getClusterVoterIDs()); validationErr != nil {
+			break
+		}
+
+		if validationErr = n.validateBlock(ctx, request.Block, n.getLevel()); validationErr == nil {
+			validationErr = n.chain.ValidateBlock(request.Block)
+		}
+	}
+
+	if validationErr != nil &&
+		!errors.Is(validationErr, ErrBlockValidation) &&
+		!errors.Is(validationErr, blockchain.ErrBlockValidation) {
+		logger.Errorf("validate block %x: %s", request.Block.Hash, validationErr)
+		return &types.BlockValidationResponse{}, validationErr
+	}
+
+	vote, err := n.signVote(request.Block, validationErr == nil)
+	if err != nil {
+		logger.Errorf("sign vote: %s", err)
+		return &types.BlockValidationResponse{}, err
+	}
+
+	if validationErr != nil {
+		logger.Debugw("block is invalid", "reason", validationErr.Error())
+	} else {
+		logger.Debug("block is valid")
+	}
+
+	return &types.BlockValidationResponse{
+		IsValid: vote.IsValid,
+		Vote:    vote,
+	}, nil
+}
+
+func (n *Node) CommitBlock(ctx context.Context, request *types.BlockCommitRequest) (*types.BlockCommitResponse, error) {
+	ctx, logger := n.logger.StartTrace(ctx, "commit block")
+	logger = logger.WithFields("block_hash", fmt.Sprintf("%x", request.Block.Hash))
+	defer logger.FinishTrace()
+
+	logger.Debugw("received commit block request")
+
+	response := &types.BlockCommitResponse{}
+
+	switch {
+	case request.Block.IsEmpty():
+		return response, ErrEmptyBlock
+
+	// if block from children node -> verify quorum, validate and add auth entry
+	case bytes.Equal(request.Block.ClusterHeadID(), n.deviceID):
+		level, err := n.childrenLevel()
+		if err != nil {
+			logger.Errorf("commit children block: %s", err)
+			return response, err
+		}
+
+		if err = checkValidators(request.Block, n.getChildrenVoterIDs()); err != nil {
+			logger.Errorf("check validators: %s", err)
+			return response, err
+		}
 
+		if err = n.verifyQuorum(ctx, request.Block); err != nil {
+			logger.Errorf("verify quorum: %s", err)
+			return response, err
+		}
+
+		if err = n.validateBlock(ctx, request.Block, level); err != nil {
 			logger.Errorf("validate block %x: %s", request.Block.Hash, err)
 			return response, err
 		}
 
-		if err := n.addAuthenticationEntry(ctx, request.Block, n.cfg.Level-1); err != nil {
+		if err = n.addAuthenticationEntry(ctx, request.Block, level); err != nil {
 			logger.Errorf("add authentication entry: %s", err)
 			return response, err
 		}
 
-		// if block from cluster node -> validate and add to auth table and chain
+	// if block from cluster node -> verify quorum and add to auth table and chain
 	default:
 		if err := n.addBlock(ctx, request.Block); err != nil {
-			if errors.Is(err, ErrBlockValidation) {
-				response.IsValid = false
-				logger.Debugw("block is invalid")
-				return response, nil
-			}
-
 			return response, err
 		}
 	}
 
-	response.IsValid = true
-
-	logger.Debug("block is valid")
+	logger.Debug("block is committed")
 
 	return response, nil
 }
@@ -152,71 +302,237 @@ func (n *Node) SendDAR(ctx context.Context, request *types.DeviceAuthenticationR
 	ctx, logger := n.logger.StartTrace(ctx, "broadcast dar")
 	defer logger.FinishTrace()
 
-	logger.Debugw("received send dar request", "device_id", string(request.DeviceId))
+	logger.Debugw("received send dar request", "device_id", fmt.Sprintf("%x", request.DeviceId))
 
-	if _, err := n.getAuthenticationEntry(ctx, request.DeviceId); err == nil {
+	if _, err := n.getAuthenticationEntry(ctx, cipher.DeviceID(request.DeviceId)); err == nil {
 		return nil, errors.New("device is already registered in authentication table")
 	}
 
+	if err := n.verifyValidity(request.Validity); err != nil {
+		return nil, err
+	}
+
 	if err := cipher.VerifyDAR(request); err != nil {
 		return nil, err
 	}
 
-	block, err := n.mineBlock(ctx, request)
+	if err := n.memPool.Add(request); err != nil {
+		return nil, err
+	}
+
+	ticket := &types.DeviceAuthenticationResponse{
+		Ticket: darTicket(request),
+		Status: types.DARStatus_DAR_STATUS_PENDING,
+	}
+
+	if err := n.putTicket(ctx, ticket); err != nil {
+		logger.Errorf("put ticket %x: %s", ticket.Ticket, err)
+		n.memPool.Remove(request)
+
+		return nil, err
+	}
+
+	// block size is reached -> don't wait for the scheduler. The production runs apart from the worker pool,
+	// since the consensus waits for the votes collected by the pool, and only one production is pending at once.
+	if n.memPool.Len() >= n.cfg.MemPool.BlockSize && n.producing.CompareAndSwap(false, true) {
+		go func() {
+			defer n.producing.Store(false)
+
+			n.ProduceBlock(context.WithoutCancel(ctx))
+		}()
+	}
+
+	return ticket, nil
+}
+
+func (n *Node) GetDARStatus(ctx context.Context, request *types.DARStatusRequest) (*types.DeviceAuthenticationResponse, error) {
+	ctx, logger := n.logger.StartTrace(ctx, "get dar status")
+	defer logger.FinishTrace()
+
+	logger.Debugw("received get dar status request", "ticket", fmt.Sprintf("%x", request.Ticket))
+
+	return n.getTicket(ctx, request.Ticket)
+}
+
+func (n *Node) RevokeDevice(ctx context.Context, request *types.DeviceRevocationRequest) (*types.DeviceRevocationResponse, error) {
+	ctx, logger := n.logger.StartTrace(ctx, "revoke device")
+	logger = logger.WithFields("device_id", fmt.Sprintf("%x", request.DeviceId))
+	defer logger.FinishTrace()
+
+	logger.Debugw("received revoke device request")
+
+	if err := n.verifyRevocation(ctx, request, n.getLevel()); err != nil {
+		logger.Errorf("verify revocation: %s", err)
+		return nil, err
+	}
+
+	block, err := n.mineBlock(ctx, types.Transactions{Revocations: []*types.DeviceRevocationRequest{request}})
 	if err != nil {
 		return nil, err
 	}
 
-	return &types.DeviceAuthenticationResponse{BlockHash: block.Hash}, nil
+	logger.Debugw("device is revoked", "block_hash", fmt.Sprintf("%x", block.Hash))
+
+	return &types.DeviceRevocationResponse{BlockHash: block.Hash}, nil
+}
+
+func (n *Node) RenewDAR(ctx context.Context, request *types.DeviceRenewalRequest) (*types.DeviceAuthenticationResponse, error) {
+	ctx, logger := n.logger.StartTrace(ctx, "renew dar")
+	logger = logger.WithFields("device_id", fmt.Sprintf("%x", request.DeviceId))
+	defer logger.FinishTrace()
+
+	logger.Debugw("received renew dar request")
+
+	if err := n.verifyRenewal(ctx, request, n.getLevel()); err != nil {
+		logger.Errorf("verify renewal: %s", err)
+		return nil, err
+	}
+
+	block, err := n.mineBlock(ctx, types.Transactions{Renewals: []*types.DeviceRenewalRequest{request}})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debugw("device authentication is renewed", "block_hash", fmt.Sprintf("%x", block.Hash))
+
+	return &types.DeviceAuthenticationResponse{
+		BlockHash: block.Hash,
+		Status:    types.DARStatus_DAR_STATUS_ACCEPTED,
+	}, nil
+}
+
+func (n *Node) RotateKey(ctx context.Context, request *types.DeviceKeyRotationRequest) (*types.DeviceAuthenticationResponse, error) {
+	ctx, logger := n.logger.StartTrace(ctx, "rotate key")
+	logger = logger.WithFields("device_id", fmt.Sprintf("%x", request.DeviceId))
+	defer logger.FinishTrace()
+
+	logger.Debugw("received rotate key request", "new_device_id", fmt.Sprintf("%x", request.NewDeviceId))
+
+	if err := n.verifyRotation(ctx, request, n.getLevel()); err != nil {
+		logger.Errorf("verify rotation: %s", err)
+		return nil, err
+	}
+
+	block, err := n.mineBlock(ctx, types.Transactions{Rotations: []*types.DeviceKeyRotationRequest{request}})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debugw("device key is rotated", "block_hash", fmt.Sprintf("%x", block.Hash))
+
+	return &types.DeviceAuthenticationResponse{
+		BlockHash: block.Hash,
+		Status:    types.DARStatus_DAR_STATUS_ACCEPTED,
+	}, nil
 }
 
 func (n *Node) SendMessage(ctx context.Context, message *types.Message) (*types.Message, error) {
 	ctx, logger := n.logger.StartTrace(ctx, "send message")
-	logger = logger.WithFields("sender_id", string(message.SenderId))
+	logger = logger.WithFields("sender_id", fmt.Sprintf("%x", message.SenderId))
 	defer logger.FinishTrace()
 
 	logger.Debug("received send message request")
 
-	if !bytes.Equal(message.ReceiverId, n.deviceID) {
+	if len(message.ReceiverId) == 0 {
 		return nil, ErrInvalidMessageReceiver
 	}
 
-	reqContent, err := n.cipher.DecryptContent(message.Data)
+	// nodes relay and keep messages of authenticated devices only, the envelope proves the sender
+	if err := n.verifyMessageSender(ctx, message); err != nil {
+		logger.Errorf("verify message sender: %s", err)
+		return nil, err
+	}
+
+	if !bytes.Equal(message.ReceiverId, n.deviceID) {
+		// the content is encrypted for the receiver, so only the envelope is forwarded
+		return n.routeMessage(ctx, message)
+	}
+
+	reqContent, err := n.cipher.DecryptContent(message.Data, message.EnvelopeVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	pubKey, err := n.getPublicKey(ctx, message.SenderId)
 	if err != nil {
 		return nil, err
 	}
 
-	if err = n.verifyAuthentication(ctx, message.SenderId, reqContent.BlockHash); err != nil {
+	if err = n.verifyContent(reqContent, message, pubKey); err != nil {
+		logger.Errorf("verify content: %s", err)
 		return nil, err
 	}
 
-	respContent := &types.Content{
-		BlockHash: n.authBlockHash,
-		Data:      []byte("You are authenticated and message is received: " + string(reqContent.Data)),
+	if _, err = n.verifyAuthentication(ctx, message.SenderId, reqContent.BlockHash); err != nil {
+		return nil, err
 	}
 
-	pubKey, err := cipher.DeserializePublicKey(message.SenderId)
+	respContent, err := cipher.NewContent(
+		n.deviceID,
+		message.SenderId,
+		n.getAuthBlockHash(),
+		[]byte("You are authenticated and message is received: "+string(reqContent.Data)),
+		reqContent.Nonce,
+	)
 	if err != nil {
 		return nil, err
 	}
 
-	data, err := cipher.EncryptContent(pubKey, respContent)
+	if err = n.cipher.SignContent(respContent); err != nil {
+		return nil, err
+	}
+
+	// response is encrypted in the format of the request, so the sender is able to decrypt it
+	data, err := cipher.EncryptContent(pubKey, respContent, message.EnvelopeVersion)
 	if err != nil {
 		return nil, err
 	}
 
 	logger.Debugw("response message is sent", "message", string(respContent.Data))
 
-	return types.NewMessage(n.deviceID, message.SenderId, data), nil
+	return types.NewMessage(n.deviceID, message.SenderId, data, message.EnvelopeVersion), nil
+}
+
+func (n *Node) GetRegistrationNonce(ctx context.Context, request *types.RegistrationNonceRequest) (*types.RegistrationNonceResponse, error) {
+	ctx, logger := n.logger.StartTrace(ctx, "get registration nonce")
+	logger = logger.WithFields("device_id", fmt.Sprintf("%x", request.DeviceId))
+	defer logger.FinishTrace()
+
+	logger.Debugw("received registration nonce request")
+
+	// in the pinned TLS mode the nonce is issued to the owner of the key only
+	if n.cfg.GRPC.TLS.Mode == config.TLSModePinned && !bytes.Equal(transport.PeerDeviceID(ctx), request.DeviceId) {
+		logger.Errorf("issue registration nonce: %s", ErrPeerIdentity)
+		return nil, ErrPeerIdentity
+	}
+
+	response, err := n.issueRegistrationNonce(request.DeviceId)
+	if err != nil {
+		logger.Errorf("issue registration nonce: %s", err)
+		return nil, err
+	}
+
+	return response, nil
 }
 
 func (n *Node) RegisterNode(ctx context.Context, request *types.NodeRegistrationRequest) (*types.NodeRegistrationResponse, error) {
 	ctx, logger := n.logger.StartTrace(ctx, "register node")
 	defer logger.FinishTrace()
 
-	logger.Debugw("received register node request", "node", request.Node.Name)
+	logger.Debugw("received register node request", "node", request.GetNode().GetName())
+
+	if err := n.verifyRegistration(ctx, request); err != nil {
+		logger.Errorf("verify registration of node %s: %s", request.GetNode().GetName(), err)
+		return nil, err
+	}
 
-	client, err := initClient(ctx, request.Node.GrpcAddress)
+	// in the pinned TLS mode the node must be the owner of the key it registers with
+	if n.cfg.GRPC.TLS.Mode == config.TLSModePinned && !bytes.Equal(transport.PeerDeviceID(ctx), request.Node.DeviceId) {
+		logger.Errorf("node %s: %s", request.Node.Name, ErrPeerIdentity)
+		return nil, ErrPeerIdentity
+	}
+
+	client, err := n.initClient(ctx, request.Node.GrpcAddress, request.Node.DeviceId)
 	if err != nil {
 		logger.Errorf("init cluster head client: %s", err)
 		return nil, err
@@ -233,7 +549,7 @@ func (n *Node) RegisterNode(ctx context.Context, request *types.NodeRegistration
 		return nil, err
 	}
 
-	peers := n.childrenNodes.ToProto()
+	peers := n.getChildrenNodes().ToProto()
 	for i, peer := range peers {
 		if peer.GrpcAddress == request.Node.GrpcAddress {
 			peers = append(peers[:i], peers[i+1:]...)
@@ -242,25 +558,126 @@ func (n *Node) RegisterNode(ctx context.Context, request *types.NodeRegistration
 	}
 
 	return &types.NodeRegistrationResponse{
-		GenesisHash: n.authBlockHash,
+		GenesisHash: n.childrenGenesisHash(),
 		Peers:       peers,
 	}, nil
 }
 
+func (n *Node) DeregisterNode(
+	ctx context.Context,
+	request *types.NodeDeregistrationRequest,
+) (*types.NodeDeregistrationResponse, error) {
+	ctx, logger := n.logger.StartTrace(ctx, "deregister node")
+	logger = logger.WithFields("device_id", fmt.Sprintf("%x", request.DeviceId))
+	defer logger.FinishTrace()
+
+	logger.Debugw("received deregister node request", "children", len(request.Children))
+
+	if err := n.deregisterNode(ctx, request); err != nil {
+		logger.Errorf("deregister node: %s", err)
+		return nil, err
+	}
+
+	return &types.NodeDeregistrationResponse{}, nil
+}
+
+func (n *Node) RotatePeerKey(ctx context.Context, request *types.DeviceKeyRotationRequest) (*types.PeerKeyRotationResponse, error) {
+	ctx, logger := n.logger.StartTrace(ctx, "rotate peer key")
+	logger = logger.WithFields("device_id", fmt.Sprintf("%x", request.DeviceId))
+	defer logger.FinishTrace()
+
+	logger.Debugw("received rotate peer key request", "new_device_id", fmt.Sprintf("%x", request.NewDeviceId))
+
+	if n.getPeer(request.DeviceId) == nil {
+		return nil, fmt.Errorf("device %x: %w", request.DeviceId, ErrUnknownPeer)
+	}
+
+	publicKey, err := n.getPublicKey(ctx, request.DeviceId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = cipher.VerifyRotation(request, publicKey); err != nil {
+		logger.Errorf("verify rotation: %s", err)
+		return nil, err
+	}
+
+	if err = n.db.Update(func(tx storage.Tx) error {
+		return putRotation(tx, request)
+	}); err != nil {
+		logger.Errorf("put rotation: %s", err)
+		return nil, err
+	}
+
+	if err = n.rotatePeerKey(ctx, request.DeviceId, request.NewDeviceId); err != nil {
+		return nil, err
+	}
+
+	logger.Debugw("peer key is rotated")
+
+	return &types.PeerKeyRotationResponse{}, nil
+}
+
+func (n *Node) AnnounceClusterHead(
+	ctx context.Context,
+	announcement *types.ClusterHeadAnnouncement,
+) (*types.ClusterHeadAnnouncementResponse, error) {
+	ctx, logger := n.logger.StartTrace(ctx, "announce cluster head")
+	defer logger.FinishTrace()
+
+	logger.Debugw("received cluster head announcement", "term", announcement.Term)
+
+	if err := n.acceptClusterHead(ctx, announcement); err != nil {
+		logger.Errorf("accept cluster head: %s", err)
+		return nil, err
+	}
+
+	return &types.ClusterHeadAnnouncementResponse{Accepted: true}, nil
+}
+
 func (n *Node) VerifyDevice(ctx context.Context, request *types.VerifyDeviceRequest) (*types.VerifyDeviceResponse, error) {
 	ctx, logger := n.logger.StartTrace(ctx, "verify device")
-	logger = logger.WithFields("device_id", string(request.DeviceId))
+	logger = logger.WithFields("device_id", fmt.Sprintf("%x", request.DeviceId))
 	defer logger.FinishTrace()
 
-	logger.Debugw("received verify device request")
+	logger.Debugw("received verify device request", "requester_id", fmt.Sprintf("%x", request.RequesterId))
 
-	if err := n.verifyAuthentication(ctx, request.DeviceId, request.BlockHash); err != nil {
-		return &types.VerifyDeviceResponse{IsVerified: false}, err
+	pubKey, err := n.getPublicKey(ctx, request.RequesterId)
+	if err != nil {
+		return nil, err
 	}
 
-	logger.Debugw("device is verified")
+	if err = cipher.VerifyDeviceVerification(request, pubKey); err != nil {
+		logger.Errorf("verify request signature: %s", err)
+		return nil, err
+	}
 
-	return &types.VerifyDeviceResponse{IsVerified: true}, nil
+	if err = n.replay.check(request.RequesterId, request.Nonce, request.Timestamp); err != nil {
+		logger.Errorf("check replay: %s", err)
+		return nil, err
+	}
+
+	hops, verifyErr := n.verifyAuthentication(ctx, request.DeviceId, request.BlockHash)
+
+	response := &types.VerifyDeviceResponse{
+		IsVerified: verifyErr == nil,
+		DeviceId:   request.DeviceId,
+		BlockHash:  request.BlockHash,
+		ReplyTo:    request.Nonce,
+		Hops:       hops,
+	}
+
+	if err = n.cipher.SignDeviceVerificationResult(response); err != nil {
+		return nil, err
+	}
+
+	if verifyErr != nil {
+		return response, verifyErr
+	}
+
+	logger.Debugw("device is verified", "hops", len(hops))
+
+	return response, nil
 }
 
 func (n *Node) GetAuthenticationTable(
@@ -279,3 +696,119 @@ func (n *Node) GetAuthenticationTable(
 
 	return &types.AuthenticationTableResponse{Table: table}, nil
 }
+
+func (n *Node) GetInclusionProof(ctx context.Context, request *types.InclusionProofRequest) (*types.InclusionProofResponse, error) {
+	ctx, logger := n.logger.StartTrace(ctx, "get inclusion proof")
+	logger = logger.WithFields("device_id", fmt.Sprintf("%x", request.DeviceId))
+	defer logger.FinishTrace()
+
+	logger.Debugw("received get inclusion proof request")
+
+	proof, err := n.getInclusionProof(ctx, request.DeviceId)
+	if err != nil {
+		logger.Errorf("get inclusion proof: %s", err)
+		return nil, err
+	}
+
+	return proof, nil
+}
+
+func (n *Node) GetPublicKey(ctx context.Context, request *types.PublicKeyRequest) (*types.PublicKeyResponse, error) {
+	ctx, logger := n.logger.StartTrace(ctx, "get public key")
+	logger = logger.WithFields("device_id", fmt.Sprintf("%x", request.DeviceId))
+	defer logger.FinishTrace()
+
+	logger.Debugw("received get public key request")
+
+	if bytes.Equal(request.DeviceId, n.deviceID) {
+		return &types.PublicKeyResponse{PublicKey: n.cipher.SerializePublicKey()}, nil
+	}
+
+	publicKey, err := n.getPublicKey(ctx, request.DeviceId)
+	if err != nil {
+		return nil, fmt.Errorf("device %x: %w", request.DeviceId, ErrNotFoundKey)
+	}
+
+	return &types.PublicKeyResponse{PublicKey: cipher.SerializePublicKey(publicKey)}, nil
+}
+
+func (n *Node) GetMailboxChallenge(ctx context.Context, request *types.MailboxChallengeRequest) (*types.MailboxChallengeResponse, error) {
+	ctx, logger := n.logger.StartTrace(ctx, "get mailbox challenge")
+	logger = logger.WithFields("device_id", fmt.Sprintf("%x", request.DeviceId))
+	defer logger.FinishTrace()
+
+	logger.Debugw("received mailbox challenge request")
+
+	holder, err := n.mailboxHolder(ctx, request.DeviceId)
+	if err != nil {
+		logger.Errorf("find mailbox: %s", err)
+		return nil, err
+	}
+
+	if holder != nil {
+		logger.Debugw("forward mailbox challenge request", "peer", holder.Name)
+		return holder.Client.GetMailboxChallenge(ctx, request)
+	}
+
+	response, err := n.issueMailboxChallenge(ctx, request.DeviceId)
+	if err != nil {
+		logger.Errorf("issue mailbox challenge: %s", err)
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (n *Node) FetchMessages(ctx context.Context, request *types.FetchMessagesRequest) (*types.FetchMessagesResponse, error) {
+	ctx, logger := n.logger.StartTrace(ctx, "fetch messages")
+	logger = logger.WithFields("device_id", fmt.Sprintf("%x", request.DeviceId))
+	defer logger.FinishTrace()
+
+	logger.Debugw("received fetch messages request", "limit", request.Limit)
+
+	holder, err := n.mailboxHolder(ctx, request.DeviceId)
+	if err != nil {
+		logger.Errorf("find mailbox: %s", err)
+		return nil, err
+	}
+
+	if holder != nil {
+		logger.Debugw("forward fetch messages request", "peer", holder.Name)
+		return holder.Client.FetchMessages(ctx, request)
+	}
+
+	messages, err := n.fetchMessages(ctx, request)
+	if err != nil {
+		logger.Errorf("fetch messages: %s", err)
+		return nil, err
+	}
+
+	return &types.FetchMessagesResponse{Messages: messages}, nil
+}
+
+func (n *Node) AckMessages(ctx context.Context, request *types.AckMessagesRequest) (*types.AckMessagesResponse, error) {
+	ctx, logger := n.logger.StartTrace(ctx, "ack messages")
+	logger = logger.WithFields("device_id", fmt.Sprintf("%x", request.DeviceId))
+	defer logger.FinishTrace()
+
+	logger.Debugw("received ack messages request", "messages", len(request.MessageIds))
+
+	holder, err := n.mailboxHolder(ctx, request.DeviceId)
+	if err != nil {
+		logger.Errorf("find mailbox: %s", err)
+		return nil, err
+	}
+
+	if holder != nil {
+		logger.Debugw("forward ack messages request", "peer", holder.Name)
+		return holder.Client.AckMessages(ctx, request)
+	}
+
+	removed, err := n.ackMessages(ctx, request)
+	if err != nil {
+		logger.Errorf("ack messages: %s", err)
+		return nil, err
+	}
+
+	return &types.AckMessagesResponse{Removed: removed}, nil
+}
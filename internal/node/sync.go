@@ -0,0 +1,137 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"authentication-chains/internal/types"
+)
+
+// syncBlocks syncs blocks from the nodes. Batches of blocks are fetched from the nodes in parallel
+// and written in order. Written blocks are the chain of the node, so an interrupted sync resumes
+// from the chain tip.
+func (n *Node) syncBlocks(ctx context.Context, peers []*Peer, from, to uint64) error {
+	ctx, logger := n.logger.StartTrace(ctx, "sync blocks")
+	defer logger.FinishTrace()
+
+	batchSize := uint64(n.cfg.Sync.BatchSize)
+
+	for from <= to {
+		var (
+			batches = make([][]*types.Block, len(peers))
+			errs    = make([]error, len(peers))
+			group   = n.workerPool.Group()
+		)
+
+		for i := range peers {
+			start := from + uint64(i)*batchSize
+			if start > to {
+				break
+			}
+
+			i, end := i, min(start+batchSize-1, to)
+
+			group.Submit(func() {
+				batches[i], errs[i] = n.fetchBlocks(ctx, peers, i, start, end)
+			})
+		}
+
+		group.Wait()
+
+		for i, batch := range batches {
+			if errs[i] != nil {
+				return errs[i]
+			}
+
+			if batch == nil {
+				break
+			}
+
+			if err := n.writeBlocks(ctx, batch); err != nil {
+				return err
+			}
+
+			from = batch[len(batch)-1].Index + 1
+		}
+	}
+
+	return nil
+}
+
+// writeBlocks adds the batch of blocks to the chain.
+func (n *Node) writeBlocks(ctx context.Context, blocks []*types.Block) error {
+	ctx, logger := n.logger.StartTrace(ctx, "write blocks")
+	defer logger.FinishTrace()
+
+	for _, block := range blocks {
+		if err := n.addBlock(ctx, block); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fetchBlocks fetches the range of blocks starting from the node with the given number
+// and falling back to the other nodes on failure.
+func (n *Node) fetchBlocks(ctx context.Context, peers []*Peer, first int, from, to uint64) ([]*types.Block, error) {
+	ctx, logger := n.logger.StartTrace(ctx, "fetch blocks")
+	defer logger.FinishTrace()
+
+	var err error
+
+	for i := 0; i < len(peers); i++ {
+		peer := peers[(first+i)%len(peers)]
+
+		var blocks []*types.Block
+		if blocks, err = n.streamBlocks(ctx, peer, from, to); err == nil {
+			return blocks, nil
+		}
+
+		logger.Errorf("stream blocks from %d to %d from node %s: %s", from, to, peer.Name, err)
+	}
+
+	return nil, err
+}
+
+// streamBlocks receives the range of blocks from the node.
+func (n *Node) streamBlocks(ctx context.Context, peer *Peer, from, to uint64) ([]*types.Block, error) {
+	stream, err := peer.Client.StreamBlocks(ctx, &types.BlocksRequest{
+		From: from,
+		To:   to,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	blocks := make([]*types.Block, 0, to-from+1)
+
+	for {
+		block, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if block.Index != from+uint64(len(blocks)) {
+			return nil, fmt.Errorf("%w: unexpected block %d", ErrNotFoundBlock, block.Index)
+		}
+
+		blocks = append(blocks, block)
+	}
+
+	if uint64(len(blocks)) != to-from+1 {
+		return nil, fmt.Errorf("%w: received %d of %d blocks", ErrNotFoundBlock, len(blocks), to-from+1)
+	}
+
+	return blocks, nil
+}
@@ -0,0 +1,157 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package node
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"authentication-chains/internal/types"
+)
+
+// maxTopologyDepth is the depth of the cluster tree if the request doesn't limit it or asks for a deeper one.
+const maxTopologyDepth = 8
+
+// getTopology returns the cluster tree. The request climbs the cluster heads up to the top cluster,
+// which collects the subtrees of its nodes. The node answers itself if its cluster head fails.
+// Visited nodes of the climb and of the way down are tracked separately, so the way down passes the climbers.
+func (n *Node) getTopology(ctx context.Context, request *types.TopologyRequest) (*types.TopologyResponse, error) {
+	ctx, logger := n.logger.StartTrace(ctx, "get topology")
+	defer logger.FinishTrace()
+
+	visited, err := n.visitTopology(request.Visited)
+	if err != nil {
+		return nil, err
+	}
+
+	depth := request.Depth
+	if depth == 0 || depth > maxTopologyDepth {
+		depth = maxTopologyDepth
+	}
+
+	if request.Subtree {
+		return &types.TopologyResponse{Nodes: []*types.TopologyNode{n.getSubtree(ctx, depth, visited)}}, nil
+	}
+
+	if clusterHead := n.getClusterHead(); clusterHead != nil && !containsDevice(visited, clusterHead.DeviceID) {
+		response, err := clusterHead.Client.GetTopology(ctx, &types.TopologyRequest{
+			Depth:   depth,
+			Visited: visited,
+		})
+		if err == nil {
+			return response, nil
+		}
+
+		logger.Errorf("get topology from cluster head %s: %s", clusterHead.Name, err)
+	}
+
+	visited = [][]byte{n.deviceID}
+	nodes := []*types.TopologyNode{n.getSubtree(ctx, depth, visited)}
+
+	if clusterNodes := n.getClusterNodes(); clusterNodes != nil {
+		nodes = append(nodes, n.getPeerSubtrees(ctx, clusterNodes.GetAll(), depth, visited)...)
+	}
+
+	return &types.TopologyResponse{Nodes: nodes}, nil
+}
+
+// getSubtree returns the node with the subtrees of its children down to the depth.
+func (n *Node) getSubtree(ctx context.Context, depth uint32, visited [][]byte) *types.TopologyNode {
+	node := &types.TopologyNode{
+		Peer: &types.Peer{
+			Name:          n.cfg.Name,
+			Level:         n.getLevel(),
+			DeviceId:      n.deviceID,
+			ClusterHeadId: n.getClusterHeadDeviceID(),
+			GrpcAddress:   n.cfg.GRPC.Address,
+		},
+	}
+
+	childrenNodes := n.getChildrenNodes()
+	if childrenNodes == nil {
+		return node
+	}
+
+	children := childrenNodes.GetAll()
+
+	switch {
+	case len(children) == 0:
+	case depth <= 1:
+		node.Truncated = true
+	default:
+		node.Children = n.getPeerSubtrees(ctx, children, depth-1, visited)
+	}
+
+	return node
+}
+
+// getPeerSubtrees requests the subtrees of the peers concurrently. Every level of the subtree gets
+// the heartbeat timeout, so the subtrees of the peers which don't answer in time are kept with the error.
+func (n *Node) getPeerSubtrees(ctx context.Context, peers []*Peer, depth uint32, visited [][]byte) []*types.TopologyNode {
+	ctx, cancel := context.WithTimeout(ctx, n.cfg.Heartbeat.Timeout*time.Duration(depth))
+	defer cancel()
+
+	var (
+		nodes = make([]*types.TopologyNode, len(peers))
+		group = n.workerPool.Group()
+	)
+
+	for i, peer := range peers {
+		i, peer := i, peer
+
+		group.Submit(func() { nodes[i] = n.getPeerSubtree(ctx, peer, depth, visited) })
+	}
+
+	group.Wait()
+
+	return nodes
+}
+
+// getPeerSubtree requests the subtree of the peer, the peer is kept with the error if its subtree isn't available.
+func (n *Node) getPeerSubtree(ctx context.Context, peer *Peer, depth uint32, visited [][]byte) *types.TopologyNode {
+	if containsDevice(visited, peer.DeviceID) {
+		return &types.TopologyNode{Peer: peer.ToProto(), Error: ErrTopologyCycle.Error()}
+	}
+
+	response, err := peer.Client.GetTopology(ctx, &types.TopologyRequest{
+		Depth:   depth,
+		Subtree: true,
+		Visited: visited,
+	})
+
+	switch {
+	case err != nil:
+		return &types.TopologyNode{Peer: peer.ToProto(), Error: err.Error()}
+	case len(response.Nodes) != 1 || response.Nodes[0].Peer == nil || !n.sameDevice(response.Nodes[0].Peer.DeviceId, peer.DeviceID):
+		return &types.TopologyNode{Peer: peer.ToProto(), Error: "subtree of another node"}
+	}
+
+	return response.Nodes[0]
+}
+
+// visitTopology adds the node to the nodes the topology request went through, the node must not be there yet.
+func (n *Node) visitTopology(visited [][]byte) ([][]byte, error) {
+	switch {
+	case containsDevice(visited, n.deviceID):
+		return nil, ErrTopologyCycle
+	case len(visited) >= maxLineage:
+		return nil, fmt.Errorf("%w: %d nodes are visited", ErrTopologyCycle, len(visited))
+	}
+
+	return append(visited[:len(visited):len(visited)], n.deviceID), nil
+}
+
+// containsDevice checks if the device id is in the list.
+func containsDevice(ids [][]byte, deviceID []byte) bool {
+	for _, id := range ids {
+		if bytes.Equal(id, deviceID) {
+			return true
+		}
+	}
+
+	return false
+}
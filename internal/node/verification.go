@@ -0,0 +1,250 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package node
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"authentication-chains/internal/cipher"
+	"authentication-chains/internal/types"
+)
+
+// maxVerificationHops limits the number of nodes the device verification goes through.
+const maxVerificationHops = 16
+
+// findBlock returns the block of the device authentication entry and the hops it's found through,
+// the last hop is made by the node. Blocks of the lower levels are fetched from the child cluster of the device.
+func (n *Node) findBlock(ctx context.Context, request *types.FindBlockRequest) (*types.Block, []*types.VerificationHop, error) {
+	entry, level, err := n.findAuthenticationEntry(ctx, request.DeviceId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !bytes.Equal(entry.BlockHash, request.Hash) || entry.BlockIndex != request.Index {
+		return nil, nil, fmt.Errorf("%w: entry of the device is made by another block", ErrNotFoundBlock)
+	}
+
+	var (
+		block *types.Block
+		hops  []*types.VerificationHop
+	)
+
+	if level == n.getLevel() {
+		if block, err = n.chain.GetBlock(entry.BlockIndex); err != nil {
+			return nil, nil, err
+		}
+
+		if !bytes.Equal(block.Hash, entry.BlockHash) {
+			return nil, nil, fmt.Errorf("%w: block hash mismatch", ErrNotFoundBlock)
+		}
+	} else if block, hops, err = n.findChildBlock(ctx, request.DeviceId, entry); err != nil {
+		return nil, nil, err
+	}
+
+	hop, err := n.newVerificationHop(request.DeviceId, entry.BlockHash, entry.BlockIndex)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return block, append(hops, hop), nil
+}
+
+// findChildBlock fetches the block of the lower level entry from the child cluster the device is registered in.
+// The block is validated against the entry and the hops of the child are audited.
+func (n *Node) findChildBlock(
+	ctx context.Context,
+	deviceID []byte,
+	entry *types.AuthenticationEntry,
+) (*types.Block, []*types.VerificationHop, error) {
+	ctx, logger := n.logger.StartTrace(ctx, "find child block")
+	logger = logger.WithFields("device_id", fmt.Sprintf("%x", deviceID), "block_hash", fmt.Sprintf("%x", entry.BlockHash))
+	defer logger.FinishTrace()
+
+	children, err := n.getChildren(ctx, entry.ClusterHeadId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	request := &types.FindBlockRequest{
+		Hash:     entry.BlockHash,
+		Index:    entry.BlockIndex,
+		DeviceId: deviceID,
+	}
+
+	// nodes of the child cluster share the chain, so the others are asked if the node of the device fails
+	for _, child := range children {
+		response, err := child.Client.FindBlock(ctx, request)
+		if err != nil {
+			logger.Errorf("find block on node %s: %s", child.Name, err)
+			continue
+		}
+
+		if err = n.validateFoundBlock(ctx, response.Block, deviceID, entry); err != nil {
+			logger.Errorf("validate block of node %s: %s", child.Name, err)
+			continue
+		}
+
+		if err = n.auditHops(ctx, response.Hops, deviceID, entry.BlockHash, child.DeviceID); err != nil {
+			logger.Errorf("audit hops of node %s: %s", child.Name, err)
+			continue
+		}
+
+		logger.Debugw("block is found", "node", child.Name, "hops", len(response.Hops))
+
+		return response.Block, response.Hops, nil
+	}
+
+	return nil, nil, fmt.Errorf("%w: block isn't found in the child cluster", ErrNotFoundBlock)
+}
+
+// getChildren returns the children nodes starting from the one the cluster head belongs to.
+// Cluster heads of the lower levels are followed through the authentication tables up to a child of the node.
+func (n *Node) getChildren(ctx context.Context, clusterHeadID []byte) ([]*Peer, error) {
+	childrenNodes := n.getChildrenNodes()
+	if childrenNodes == nil {
+		return nil, fmt.Errorf("%w: node has no children", ErrUnknownPeer)
+	}
+
+	var preferred *Peer
+
+	for id, i := clusterHeadID, 0; i < maxLineage; i++ {
+		if preferred = childrenNodes.Get(n.currentDeviceID(id)); preferred != nil {
+			break
+		}
+
+		entry, _, err := n.findAuthenticationEntry(ctx, id)
+		if err != nil || len(entry.ClusterHeadId) == 0 || n.sameDevice(entry.ClusterHeadId, n.deviceID) {
+			break
+		}
+
+		id = entry.ClusterHeadId
+	}
+
+	if preferred == nil {
+		return childrenNodes.GetAll(), nil
+	}
+
+	children := []*Peer{preferred}
+
+	for _, child := range childrenNodes.GetAll() {
+		if child != preferred {
+			children = append(children, child)
+		}
+	}
+
+	return children, nil
+}
+
+// newVerificationHop creates the signed statement of the node that the device is authenticated by the block.
+func (n *Node) newVerificationHop(deviceID, blockHash []byte, blockIndex uint64) (*types.VerificationHop, error) {
+	hop := &types.VerificationHop{
+		Level:      n.getLevel(),
+		DeviceId:   deviceID,
+		BlockHash:  blockHash,
+		BlockIndex: blockIndex,
+	}
+
+	if err := n.cipher.SignVerificationHop(hop); err != nil {
+		return nil, err
+	}
+
+	return hop, nil
+}
+
+// auditHops verifies that every hop is signed by its node for the same block of the device
+// and the last hop is made by the node which responded.
+func (n *Node) auditHops(ctx context.Context, hops []*types.VerificationHop, deviceID, blockHash, responderID []byte) error {
+	switch {
+	case len(hops) == 0:
+		return fmt.Errorf("%w: hops are missing", ErrVerification)
+	case len(hops) > maxVerificationHops:
+		return fmt.Errorf("%w: %d hops exceed the limit", ErrVerification, len(hops))
+	case !n.sameDevice(hops[len(hops)-1].NodeId, responderID):
+		return fmt.Errorf("%w: last hop isn't made by the responder", ErrVerification)
+	}
+
+	for _, hop := range hops {
+		if !bytes.Equal(hop.DeviceId, deviceID) || !bytes.Equal(hop.BlockHash, blockHash) || hop.BlockIndex != hops[0].BlockIndex {
+			return fmt.Errorf("%w: hop of node %x is made for another block", ErrVerification, hop.NodeId)
+		}
+
+		publicKey, err := n.getPublicKey(ctx, hop.NodeId)
+		if err != nil {
+			return err
+		}
+
+		if err = cipher.VerifyVerificationHop(hop, publicKey); err != nil {
+			return fmt.Errorf("%w: %s", ErrVerification, err)
+		}
+	}
+
+	return nil
+}
+
+// validateFoundBlock verifies that the block is the block of the entry, its transactions are committed
+// by its hash and one of them authenticates the device. The authenticating transaction must be signed by the device.
+func (n *Node) validateFoundBlock(ctx context.Context, block *types.Block, deviceID []byte, entry *types.AuthenticationEntry) error {
+	switch {
+	case block == nil:
+		return fmt.Errorf("%w: block is missing", ErrVerification)
+	case !bytes.Equal(block.Hash, entry.BlockHash) || block.Index != entry.BlockIndex:
+		return fmt.Errorf("%w: block doesn't match the entry", ErrVerification)
+	}
+
+	hash, err := cipher.HashBlock(block)
+	if err != nil || !bytes.Equal(hash, block.Hash) {
+		return fmt.Errorf("%w: block hash mismatch", ErrVerification)
+	}
+
+	root, err := cipher.MerkleRoot(block)
+	if err != nil || !bytes.Equal(root, block.MerkleRoot) {
+		return fmt.Errorf("%w: merkle root mismatch", ErrVerification)
+	}
+
+	for _, dar := range block.Dars {
+		if bytes.Equal(cipher.DeviceID(dar.DeviceId), deviceID) {
+			if err = cipher.VerifyDAR(dar); err != nil {
+				return fmt.Errorf("%w: %s", ErrVerification, err)
+			}
+
+			return nil
+		}
+	}
+
+	for _, renewal := range block.Renewals {
+		if bytes.Equal(cipher.DeviceID(renewal.DeviceId), deviceID) {
+			publicKey, err := n.getPublicKey(ctx, renewal.DeviceId)
+			if err != nil {
+				return fmt.Errorf("%w: %s", ErrVerification, err)
+			}
+
+			if err = cipher.VerifyRenewal(renewal, publicKey); err != nil {
+				return fmt.Errorf("%w: %s", ErrVerification, err)
+			}
+
+			return nil
+		}
+	}
+
+	for _, rotation := range block.Rotations {
+		if bytes.Equal(rotation.NewDeviceId, deviceID) {
+			// the new key is vouched for by the key it replaces
+			publicKey, err := n.getPublicKey(ctx, rotation.DeviceId)
+			if err != nil {
+				return fmt.Errorf("%w: %s", ErrVerification, err)
+			}
+
+			if err = cipher.VerifyRotation(rotation, publicKey); err != nil {
+				return fmt.Errorf("%w: %s", ErrVerification, err)
+			}
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: block has no transaction of the device", ErrVerification)
+}
@@ -0,0 +1,180 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// boltFile is the name of the bbolt database file in the storage directory.
+const boltFile = "chain.bolt"
+
+// boltExpirationSize is the size of the expiration time prefix of the stored values.
+const boltExpirationSize = 8
+
+type (
+	// boltStorage implements storage on top of bbolt.
+	boltStorage struct {
+		db *bolt.DB
+	}
+
+	// boltTx implements storage transaction on top of bbolt transaction.
+	// Values are prefixed with the expiration unix time, zero means the value never expires.
+	boltTx struct {
+		tx *bolt.Tx
+	}
+)
+
+// NewBolt opens the bbolt storage in the directory.
+func NewBolt(directory string) (Storage, error) {
+	if err := os.MkdirAll(directory, 0o755); err != nil {
+		return nil, err
+	}
+
+	db, err := bolt.Open(filepath.Join(directory, boltFile), 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	return &boltStorage{db: db}, nil
+}
+
+// View executes the function within a read-only transaction.
+func (s *boltStorage) View(fn func(tx Tx) error) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		return fn(&boltTx{tx: tx})
+	})
+}
+
+// Update executes the function within a read-write transaction.
+func (s *boltStorage) Update(fn func(tx Tx) error) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return fn(&boltTx{tx: tx})
+	})
+}
+
+// Close closes the storage.
+func (s *boltStorage) Close() error {
+	return s.db.Close()
+}
+
+// Get returns the value of the key in the bucket.
+func (t *boltTx) Get(bucket string, key []byte) ([]byte, error) {
+	b := t.tx.Bucket([]byte(bucket))
+	if b == nil {
+		return nil, ErrNotFound
+	}
+
+	value, ok := boltValue(b.Get(key))
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return value, nil
+}
+
+// GetAll returns all entries of the bucket.
+func (t *boltTx) GetAll(bucket string) ([]Entry, error) {
+	return t.scan(bucket, nil, nil)
+}
+
+// RangeScan returns entries of the bucket with keys in [start, end].
+func (t *boltTx) RangeScan(bucket string, start, end []byte) ([]Entry, error) {
+	return t.scan(bucket, start, end)
+}
+
+// Put sets the value of the key in the bucket.
+func (t *boltTx) Put(bucket string, key, value []byte, ttl uint32) error {
+	if !t.tx.Writable() {
+		return ErrReadOnly
+	}
+
+	b, err := t.tx.CreateBucketIfNotExists([]byte(bucket))
+	if err != nil {
+		return err
+	}
+
+	var expiresAt uint64
+	if ttl != 0 {
+		expiresAt = uint64(time.Now().Unix()) + uint64(ttl)
+	}
+
+	data := make([]byte, boltExpirationSize+len(value))
+	binary.BigEndian.PutUint64(data, expiresAt)
+	copy(data[boltExpirationSize:], value)
+
+	return b.Put(key, data)
+}
+
+// Delete removes the key from the bucket.
+func (t *boltTx) Delete(bucket string, key []byte) error {
+	if !t.tx.Writable() {
+		return ErrReadOnly
+	}
+
+	b := t.tx.Bucket([]byte(bucket))
+	if b == nil {
+		return ErrNotFound
+	}
+
+	if _, ok := boltValue(b.Get(key)); !ok {
+		return ErrNotFound
+	}
+
+	return b.Delete(key)
+}
+
+// scan returns live entries of the bucket with keys in [start, end], nil bounds are open.
+func (t *boltTx) scan(bucket string, start, end []byte) ([]Entry, error) {
+	b := t.tx.Bucket([]byte(bucket))
+	if b == nil {
+		return nil, ErrNotFound
+	}
+
+	var (
+		entries []Entry
+		cursor  = b.Cursor()
+		key     []byte
+		data    []byte
+	)
+
+	if start == nil {
+		key, data = cursor.First()
+	} else {
+		key, data = cursor.Seek(start)
+	}
+
+	for ; key != nil && (end == nil || bytes.Compare(key, end) <= 0); key, data = cursor.Next() {
+		if value, ok := boltValue(data); ok {
+			entries = append(entries, Entry{Key: bytes.Clone(key), Value: value})
+		}
+	}
+
+	if len(entries) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return entries, nil
+}
+
+// boltValue strips the expiration time prefix of the stored data and reports if the value is alive.
+func boltValue(data []byte) ([]byte, bool) {
+	if len(data) < boltExpirationSize {
+		return nil, false
+	}
+
+	expiresAt := binary.BigEndian.Uint64(data)
+	if expiresAt != 0 && uint64(time.Now().Unix()) >= expiresAt {
+		return nil, false
+	}
+
+	return bytes.Clone(data[boltExpirationSize:]), true
+}
@@ -0,0 +1,15 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package storage
+
+import (
+	"errors"
+)
+
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrReadOnly      = errors.New("transaction is read-only")
+	ErrUnknownEngine = errors.New("unknown storage engine")
+)
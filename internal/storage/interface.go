@@ -0,0 +1,39 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package storage
+
+type (
+	// Storage - describe an interface for working with key-value storage of buckets.
+	Storage interface {
+		// View executes the function within a read-only transaction.
+		View(fn func(tx Tx) error) error
+		// Update executes the function within a read-write transaction.
+		// Changes are committed if the function returns nil, otherwise they are discarded.
+		Update(fn func(tx Tx) error) error
+		// Close closes the storage.
+		Close() error
+	}
+
+	// Tx - describe an interface for working with storage transaction.
+	Tx interface {
+		// Get returns the value of the key in the bucket or ErrNotFound.
+		Get(bucket string, key []byte) ([]byte, error)
+		// GetAll returns all entries of the bucket ordered by key or ErrNotFound if the bucket is empty.
+		GetAll(bucket string) ([]Entry, error)
+		// RangeScan returns entries of the bucket with keys in [start, end] ordered by key
+		// or ErrNotFound if there are no such entries.
+		RangeScan(bucket string, start, end []byte) ([]Entry, error)
+		// Put sets the value of the key in the bucket, the entry expires after ttl seconds unless ttl is zero.
+		Put(bucket string, key, value []byte, ttl uint32) error
+		// Delete removes the key from the bucket or returns ErrNotFound.
+		Delete(bucket string, key []byte) error
+	}
+)
+
+// Entry is a key-value pair of the bucket.
+type Entry struct {
+	Key   []byte
+	Value []byte
+}
@@ -0,0 +1,210 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package storage
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+	"time"
+)
+
+type (
+	// memoryStorage implements storage in memory, it is intended for tests and simulations.
+	memoryStorage struct {
+		mutex   sync.RWMutex
+		buckets map[string]map[string]memoryItem
+	}
+
+	// memoryTx implements storage transaction in memory. Changes are staged until the transaction commits.
+	memoryTx struct {
+		storage  *memoryStorage
+		writable bool
+		changes  map[string]map[string]*memoryItem
+	}
+
+	// memoryItem is a stored value with its expiration time.
+	memoryItem struct {
+		value     []byte
+		expiresAt time.Time
+	}
+)
+
+// NewMemory creates an empty in-memory storage.
+func NewMemory() Storage {
+	return &memoryStorage{
+		buckets: make(map[string]map[string]memoryItem),
+	}
+}
+
+// View executes the function within a read-only transaction.
+func (s *memoryStorage) View(fn func(tx Tx) error) error {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return fn(&memoryTx{storage: s})
+}
+
+// Update executes the function within a read-write transaction.
+func (s *memoryStorage) Update(fn func(tx Tx) error) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	tx := &memoryTx{
+		storage:  s,
+		writable: true,
+		changes:  make(map[string]map[string]*memoryItem),
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	tx.commit()
+
+	return nil
+}
+
+// Close closes the storage.
+func (s *memoryStorage) Close() error {
+	return nil
+}
+
+// Get returns the value of the key in the bucket.
+func (t *memoryTx) Get(bucket string, key []byte) ([]byte, error) {
+	item, ok := t.get(bucket, string(key))
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return item.value, nil
+}
+
+// GetAll returns all entries of the bucket.
+func (t *memoryTx) GetAll(bucket string) ([]Entry, error) {
+	return t.scan(bucket, func(key string) bool { return true })
+}
+
+// RangeScan returns entries of the bucket with keys in [start, end].
+func (t *memoryTx) RangeScan(bucket string, start, end []byte) ([]Entry, error) {
+	return t.scan(bucket, func(key string) bool {
+		return bytes.Compare([]byte(key), start) >= 0 && bytes.Compare([]byte(key), end) <= 0
+	})
+}
+
+// Put sets the value of the key in the bucket.
+func (t *memoryTx) Put(bucket string, key, value []byte, ttl uint32) error {
+	if !t.writable {
+		return ErrReadOnly
+	}
+
+	item := &memoryItem{value: bytes.Clone(value)}
+	if ttl != 0 {
+		item.expiresAt = time.Now().Add(time.Duration(ttl) * time.Second)
+	}
+
+	t.stage(bucket, string(key), item)
+
+	return nil
+}
+
+// Delete removes the key from the bucket.
+func (t *memoryTx) Delete(bucket string, key []byte) error {
+	if !t.writable {
+		return ErrReadOnly
+	}
+
+	if _, ok := t.get(bucket, string(key)); !ok {
+		return ErrNotFound
+	}
+
+	t.stage(bucket, string(key), nil)
+
+	return nil
+}
+
+// get returns the live item of the key, staged changes take precedence over stored ones.
+func (t *memoryTx) get(bucket, key string) (memoryItem, bool) {
+	if change, ok := t.changes[bucket][key]; ok {
+		if change == nil || change.isExpired() {
+			return memoryItem{}, false
+		}
+
+		return *change, true
+	}
+
+	item, ok := t.storage.buckets[bucket][key]
+	if !ok || item.isExpired() {
+		return memoryItem{}, false
+	}
+
+	return item, true
+}
+
+// scan returns live entries of the bucket with keys matching the filter ordered by key.
+func (t *memoryTx) scan(bucket string, match func(key string) bool) ([]Entry, error) {
+	keys := make(map[string]bool)
+
+	for key := range t.storage.buckets[bucket] {
+		keys[key] = true
+	}
+
+	for key := range t.changes[bucket] {
+		keys[key] = true
+	}
+
+	var entries []Entry
+
+	for key := range keys {
+		if !match(key) {
+			continue
+		}
+
+		if item, ok := t.get(bucket, key); ok {
+			entries = append(entries, Entry{Key: []byte(key), Value: item.value})
+		}
+	}
+
+	if len(entries) == 0 {
+		return nil, ErrNotFound
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].Key, entries[j].Key) < 0
+	})
+
+	return entries, nil
+}
+
+// stage records the change of the key, nil item means deletion.
+func (t *memoryTx) stage(bucket, key string, item *memoryItem) {
+	if t.changes[bucket] == nil {
+		t.changes[bucket] = make(map[string]*memoryItem)
+	}
+
+	t.changes[bucket][key] = item
+}
+
+// commit applies staged changes to the storage.
+func (t *memoryTx) commit() {
+	for bucket, changes := range t.changes {
+		if t.storage.buckets[bucket] == nil {
+			t.storage.buckets[bucket] = make(map[string]memoryItem)
+		}
+
+		for key, item := range changes {
+			if item == nil {
+				delete(t.storage.buckets[bucket], key)
+				continue
+			}
+
+			t.storage.buckets[bucket][key] = *item
+		}
+	}
+}
+
+func (i memoryItem) isExpired() bool {
+	return !i.expiresAt.IsZero() && time.Now().After(i.expiresAt)
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package storage
+
+import (
+	"errors"
+
+	"github.com/nutsdb/nutsdb"
+)
+
+type (
+	// nutsStorage implements storage on top of nutsdb.
+	nutsStorage struct {
+		db *nutsdb.DB
+	}
+
+	// nutsTx implements storage transaction on top of nutsdb transaction.
+	nutsTx struct {
+		tx *nutsdb.Tx
+	}
+)
+
+// NewNutsDB opens the nutsdb storage in the directory.
+func NewNutsDB(directory string) (Storage, error) {
+	db, err := nutsdb.Open(
+		nutsdb.DefaultOptions,
+		nutsdb.WithDir(directory),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &nutsStorage{db: db}, nil
+}
+
+// View executes the function within a read-only transaction.
+func (s *nutsStorage) View(fn func(tx Tx) error) error {
+	return nutsManaged(s.db.View, fn)
+}
+
+// Update executes the function within a read-write transaction.
+func (s *nutsStorage) Update(fn func(tx Tx) error) error {
+	return nutsManaged(s.db.Update, fn)
+}
+
+// nutsManaged executes the function within the nutsdb transaction. nutsdb formats the error of the function
+// with the rollback error, so the error of the function is returned as it is to keep it comparable.
+func nutsManaged(managed func(fn func(tx *nutsdb.Tx) error) error, fn func(tx Tx) error) error {
+	var fnErr error
+
+	err := managed(func(tx *nutsdb.Tx) error {
+		fnErr = fn(&nutsTx{tx: tx})
+		return fnErr
+	})
+	if fnErr != nil {
+		return fnErr
+	}
+
+	return err
+}
+
+// Close closes the storage.
+func (s *nutsStorage) Close() error {
+	return s.db.Close()
+}
+
+// Get returns the value of the key in the bucket.
+func (t *nutsTx) Get(bucket string, key []byte) ([]byte, error) {
+	entry, err := t.tx.Get(bucket, key)
+	if err != nil {
+		return nil, nutsError(err)
+	}
+
+	return entry.Value, nil
+}
+
+// GetAll returns all entries of the bucket.
+func (t *nutsTx) GetAll(bucket string) ([]Entry, error) {
+	entries, err := t.tx.GetAll(bucket)
+	if err != nil {
+		return nil, nutsError(err)
+	}
+
+	return nutsEntries(entries), nil
+}
+
+// RangeScan returns entries of the bucket with keys in [start, end].
+func (t *nutsTx) RangeScan(bucket string, start, end []byte) ([]Entry, error) {
+	entries, err := t.tx.RangeScan(bucket, start, end)
+	if err != nil {
+		return nil, nutsError(err)
+	}
+
+	return nutsEntries(entries), nil
+}
+
+// Put sets the value of the key in the bucket.
+func (t *nutsTx) Put(bucket string, key, value []byte, ttl uint32) error {
+	return nutsError(t.tx.Put(bucket, key, value, ttl))
+}
+
+// Delete removes the key from the bucket.
+func (t *nutsTx) Delete(bucket string, key []byte) error {
+	return nutsError(t.tx.Delete(bucket, key))
+}
+
+// nutsError converts nutsdb errors of missing data to ErrNotFound and of writes in View to ErrReadOnly.
+func nutsError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, nutsdb.ErrTxNotWritable):
+		return ErrReadOnly
+	case errors.Is(err, nutsdb.ErrKeyNotFound),
+		errors.Is(err, nutsdb.ErrNotFoundKey),
+		errors.Is(err, nutsdb.ErrNotFoundBucket),
+		errors.Is(err, nutsdb.ErrBucketNotFound),
+		errors.Is(err, nutsdb.ErrBucketEmpty),
+		errors.Is(err, nutsdb.ErrRangeScan):
+		return ErrNotFound
+	default:
+		return err
+	}
+}
+
+func nutsEntries(entries nutsdb.Entries) []Entry {
+	result := make([]Entry, len(entries))
+
+	for i, entry := range entries {
+		result[i] = Entry{Key: entry.Key, Value: entry.Value}
+	}
+
+	return result
+}
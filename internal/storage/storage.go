@@ -0,0 +1,25 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package storage
+
+import (
+	"fmt"
+
+	"authentication-chains/internal/config"
+)
+
+// New opens the storage of the configured engine, nutsdb is used by default.
+func New(cfg config.Storage) (Storage, error) {
+	switch cfg.Engine {
+	case config.EngineNutsDB, "":
+		return NewNutsDB(cfg.Directory)
+	case config.EngineBolt:
+		return NewBolt(cfg.Directory)
+	case config.EngineMemory:
+		return NewMemory(), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEngine, cfg.Engine)
+	}
+}
@@ -0,0 +1,347 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+const testBucket = "test"
+
+// engines are the storage engines the conformance tests run against.
+var engines = []struct {
+	name string
+	open func(t *testing.T) Storage
+}{
+	{
+		name: "nutsdb",
+		open: func(t *testing.T) Storage {
+			db, err := NewNutsDB(t.TempDir())
+			if err != nil {
+				t.Fatalf("open nutsdb: %s", err)
+			}
+
+			return db
+		},
+	},
+	{
+		name: "bbolt",
+		open: func(t *testing.T) Storage {
+			db, err := NewBolt(t.TempDir())
+			if err != nil {
+				t.Fatalf("open bbolt: %s", err)
+			}
+
+			return db
+		},
+	},
+	{
+		name: "memory",
+		open: func(t *testing.T) Storage {
+			return NewMemory()
+		},
+	},
+}
+
+func TestConformance(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, db Storage)
+	}{
+		{name: "missing bucket", run: testMissingBucket},
+		{name: "missing key", run: testMissingKey},
+		{name: "put and delete", run: testPutDelete},
+		{name: "rollback", run: testRollback},
+		{name: "range scan", run: testRangeScan},
+		{name: "read-only view", run: testReadOnlyView},
+		{name: "ttl expiry", run: testTTLExpiry},
+	}
+
+	for _, engine := range engines {
+		engine := engine
+
+		t.Run(engine.name, func(t *testing.T) {
+			t.Parallel()
+
+			for _, test := range tests {
+				t.Run(test.name, func(t *testing.T) {
+					db := engine.open(t)
+					defer db.Close()
+
+					test.run(t, db)
+				})
+			}
+		})
+	}
+}
+
+func testMissingBucket(t *testing.T, db Storage) {
+	err := db.View(func(tx Tx) error {
+		if _, err := tx.Get(testBucket, []byte("key")); !errors.Is(err, ErrNotFound) {
+			t.Errorf("get: got %v, want %v", err, ErrNotFound)
+		}
+
+		if _, err := tx.GetAll(testBucket); !errors.Is(err, ErrNotFound) {
+			t.Errorf("get all: got %v, want %v", err, ErrNotFound)
+		}
+
+		if _, err := tx.RangeScan(testBucket, []byte("a"), []byte("z")); !errors.Is(err, ErrNotFound) {
+			t.Errorf("range scan: got %v, want %v", err, ErrNotFound)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("view: %s", err)
+	}
+
+	err = db.Update(func(tx Tx) error {
+		if err := tx.Delete(testBucket, []byte("key")); !errors.Is(err, ErrNotFound) {
+			t.Errorf("delete: got %v, want %v", err, ErrNotFound)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("update: %s", err)
+	}
+}
+
+func testMissingKey(t *testing.T, db Storage) {
+	putEntries(t, db, Entry{Key: []byte("key"), Value: []byte("value")})
+
+	err := db.Update(func(tx Tx) error {
+		if _, err := tx.Get(testBucket, []byte("other")); !errors.Is(err, ErrNotFound) {
+			t.Errorf("get: got %v, want %v", err, ErrNotFound)
+		}
+
+		if err := tx.Delete(testBucket, []byte("other")); !errors.Is(err, ErrNotFound) {
+			t.Errorf("delete: got %v, want %v", err, ErrNotFound)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("update: %s", err)
+	}
+}
+
+func testPutDelete(t *testing.T, db Storage) {
+	putEntries(t, db, Entry{Key: []byte("key"), Value: []byte("value")})
+	expectValue(t, db, []byte("key"), []byte("value"))
+
+	putEntries(t, db, Entry{Key: []byte("key"), Value: []byte("other")})
+	expectValue(t, db, []byte("key"), []byte("other"))
+
+	if err := db.Update(func(tx Tx) error {
+		return tx.Delete(testBucket, []byte("key"))
+	}); err != nil {
+		t.Fatalf("delete: %s", err)
+	}
+
+	expectValue(t, db, []byte("key"), nil)
+}
+
+func testRollback(t *testing.T, db Storage) {
+	failure := errors.New("failure")
+
+	err := db.Update(func(tx Tx) error {
+		if err := tx.Put(testBucket, []byte("key"), []byte("value"), 0); err != nil {
+			return err
+		}
+
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("update: got %v, want %v", err, failure)
+	}
+
+	expectValue(t, db, []byte("key"), nil)
+}
+
+func testRangeScan(t *testing.T, db Storage) {
+	putEntries(t, db,
+		Entry{Key: []byte("d"), Value: []byte("4")},
+		Entry{Key: []byte("a"), Value: []byte("1")},
+		Entry{Key: []byte("c"), Value: []byte("3")},
+		Entry{Key: []byte("b"), Value: []byte("2")},
+		Entry{Key: []byte("e"), Value: []byte("5")},
+	)
+
+	tests := []struct {
+		name       string
+		start, end string
+		want       []string
+	}{
+		{name: "inclusive bounds", start: "b", end: "d", want: []string{"b", "c", "d"}},
+		{name: "single key", start: "c", end: "c", want: []string{"c"}},
+		{name: "bounds between keys", start: "bb", end: "cc", want: []string{"c"}},
+		{name: "whole bucket", start: "", end: "z", want: []string{"a", "b", "c", "d", "e"}},
+		{name: "no keys", start: "f", end: "z"},
+	}
+
+	err := db.View(func(tx Tx) error {
+		for _, test := range tests {
+			entries, err := tx.RangeScan(testBucket, []byte(test.start), []byte(test.end))
+
+			if len(test.want) == 0 {
+				if !errors.Is(err, ErrNotFound) {
+					t.Errorf("%s: got %v, want %v", test.name, err, ErrNotFound)
+				}
+
+				continue
+			}
+
+			if err != nil {
+				t.Errorf("%s: %s", test.name, err)
+				continue
+			}
+
+			if got := entryKeys(entries); !equalKeys(got, test.want) {
+				t.Errorf("%s: got keys %q, want %q", test.name, got, test.want)
+			}
+		}
+
+		entries, err := tx.GetAll(testBucket)
+		if err != nil {
+			return err
+		}
+
+		if got, want := entryKeys(entries), []string{"a", "b", "c", "d", "e"}; !equalKeys(got, want) {
+			t.Errorf("get all: got keys %q, want %q", got, want)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("view: %s", err)
+	}
+}
+
+func testReadOnlyView(t *testing.T, db Storage) {
+	putEntries(t, db, Entry{Key: []byte("key"), Value: []byte("value")})
+
+	err := db.View(func(tx Tx) error {
+		if err := tx.Put(testBucket, []byte("other"), []byte("value"), 0); !errors.Is(err, ErrReadOnly) {
+			t.Errorf("put: got %v, want %v", err, ErrReadOnly)
+		}
+
+		if err := tx.Delete(testBucket, []byte("key")); !errors.Is(err, ErrReadOnly) {
+			t.Errorf("delete: got %v, want %v", err, ErrReadOnly)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("view: %s", err)
+	}
+
+	expectValue(t, db, []byte("key"), []byte("value"))
+	expectValue(t, db, []byte("other"), nil)
+}
+
+func testTTLExpiry(t *testing.T, db Storage) {
+	if testing.Short() {
+		t.Skip("ttl expiry takes seconds")
+	}
+
+	putEntries(t, db, Entry{Key: []byte("kept"), Value: []byte("value")})
+
+	if err := db.Update(func(tx Tx) error {
+		return tx.Put(testBucket, []byte("expiring"), []byte("value"), 1)
+	}); err != nil {
+		t.Fatalf("put: %s", err)
+	}
+
+	expectValue(t, db, []byte("expiring"), []byte("value"))
+
+	time.Sleep(2100 * time.Millisecond)
+
+	expectValue(t, db, []byte("expiring"), nil)
+	expectValue(t, db, []byte("kept"), []byte("value"))
+
+	err := db.View(func(tx Tx) error {
+		entries, err := tx.GetAll(testBucket)
+		if err != nil {
+			return err
+		}
+
+		if got, want := entryKeys(entries), []string{"kept"}; !equalKeys(got, want) {
+			t.Errorf("get all: got keys %q, want %q", got, want)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("view: %s", err)
+	}
+}
+
+// putEntries puts the entries without ttl into the test bucket.
+func putEntries(t *testing.T, db Storage, entries ...Entry) {
+	t.Helper()
+
+	if err := db.Update(func(tx Tx) error {
+		for _, entry := range entries {
+			if err := tx.Put(testBucket, entry.Key, entry.Value, 0); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		t.Fatalf("put entries: %s", err)
+	}
+}
+
+// expectValue checks the value of the key in the test bucket, nil means the key must be missing.
+func expectValue(t *testing.T, db Storage, key, want []byte) {
+	t.Helper()
+
+	var (
+		value []byte
+		err   error
+	)
+
+	_ = db.View(func(tx Tx) error {
+		value, err = tx.Get(testBucket, key)
+		return nil
+	})
+
+	switch {
+	case want == nil && !errors.Is(err, ErrNotFound):
+		t.Errorf("get %s: got %q, %v, want %v", key, value, err, ErrNotFound)
+	case want != nil && err != nil:
+		t.Errorf("get %s: %s", key, err)
+	case want != nil && !bytes.Equal(value, want):
+		t.Errorf("get %s: got %q, want %q", key, value, want)
+	}
+}
+
+func entryKeys(entries []Entry) []string {
+	keys := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		keys = append(keys, string(entry.Key))
+	}
+
+	return keys
+}
+
+func equalKeys(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+
+	return true
+}
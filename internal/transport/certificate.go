@@ -0,0 +1,97 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package transport
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"math/big"
+	"sync"
+	"time"
+
+	"authentication-chains/internal/cipher"
+)
+
+// Validity of the self-signed certificates, the certificate is reissued before it expires.
+const (
+	certificateValidity    = 24 * time.Hour
+	certificateRenewBefore = time.Hour
+	certificateClockSkew   = time.Hour
+)
+
+// selfSigned issues certificates self-signed with the current key of the identity.
+// The certificate is reissued when the key is rotated or the certificate is about to expire.
+type selfSigned struct {
+	identity    Identity
+	mutex       sync.Mutex
+	deviceID    []byte
+	certificate *tls.Certificate
+}
+
+func newSelfSigned(identity Identity) *selfSigned {
+	return &selfSigned{identity: identity}
+}
+
+// get returns the certificate of the current key.
+func (s *selfSigned) get() (*tls.Certificate, error) {
+	c := s.identity()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.certificate != nil && bytes.Equal(s.deviceID, c.DeviceID()) &&
+		time.Until(s.certificate.Leaf.NotAfter) > certificateRenewBefore {
+		return s.certificate, nil
+	}
+
+	certificate, err := selfSignedCertificate(c)
+	if err != nil {
+		return nil, err
+	}
+
+	s.deviceID, s.certificate = c.DeviceID(), certificate
+
+	return certificate, nil
+}
+
+// selfSignedCertificate issues the certificate of the key signed by the key itself, its subject is the device id.
+func selfSignedCertificate(c cipher.Cipher) (*tls.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: hex.EncodeToString(c.DeviceID())},
+		NotBefore:             now.Add(-certificateClockSkew),
+		NotAfter:              now.Add(certificateValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, c.GetPublicKey(), c.GetPrivateKey())
+	if err != nil {
+		return nil, err
+	}
+
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  c.GetPrivateKey(),
+		Leaf:        leaf,
+	}, nil
+}
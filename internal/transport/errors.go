@@ -0,0 +1,16 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package transport
+
+import (
+	"errors"
+)
+
+var (
+	ErrUnknownMode        = errors.New("unknown tls mode")
+	ErrCertificateMissing = errors.New("certificate is missing")
+	ErrInvalidCertificate = errors.New("invalid certificate")
+	ErrPinMismatch        = errors.New("certificate doesn't match the pinned device id")
+)
@@ -0,0 +1,227 @@
+/*
+ * Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+ */
+
+package transport
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/peer"
+
+	"authentication-chains/internal/cipher"
+	"authentication-chains/internal/config"
+)
+
+type (
+	// Identity returns the current key of the node or the client, certificates of the pinned mode are signed with it.
+	Identity func() cipher.Cipher
+
+	// Pin checks the device id of the certificate presented by the other side.
+	Pin func(deviceID []byte) bool
+)
+
+// ServerCredentials creates the transport credentials of the node server.
+// In the pinned mode every client must present a certificate self-signed with its device key.
+func ServerCredentials(cfg config.TLS, identity Identity) (credentials.TransportCredentials, error) {
+	switch cfg.Mode {
+	case "", config.TLSModeDisabled:
+		return insecure.NewCredentials(), nil
+	case config.TLSModePinned:
+		certificates := newSelfSigned(identity)
+
+		return credentials.NewTLS(&tls.Config{
+			MinVersion: tls.VersionTLS13,
+			ClientAuth: tls.RequireAnyClientCert,
+			GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+				return certificates.get()
+			},
+			VerifyPeerCertificate: verifyPinned(nil),
+		}), nil
+	case config.TLSModeTLS, config.TLSModeMutual:
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownMode, cfg.Mode)
+	}
+
+	if cfg.CertFile == "" {
+		return nil, fmt.Errorf("%w: cert file is required in %s mode", ErrCertificateMissing, cfg.Mode)
+	}
+
+	certificate, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{certificate},
+	}
+
+	if cfg.Mode == config.TLSModeMutual {
+		if cfg.CAFile == "" {
+			return nil, fmt.Errorf("%w: ca file is required to verify clients", ErrCertificateMissing)
+		}
+
+		if tlsConfig.ClientCAs, err = loadCertPool(cfg.CAFile); err != nil {
+			return nil, err
+		}
+
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// DialCredentials creates the transport credentials of the connection to a node.
+// In the pinned mode the certificate of the node is checked by the pin, any device id is accepted if it's nil.
+func DialCredentials(cfg config.TLS, identity Identity, pin Pin) (credentials.TransportCredentials, error) {
+	switch cfg.Mode {
+	case "", config.TLSModeDisabled:
+		return insecure.NewCredentials(), nil
+	case config.TLSModePinned:
+		certificates := newSelfSigned(identity)
+
+		return credentials.NewTLS(&tls.Config{
+			MinVersion: tls.VersionTLS13,
+			// the certificate is self-signed, it's verified against the pinned device id instead of a CA
+			InsecureSkipVerify: true,
+			GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+				return certificates.get()
+			},
+			VerifyPeerCertificate: verifyPinned(pin),
+		}), nil
+	case config.TLSModeTLS, config.TLSModeMutual:
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownMode, cfg.Mode)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: cfg.ServerName,
+	}
+
+	if cfg.CAFile != "" {
+		pool, err := loadCertPool(cfg.CAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.Mode == config.TLSModeMutual {
+		certificate, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// PinDeviceID returns the pin of the hex device id, it's nil if the id is empty.
+func PinDeviceID(deviceID string) (Pin, error) {
+	if deviceID == "" {
+		return nil, nil
+	}
+
+	id, err := hex.DecodeString(deviceID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pinned device id: %w", err)
+	}
+
+	return func(deviceID []byte) bool {
+		return bytes.Equal(deviceID, id)
+	}, nil
+}
+
+// DeviceID returns the device id of the certificate the other side of the connection presented,
+// it's nil if the connection isn't secured or the certificate is missing.
+func DeviceID(info credentials.AuthInfo) []byte {
+	tlsInfo, ok := info.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.PeerCertificates) == 0 {
+		return nil
+	}
+
+	deviceID, err := certificateDeviceID(tlsInfo.State.PeerCertificates[0])
+	if err != nil {
+		return nil
+	}
+
+	return deviceID
+}
+
+// PeerDeviceID returns the device id of the certificate presented by the caller of the request.
+func PeerDeviceID(ctx context.Context) []byte {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	return DeviceID(p.AuthInfo)
+}
+
+// verifyPinned verifies that the certificate is self-signed and its device id is accepted by the pin.
+// Possession of the certificate key is proven by the handshake itself.
+func verifyPinned(pin Pin) func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return ErrCertificateMissing
+		}
+
+		certificate, err := x509.ParseCertificate(rawCerts[0])
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidCertificate, err)
+		}
+
+		err = certificate.CheckSignature(certificate.SignatureAlgorithm, certificate.RawTBSCertificate, certificate.Signature)
+		if err != nil {
+			return fmt.Errorf("%w: certificate is not self-signed: %s", ErrInvalidCertificate, err)
+		}
+
+		deviceID, err := certificateDeviceID(certificate)
+		if err != nil {
+			return err
+		}
+
+		if pin != nil && !pin(deviceID) {
+			return fmt.Errorf("%w: %x", ErrPinMismatch, deviceID)
+		}
+
+		return nil
+	}
+}
+
+// certificateDeviceID returns the fingerprint of the certificate public key.
+func certificateDeviceID(certificate *x509.Certificate) ([]byte, error) {
+	if _, err := cipher.KeyAlgorithm(certificate.PublicKey); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCertificate, err)
+	}
+
+	return cipher.Fingerprint(certificate.PublicKey), nil
+}
+
+// loadCertPool loads the PEM certificates of the file into a pool.
+func loadCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("%w: no certificates in %s", ErrInvalidCertificate, path)
+	}
+
+	return pool, nil
+}
@@ -0,0 +1,688 @@
+//
+// Copyright © 2023 Khruslov Dmytro khruslov.work@gmail.com
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        v4.23.4
+// source: archive.proto
+
+package types
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ArchiveHeader is the first record of the node archive.
+type ArchiveHeader struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version        uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	NodeName       string `protobuf:"bytes,2,opt,name=node_name,json=nodeName,proto3" json:"node_name,omitempty"`
+	Level          uint32 `protobuf:"varint,3,opt,name=level,proto3" json:"level,omitempty"`
+	GenesisHash    []byte `protobuf:"bytes,4,opt,name=genesis_hash,json=genesisHash,proto3" json:"genesis_hash,omitempty"`
+	LastBlockIndex uint64 `protobuf:"varint,5,opt,name=last_block_index,json=lastBlockIndex,proto3" json:"last_block_index,omitempty"`
+	CreatedAt      int64  `protobuf:"varint,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+}
+
+func (x *ArchiveHeader) Reset() {
+	*x = ArchiveHeader{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_archive_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ArchiveHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveHeader) ProtoMessage() {}
+
+func (x *ArchiveHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_archive_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveHeader.ProtoReflect.Descriptor instead.
+func (*ArchiveHeader) Descriptor() ([]byte, []int) {
+	return file_archive_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ArchiveHeader) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *ArchiveHeader) GetNodeName() string {
+	if x != nil {
+		return x.NodeName
+	}
+	return ""
+}
+
+func (x *ArchiveHeader) GetLevel() uint32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+func (x *ArchiveHeader) GetGenesisHash() []byte {
+	if x != nil {
+		return x.GenesisHash
+	}
+	return nil
+}
+
+func (x *ArchiveHeader) GetLastBlockIndex() uint64 {
+	if x != nil {
+		return x.LastBlockIndex
+	}
+	return 0
+}
+
+func (x *ArchiveHeader) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+// ArchivePeer is the peer stored in one of the peer buckets.
+type ArchivePeer struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Bucket string `protobuf:"bytes,1,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	Peer   *Peer  `protobuf:"bytes,2,opt,name=peer,proto3" json:"peer,omitempty"`
+}
+
+func (x *ArchivePeer) Reset() {
+	*x = ArchivePeer{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_archive_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ArchivePeer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchivePeer) ProtoMessage() {}
+
+func (x *ArchivePeer) ProtoReflect() protoreflect.Message {
+	mi := &file_archive_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchivePeer.ProtoReflect.Descriptor instead.
+func (*ArchivePeer) Descriptor() ([]byte, []int) {
+	return file_archive_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ArchivePeer) GetBucket() string {
+	if x != nil {
+		return x.Bucket
+	}
+	return ""
+}
+
+func (x *ArchivePeer) GetPeer() *Peer {
+	if x != nil {
+		return x.Peer
+	}
+	return nil
+}
+
+// ArchiveEntry is the entry of the authentication table of the level.
+type ArchiveEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Level uint32               `protobuf:"varint,1,opt,name=level,proto3" json:"level,omitempty"`
+	Entry *AuthenticationEntry `protobuf:"bytes,2,opt,name=entry,proto3" json:"entry,omitempty"`
+}
+
+func (x *ArchiveEntry) Reset() {
+	*x = ArchiveEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_archive_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ArchiveEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveEntry) ProtoMessage() {}
+
+func (x *ArchiveEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_archive_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveEntry.ProtoReflect.Descriptor instead.
+func (*ArchiveEntry) Descriptor() ([]byte, []int) {
+	return file_archive_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ArchiveEntry) GetLevel() uint32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+func (x *ArchiveEntry) GetEntry() *AuthenticationEntry {
+	if x != nil {
+		return x.Entry
+	}
+	return nil
+}
+
+// ArchiveKey is the public key of the device, the votes of the archived blocks are verified by these keys.
+type ArchiveKey struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DeviceId  []byte `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	PublicKey []byte `protobuf:"bytes,2,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+}
+
+func (x *ArchiveKey) Reset() {
+	*x = ArchiveKey{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_archive_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ArchiveKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveKey) ProtoMessage() {}
+
+func (x *ArchiveKey) ProtoReflect() protoreflect.Message {
+	mi := &file_archive_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveKey.ProtoReflect.Descriptor instead.
+func (*ArchiveKey) Descriptor() ([]byte, []int) {
+	return file_archive_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ArchiveKey) GetDeviceId() []byte {
+	if x != nil {
+		return x.DeviceId
+	}
+	return nil
+}
+
+func (x *ArchiveKey) GetPublicKey() []byte {
+	if x != nil {
+		return x.PublicKey
+	}
+	return nil
+}
+
+// ArchiveTrailer is the last record of the node archive.
+// Checksum is the SHA-256 of all archive bytes preceding the trailer record.
+type ArchiveTrailer struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Records  uint64 `protobuf:"varint,1,opt,name=records,proto3" json:"records,omitempty"`
+	Checksum []byte `protobuf:"bytes,2,opt,name=checksum,proto3" json:"checksum,omitempty"`
+}
+
+func (x *ArchiveTrailer) Reset() {
+	*x = ArchiveTrailer{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_archive_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ArchiveTrailer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveTrailer) ProtoMessage() {}
+
+func (x *ArchiveTrailer) ProtoReflect() protoreflect.Message {
+	mi := &file_archive_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveTrailer.ProtoReflect.Descriptor instead.
+func (*ArchiveTrailer) Descriptor() ([]byte, []int) {
+	return file_archive_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ArchiveTrailer) GetRecords() uint64 {
+	if x != nil {
+		return x.Records
+	}
+	return 0
+}
+
+func (x *ArchiveTrailer) GetChecksum() []byte {
+	if x != nil {
+		return x.Checksum
+	}
+	return nil
+}
+
+// ArchiveRecord is the length-prefixed record of the node archive.
+type ArchiveRecord struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Record:
+	//	*ArchiveRecord_Header
+	//	*ArchiveRecord_Peer
+	//	*ArchiveRecord_Block
+	//	*ArchiveRecord_Entry
+	//	*ArchiveRecord_Trailer
+	//	*ArchiveRecord_Key
+	Record isArchiveRecord_Record `protobuf_oneof:"record"`
+}
+
+func (x *ArchiveRecord) Reset() {
+	*x = ArchiveRecord{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_archive_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ArchiveRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveRecord) ProtoMessage() {}
+
+func (x *ArchiveRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_archive_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveRecord.ProtoReflect.Descriptor instead.
+func (*ArchiveRecord) Descriptor() ([]byte, []int) {
+	return file_archive_proto_rawDescGZIP(), []int{5}
+}
+
+func (m *ArchiveRecord) GetRecord() isArchiveRecord_Record {
+	if m != nil {
+		return m.Record
+	}
+	return nil
+}
+
+func (x *ArchiveRecord) GetHeader() *ArchiveHeader {
+	if x, ok := x.GetRecord().(*ArchiveRecord_Header); ok {
+		return x.Header
+	}
+	return nil
+}
+
+func (x *ArchiveRecord) GetPeer() *ArchivePeer {
+	if x, ok := x.GetRecord().(*ArchiveRecord_Peer); ok {
+		return x.Peer
+	}
+	return nil
+}
+
+func (x *ArchiveRecord) GetBlock() *Block {
+	if x, ok := x.GetRecord().(*ArchiveRecord_Block); ok {
+		return x.Block
+	}
+	return nil
+}
+
+func (x *ArchiveRecord) GetEntry() *ArchiveEntry {
+	if x, ok := x.GetRecord().(*ArchiveRecord_Entry); ok {
+		return x.Entry
+	}
+	return nil
+}
+
+func (x *ArchiveRecord) GetTrailer() *ArchiveTrailer {
+	if x, ok := x.GetRecord().(*ArchiveRecord_Trailer); ok {
+		return x.Trailer
+	}
+	return nil
+}
+
+func (x *ArchiveRecord) GetKey() *ArchiveKey {
+	if x, ok := x.GetRecord().(*ArchiveRecord_Key); ok {
+		return x.Key
+	}
+	return nil
+}
+
+type isArchiveRecord_Record interface {
+	isArchiveRecord_Record()
+}
+
+type ArchiveRecord_Header struct {
+	Header *ArchiveHeader `protobuf:"bytes,1,opt,name=header,proto3,oneof"`
+}
+
+type ArchiveRecord_Peer struct {
+	Peer *ArchivePeer `protobuf:"bytes,2,opt,name=peer,proto3,oneof"`
+}
+
+type ArchiveRecord_Block struct {
+	Block *Block `protobuf:"bytes,3,opt,name=block,proto3,oneof"`
+}
+
+type ArchiveRecord_Entry struct {
+	Entry *ArchiveEntry `protobuf:"bytes,4,opt,name=entry,proto3,oneof"`
+}
+
+type ArchiveRecord_Trailer struct {
+	Trailer *ArchiveTrailer `protobuf:"bytes,5,opt,name=trailer,proto3,oneof"`
+}
+
+type ArchiveRecord_Key struct {
+	Key *ArchiveKey `protobuf:"bytes,6,opt,name=key,proto3,oneof"`
+}
+
+func (*ArchiveRecord_Header) isArchiveRecord_Record() {}
+
+func (*ArchiveRecord_Peer) isArchiveRecord_Record() {}
+
+func (*ArchiveRecord_Block) isArchiveRecord_Record() {}
+
+func (*ArchiveRecord_Entry) isArchiveRecord_Record() {}
+
+func (*ArchiveRecord_Trailer) isArchiveRecord_Record() {}
+
+func (*ArchiveRecord_Key) isArchiveRecord_Record() {}
+
+var File_archive_proto protoreflect.FileDescriptor
+
+var file_archive_proto_rawDesc = []byte{
+	0x0a, 0x0d, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x0a, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x1a, 0x14, 0x61, 0x75, 0x74,
+	0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x1a, 0x0c, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a,
+	0x0b, 0x70, 0x65, 0x65, 0x72, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xc8, 0x01, 0x0a,
+	0x0d, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x18,
+	0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x6e, 0x6f, 0x64, 0x65,
+	0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6e, 0x6f, 0x64,
+	0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x21, 0x0a, 0x0c, 0x67,
+	0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x0b, 0x67, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x48, 0x61, 0x73, 0x68, 0x12, 0x28,
+	0x0a, 0x10, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x69, 0x6e, 0x64,
+	0x65, 0x78, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x6c, 0x61, 0x73, 0x74, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x63, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0x4b, 0x0a, 0x0b, 0x41, 0x72, 0x63, 0x68, 0x69,
+	0x76, 0x65, 0x50, 0x65, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x12, 0x24,
+	0x0a, 0x04, 0x70, 0x65, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x62,
+	0x6c, 0x6f, 0x63, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x2e, 0x50, 0x65, 0x65, 0x72, 0x52, 0x04,
+	0x70, 0x65, 0x65, 0x72, 0x22, 0x5b, 0x0a, 0x0c, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x35, 0x0a, 0x05, 0x65, 0x6e,
+	0x74, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x62, 0x6c, 0x6f, 0x63,
+	0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x65, 0x6e, 0x74, 0x72,
+	0x79, 0x22, 0x48, 0x0a, 0x0a, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x4b, 0x65, 0x79, 0x12,
+	0x1b, 0x0a, 0x09, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x08, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a,
+	0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x09, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x22, 0x46, 0x0a, 0x0e, 0x41,
+	0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x54, 0x72, 0x61, 0x69, 0x6c, 0x65, 0x72, 0x12, 0x18, 0x0a,
+	0x07, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07,
+	0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x68, 0x65, 0x63, 0x6b,
+	0x73, 0x75, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x63, 0x68, 0x65, 0x63, 0x6b,
+	0x73, 0x75, 0x6d, 0x22, 0xbe, 0x02, 0x0a, 0x0d, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x52,
+	0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x33, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x63, 0x68, 0x61,
+	0x69, 0x6e, 0x2e, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72,
+	0x48, 0x00, 0x52, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x2d, 0x0a, 0x04, 0x70, 0x65,
+	0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b,
+	0x63, 0x68, 0x61, 0x69, 0x6e, 0x2e, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x50, 0x65, 0x65,
+	0x72, 0x48, 0x00, 0x52, 0x04, 0x70, 0x65, 0x65, 0x72, 0x12, 0x29, 0x0a, 0x05, 0x62, 0x6c, 0x6f,
+	0x63, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b,
+	0x63, 0x68, 0x61, 0x69, 0x6e, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x00, 0x52, 0x05, 0x62,
+	0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x30, 0x0a, 0x05, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e,
+	0x2e, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x48, 0x00, 0x52,
+	0x05, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x36, 0x0a, 0x07, 0x74, 0x72, 0x61, 0x69, 0x6c, 0x65,
+	0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x63,
+	0x68, 0x61, 0x69, 0x6e, 0x2e, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x54, 0x72, 0x61, 0x69,
+	0x6c, 0x65, 0x72, 0x48, 0x00, 0x52, 0x07, 0x74, 0x72, 0x61, 0x69, 0x6c, 0x65, 0x72, 0x12, 0x2a,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x62, 0x6c,
+	0x6f, 0x63, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x2e, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65,
+	0x4b, 0x65, 0x79, 0x48, 0x00, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x42, 0x08, 0x0a, 0x06, 0x72, 0x65,
+	0x63, 0x6f, 0x72, 0x64, 0x42, 0x10, 0x5a, 0x0e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c,
+	0x2f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_archive_proto_rawDescOnce sync.Once
+	file_archive_proto_rawDescData = file_archive_proto_rawDesc
+)
+
+func file_archive_proto_rawDescGZIP() []byte {
+	file_archive_proto_rawDescOnce.Do(func() {
+		file_archive_proto_rawDescData = protoimpl.X.CompressGZIP(file_archive_proto_rawDescData)
+	})
+	return file_archive_proto_rawDescData
+}
+
+var file_archive_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_archive_proto_goTypes = []interface{}{
+	(*ArchiveHeader)(nil),       // 0: blockchain.ArchiveHeader
+	(*ArchivePeer)(nil),         // 1: blockchain.ArchivePeer
+	(*ArchiveEntry)(nil),        // 2: blockchain.ArchiveEntry
+	(*ArchiveKey)(nil),          // 3: blockchain.ArchiveKey
+	(*ArchiveTrailer)(nil),      // 4: blockchain.ArchiveTrailer
+	(*ArchiveRecord)(nil),       // 5: blockchain.ArchiveRecord
+	(*Peer)(nil),                // 6: blockchain.Peer
+	(*AuthenticationEntry)(nil), // 7: blockchain.AuthenticationEntry
+	(*Block)(nil),               // 8: blockchain.Block
+}
+var file_archive_proto_depIdxs = []int32{
+	6, // 0: blockchain.ArchivePeer.peer:type_name -> blockchain.Peer
+	7, // 1: blockchain.ArchiveEntry.entry:type_name -> blockchain.AuthenticationEntry
+	0, // 2: blockchain.ArchiveRecord.header:type_name -> blockchain.ArchiveHeader
+	1, // 3: blockchain.ArchiveRecord.peer:type_name -> blockchain.ArchivePeer
+	8, // 4: blockchain.ArchiveRecord.block:type_name -> blockchain.Block
+	2, // 5: blockchain.ArchiveRecord.entry:type_name -> blockchain.ArchiveEntry
+	4, // 6: blockchain.ArchiveRecord.trailer:type_name -> blockchain.ArchiveTrailer
+	3, // 7: blockchain.ArchiveRecord.key:type_name -> blockchain.ArchiveKey
+	8, // [8:8] is the sub-list for method output_type
+	8, // [8:8] is the sub-list for method input_type
+	8, // [8:8] is the sub-list for extension type_name
+	8, // [8:8] is the sub-list for extension extendee
+	0, // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_archive_proto_init() }
+func file_archive_proto_init() {
+	if File_archive_proto != nil {
+		return
+	}
+	file_authentication_proto_init()
+	file_blocks_proto_init()
+	file_peers_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_archive_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ArchiveHeader); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_archive_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ArchivePeer); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_archive_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ArchiveEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_archive_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ArchiveKey); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_archive_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ArchiveTrailer); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_archive_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ArchiveRecord); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_archive_proto_msgTypes[5].OneofWrappers = []interface{}{
+		(*ArchiveRecord_Header)(nil),
+		(*ArchiveRecord_Peer)(nil),
+		(*ArchiveRecord_Block)(nil),
+		(*ArchiveRecord_Entry)(nil),
+		(*ArchiveRecord_Trailer)(nil),
+		(*ArchiveRecord_Key)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_archive_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_archive_proto_goTypes,
+		DependencyIndexes: file_archive_proto_depIdxs,
+		MessageInfos:      file_archive_proto_msgTypes,
+	}.Build()
+	File_archive_proto = out.File
+	file_archive_proto_rawDesc = nil
+	file_archive_proto_goTypes = nil
+	file_archive_proto_depIdxs = nil
+}
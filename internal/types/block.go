@@ -6,18 +6,77 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
-func NewBlock(prevHash []byte, index uint64, dar *DeviceAuthenticationRequest) *Block {
+// Transactions is the content of a block.
+type Transactions struct {
+	DARs        []*DeviceAuthenticationRequest
+	Revocations []*DeviceRevocationRequest
+	Renewals    []*DeviceRenewalRequest
+	Rotations   []*DeviceKeyRotationRequest
+}
+
+func NewBlock(prevHash []byte, index uint64, txs Transactions) *Block {
 	block := &Block{
-		Hash:      nil,
-		PrevHash:  prevHash,
-		Index:     index,
-		Dar:       dar,
-		Timestamp: time.Now().Unix(),
+		Hash:        nil,
+		PrevHash:    prevHash,
+		Index:       index,
+		Dars:        txs.DARs,
+		Revocations: txs.Revocations,
+		Renewals:    txs.Renewals,
+		Rotations:   txs.Rotations,
+		Timestamp:   time.Now().Unix(),
 	}
 
 	return block
 }
 
+// Header returns the header of the block.
+func (b *Block) Header() *BlockHeader {
+	return &BlockHeader{
+		Hash:         b.Hash,
+		PrevHash:     b.PrevHash,
+		Index:        b.Index,
+		MerkleRoot:   b.MerkleRoot,
+		Timestamp:    b.Timestamp,
+		ValidatorIds: b.ValidatorIds,
+	}
+}
+
+// ClusterHeadID returns the cluster head id shared by transactions of the block.
+func (b *Block) ClusterHeadID() []byte {
+	switch {
+	case len(b.Dars) != 0:
+		return b.Dars[0].ClusterHeadId
+	case len(b.Revocations) != 0:
+		return b.Revocations[0].ClusterHeadId
+	case len(b.Renewals) != 0:
+		return b.Renewals[0].ClusterHeadId
+	case len(b.Rotations) != 0:
+		return b.Rotations[0].ClusterHeadId
+	default:
+		return nil
+	}
+}
+
+// IsEmpty checks if the block has no transactions.
+func (b *Block) IsEmpty() bool {
+	return len(b.Dars) == 0 && len(b.Revocations) == 0 && len(b.Renewals) == 0 && len(b.Rotations) == 0
+}
+
+// IsEmpty checks if there are no transactions.
+func (t Transactions) IsEmpty() bool {
+	return len(t.DARs) == 0 && len(t.Revocations) == 0 && len(t.Renewals) == 0 && len(t.Rotations) == 0
+}
+
+// NotAfter returns the expiration time of authentication granted by the block for the validity period.
+// Zero validity means no expiry.
+func (b *Block) NotAfter(validity uint64) int64 {
+	if validity == 0 {
+		return 0
+	}
+
+	return b.Timestamp + int64(validity)
+}
+
 // Serialize serializes a block.
 func (b *Block) Serialize() []byte {
 	data, err := proto.Marshal(b)
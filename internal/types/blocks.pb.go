@@ -29,11 +29,18 @@ type Block struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Hash      []byte                       `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
-	PrevHash  []byte                       `protobuf:"bytes,2,opt,name=prev_hash,json=prevHash,proto3" json:"prev_hash,omitempty"`
-	Index     uint64                       `protobuf:"varint,3,opt,name=index,proto3" json:"index,omitempty"`
-	Dar       *DeviceAuthenticationRequest `protobuf:"bytes,4,opt,name=dar,proto3" json:"dar,omitempty"`
-	Timestamp int64                        `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Hash        []byte                         `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	PrevHash    []byte                         `protobuf:"bytes,2,opt,name=prev_hash,json=prevHash,proto3" json:"prev_hash,omitempty"`
+	Index       uint64                         `protobuf:"varint,3,opt,name=index,proto3" json:"index,omitempty"`
+	Dars        []*DeviceAuthenticationRequest `protobuf:"bytes,4,rep,name=dars,proto3" json:"dars,omitempty"`
+	Timestamp   int64                          `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	MerkleRoot  []byte                         `protobuf:"bytes,6,opt,name=merkle_root,json=merkleRoot,proto3" json:"merkle_root,omitempty"`
+	Revocations []*DeviceRevocationRequest     `protobuf:"bytes,7,rep,name=revocations,proto3" json:"revocations,omitempty"`
+	Renewals    []*DeviceRenewalRequest        `protobuf:"bytes,8,rep,name=renewals,proto3" json:"renewals,omitempty"`
+	Votes       []*BlockVote                   `protobuf:"bytes,9,rep,name=votes,proto3" json:"votes,omitempty"`
+	Rotations   []*DeviceKeyRotationRequest    `protobuf:"bytes,10,rep,name=rotations,proto3" json:"rotations,omitempty"`
+	// validator_ids are the cluster validators when the block was mined, the quorum of the block is counted among them.
+	ValidatorIds [][]byte `protobuf:"bytes,11,rep,name=validator_ids,json=validatorIds,proto3" json:"validator_ids,omitempty"`
 }
 
 func (x *Block) Reset() {
@@ -89,22 +96,515 @@ func (x *Block) GetIndex() uint64 {
 	return 0
 }
 
-func (x *Block) GetDar() *DeviceAuthenticationRequest {
+func (x *Block) GetDars() []*DeviceAuthenticationRequest {
+	if x != nil {
+		return x.Dars
+	}
+	return nil
+}
+
+func (x *Block) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *Block) GetMerkleRoot() []byte {
+	if x != nil {
+		return x.MerkleRoot
+	}
+	return nil
+}
+
+func (x *Block) GetRevocations() []*DeviceRevocationRequest {
+	if x != nil {
+		return x.Revocations
+	}
+	return nil
+}
+
+func (x *Block) GetRenewals() []*DeviceRenewalRequest {
+	if x != nil {
+		return x.Renewals
+	}
+	return nil
+}
+
+func (x *Block) GetVotes() []*BlockVote {
+	if x != nil {
+		return x.Votes
+	}
+	return nil
+}
+
+func (x *Block) GetRotations() []*DeviceKeyRotationRequest {
+	if x != nil {
+		return x.Rotations
+	}
+	return nil
+}
+
+func (x *Block) GetValidatorIds() [][]byte {
+	if x != nil {
+		return x.ValidatorIds
+	}
+	return nil
+}
+
+// BlockVote is a signed decision of a validator on the block.
+// Votes are stored alongside the block and are not covered by its hash.
+type BlockVote struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ValidatorId []byte `protobuf:"bytes,1,opt,name=validator_id,json=validatorId,proto3" json:"validator_id,omitempty"`
+	BlockHash   []byte `protobuf:"bytes,2,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+	IsValid     bool   `protobuf:"varint,3,opt,name=is_valid,json=isValid,proto3" json:"is_valid,omitempty"`
+	Signature   []byte `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (x *BlockVote) Reset() {
+	*x = BlockVote{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_blocks_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlockVote) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockVote) ProtoMessage() {}
+
+func (x *BlockVote) ProtoReflect() protoreflect.Message {
+	mi := &file_blocks_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockVote.ProtoReflect.Descriptor instead.
+func (*BlockVote) Descriptor() ([]byte, []int) {
+	return file_blocks_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *BlockVote) GetValidatorId() []byte {
+	if x != nil {
+		return x.ValidatorId
+	}
+	return nil
+}
+
+func (x *BlockVote) GetBlockHash() []byte {
+	if x != nil {
+		return x.BlockHash
+	}
+	return nil
+}
+
+func (x *BlockVote) GetIsValid() bool {
+	if x != nil {
+		return x.IsValid
+	}
+	return false
+}
+
+func (x *BlockVote) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+// BlockHeader is the part of the block covered by the block hash.
+// Device authentication requests are committed through the merkle root, validators are committed as they are.
+type BlockHeader struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Hash         []byte   `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	PrevHash     []byte   `protobuf:"bytes,2,opt,name=prev_hash,json=prevHash,proto3" json:"prev_hash,omitempty"`
+	Index        uint64   `protobuf:"varint,3,opt,name=index,proto3" json:"index,omitempty"`
+	MerkleRoot   []byte   `protobuf:"bytes,4,opt,name=merkle_root,json=merkleRoot,proto3" json:"merkle_root,omitempty"`
+	Timestamp    int64    `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	ValidatorIds [][]byte `protobuf:"bytes,6,rep,name=validator_ids,json=validatorIds,proto3" json:"validator_ids,omitempty"`
+}
+
+func (x *BlockHeader) Reset() {
+	*x = BlockHeader{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_blocks_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlockHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockHeader) ProtoMessage() {}
+
+func (x *BlockHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_blocks_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockHeader.ProtoReflect.Descriptor instead.
+func (*BlockHeader) Descriptor() ([]byte, []int) {
+	return file_blocks_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BlockHeader) GetHash() []byte {
+	if x != nil {
+		return x.Hash
+	}
+	return nil
+}
+
+func (x *BlockHeader) GetPrevHash() []byte {
+	if x != nil {
+		return x.PrevHash
+	}
+	return nil
+}
+
+func (x *BlockHeader) GetIndex() uint64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BlockHeader) GetMerkleRoot() []byte {
+	if x != nil {
+		return x.MerkleRoot
+	}
+	return nil
+}
+
+func (x *BlockHeader) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *BlockHeader) GetValidatorIds() [][]byte {
+	if x != nil {
+		return x.ValidatorIds
+	}
+	return nil
+}
+
+// MerkleStep is a sibling hash on the path from a leaf to the merkle root.
+type MerkleStep struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Hash []byte `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Left bool   `protobuf:"varint,2,opt,name=left,proto3" json:"left,omitempty"`
+}
+
+func (x *MerkleStep) Reset() {
+	*x = MerkleStep{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_blocks_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MerkleStep) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MerkleStep) ProtoMessage() {}
+
+func (x *MerkleStep) ProtoReflect() protoreflect.Message {
+	mi := &file_blocks_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MerkleStep.ProtoReflect.Descriptor instead.
+func (*MerkleStep) Descriptor() ([]byte, []int) {
+	return file_blocks_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *MerkleStep) GetHash() []byte {
+	if x != nil {
+		return x.Hash
+	}
+	return nil
+}
+
+func (x *MerkleStep) GetLeft() bool {
+	if x != nil {
+		return x.Left
+	}
+	return false
+}
+
+// InclusionProofRequest is the request for proving that device is registered in the chain.
+type InclusionProofRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DeviceId []byte `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+}
+
+func (x *InclusionProofRequest) Reset() {
+	*x = InclusionProofRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_blocks_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InclusionProofRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InclusionProofRequest) ProtoMessage() {}
+
+func (x *InclusionProofRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_blocks_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InclusionProofRequest.ProtoReflect.Descriptor instead.
+func (*InclusionProofRequest) Descriptor() ([]byte, []int) {
+	return file_blocks_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *InclusionProofRequest) GetDeviceId() []byte {
+	if x != nil {
+		return x.DeviceId
+	}
+	return nil
+}
+
+// InclusionProofResponse is the merkle path from the device authentication request to the block header.
+// Renewal or rotation is set instead of dar if the device authentication was renewed or its key was rotated.
+type InclusionProofResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Header   *BlockHeader                 `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	Dar      *DeviceAuthenticationRequest `protobuf:"bytes,2,opt,name=dar,proto3" json:"dar,omitempty"`
+	Path     []*MerkleStep                `protobuf:"bytes,3,rep,name=path,proto3" json:"path,omitempty"`
+	Renewal  *DeviceRenewalRequest        `protobuf:"bytes,4,opt,name=renewal,proto3" json:"renewal,omitempty"`
+	Rotation *DeviceKeyRotationRequest    `protobuf:"bytes,5,opt,name=rotation,proto3" json:"rotation,omitempty"`
+}
+
+func (x *InclusionProofResponse) Reset() {
+	*x = InclusionProofResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_blocks_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InclusionProofResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InclusionProofResponse) ProtoMessage() {}
+
+func (x *InclusionProofResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_blocks_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InclusionProofResponse.ProtoReflect.Descriptor instead.
+func (*InclusionProofResponse) Descriptor() ([]byte, []int) {
+	return file_blocks_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *InclusionProofResponse) GetHeader() *BlockHeader {
+	if x != nil {
+		return x.Header
+	}
+	return nil
+}
+
+func (x *InclusionProofResponse) GetDar() *DeviceAuthenticationRequest {
 	if x != nil {
 		return x.Dar
 	}
-	return nil
+	return nil
+}
+
+func (x *InclusionProofResponse) GetPath() []*MerkleStep {
+	if x != nil {
+		return x.Path
+	}
+	return nil
+}
+
+func (x *InclusionProofResponse) GetRenewal() *DeviceRenewalRequest {
+	if x != nil {
+		return x.Renewal
+	}
+	return nil
+}
+
+func (x *InclusionProofResponse) GetRotation() *DeviceKeyRotationRequest {
+	if x != nil {
+		return x.Rotation
+	}
+	return nil
+}
+
+// BlockValidationRequest is the request for validating block.
+type BlockValidationRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Block *Block `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
+}
+
+func (x *BlockValidationRequest) Reset() {
+	*x = BlockValidationRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_blocks_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlockValidationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockValidationRequest) ProtoMessage() {}
+
+func (x *BlockValidationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_blocks_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockValidationRequest.ProtoReflect.Descriptor instead.
+func (*BlockValidationRequest) Descriptor() ([]byte, []int) {
+	return file_blocks_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BlockValidationRequest) GetBlock() *Block {
+	if x != nil {
+		return x.Block
+	}
+	return nil
+}
+
+// BlockValidationResponse is the response for validating block.
+type BlockValidationResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	IsValid bool       `protobuf:"varint,1,opt,name=is_valid,json=isValid,proto3" json:"is_valid,omitempty"`
+	Vote    *BlockVote `protobuf:"bytes,2,opt,name=vote,proto3" json:"vote,omitempty"`
+}
+
+func (x *BlockValidationResponse) Reset() {
+	*x = BlockValidationResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_blocks_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-func (x *Block) GetTimestamp() int64 {
+func (x *BlockValidationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockValidationResponse) ProtoMessage() {}
+
+func (x *BlockValidationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_blocks_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockValidationResponse.ProtoReflect.Descriptor instead.
+func (*BlockValidationResponse) Descriptor() ([]byte, []int) {
+	return file_blocks_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *BlockValidationResponse) GetIsValid() bool {
 	if x != nil {
-		return x.Timestamp
+		return x.IsValid
 	}
-	return 0
+	return false
 }
 
-// BlockValidationRequest is the request for validating block.
-type BlockValidationRequest struct {
+func (x *BlockValidationResponse) GetVote() *BlockVote {
+	if x != nil {
+		return x.Vote
+	}
+	return nil
+}
+
+// BlockCommitRequest is the request for committing block approved by the quorum.
+type BlockCommitRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
@@ -112,23 +612,23 @@ type BlockValidationRequest struct {
 	Block *Block `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
 }
 
-func (x *BlockValidationRequest) Reset() {
-	*x = BlockValidationRequest{}
+func (x *BlockCommitRequest) Reset() {
+	*x = BlockCommitRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_blocks_proto_msgTypes[1]
+		mi := &file_blocks_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *BlockValidationRequest) String() string {
+func (x *BlockCommitRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BlockValidationRequest) ProtoMessage() {}
+func (*BlockCommitRequest) ProtoMessage() {}
 
-func (x *BlockValidationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_blocks_proto_msgTypes[1]
+func (x *BlockCommitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_blocks_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -139,44 +639,85 @@ func (x *BlockValidationRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BlockValidationRequest.ProtoReflect.Descriptor instead.
-func (*BlockValidationRequest) Descriptor() ([]byte, []int) {
-	return file_blocks_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use BlockCommitRequest.ProtoReflect.Descriptor instead.
+func (*BlockCommitRequest) Descriptor() ([]byte, []int) {
+	return file_blocks_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *BlockValidationRequest) GetBlock() *Block {
+func (x *BlockCommitRequest) GetBlock() *Block {
 	if x != nil {
 		return x.Block
 	}
 	return nil
 }
 
-// BlockValidationResponse is the response for validating block.
-type BlockValidationResponse struct {
+// BlockCommitResponse is the response for committing block.
+type BlockCommitResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+}
+
+func (x *BlockCommitResponse) Reset() {
+	*x = BlockCommitResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_blocks_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
 
-	IsValid bool `protobuf:"varint,1,opt,name=is_valid,json=isValid,proto3" json:"is_valid,omitempty"`
+func (x *BlockCommitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *BlockValidationResponse) Reset() {
-	*x = BlockValidationResponse{}
+func (*BlockCommitResponse) ProtoMessage() {}
+
+func (x *BlockCommitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_blocks_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockCommitResponse.ProtoReflect.Descriptor instead.
+func (*BlockCommitResponse) Descriptor() ([]byte, []int) {
+	return file_blocks_proto_rawDescGZIP(), []int{9}
+}
+
+// FindBlockRequest is the request for the block of the device authentication entry from the cluster which owns it.
+type FindBlockRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Hash     []byte `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Index    uint64 `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	DeviceId []byte `protobuf:"bytes,3,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+}
+
+func (x *FindBlockRequest) Reset() {
+	*x = FindBlockRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_blocks_proto_msgTypes[2]
+		mi := &file_blocks_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *BlockValidationResponse) String() string {
+func (x *FindBlockRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BlockValidationResponse) ProtoMessage() {}
+func (*FindBlockRequest) ProtoMessage() {}
 
-func (x *BlockValidationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_blocks_proto_msgTypes[2]
+func (x *FindBlockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_blocks_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -187,16 +728,86 @@ func (x *BlockValidationResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BlockValidationResponse.ProtoReflect.Descriptor instead.
-func (*BlockValidationResponse) Descriptor() ([]byte, []int) {
-	return file_blocks_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use FindBlockRequest.ProtoReflect.Descriptor instead.
+func (*FindBlockRequest) Descriptor() ([]byte, []int) {
+	return file_blocks_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *BlockValidationResponse) GetIsValid() bool {
+func (x *FindBlockRequest) GetHash() []byte {
 	if x != nil {
-		return x.IsValid
+		return x.Hash
 	}
-	return false
+	return nil
+}
+
+func (x *FindBlockRequest) GetIndex() uint64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *FindBlockRequest) GetDeviceId() []byte {
+	if x != nil {
+		return x.DeviceId
+	}
+	return nil
+}
+
+// FindBlockResponse is the block and the hops it's found through, starting from the node of the block cluster.
+type FindBlockResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Block *Block             `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
+	Hops  []*VerificationHop `protobuf:"bytes,2,rep,name=hops,proto3" json:"hops,omitempty"`
+}
+
+func (x *FindBlockResponse) Reset() {
+	*x = FindBlockResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_blocks_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FindBlockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindBlockResponse) ProtoMessage() {}
+
+func (x *FindBlockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_blocks_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindBlockResponse.ProtoReflect.Descriptor instead.
+func (*FindBlockResponse) Descriptor() ([]byte, []int) {
+	return file_blocks_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *FindBlockResponse) GetBlock() *Block {
+	if x != nil {
+		return x.Block
+	}
+	return nil
+}
+
+func (x *FindBlockResponse) GetHops() []*VerificationHop {
+	if x != nil {
+		return x.Hops
+	}
+	return nil
 }
 
 // BlockRequest is the request for getting block by index.
@@ -211,7 +822,7 @@ type BlockRequest struct {
 func (x *BlockRequest) Reset() {
 	*x = BlockRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_blocks_proto_msgTypes[3]
+		mi := &file_blocks_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -224,7 +835,7 @@ func (x *BlockRequest) String() string {
 func (*BlockRequest) ProtoMessage() {}
 
 func (x *BlockRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_blocks_proto_msgTypes[3]
+	mi := &file_blocks_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -237,7 +848,7 @@ func (x *BlockRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BlockRequest.ProtoReflect.Descriptor instead.
 func (*BlockRequest) Descriptor() ([]byte, []int) {
-	return file_blocks_proto_rawDescGZIP(), []int{3}
+	return file_blocks_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *BlockRequest) GetIndex() uint64 {
@@ -259,7 +870,7 @@ type BlockResponse struct {
 func (x *BlockResponse) Reset() {
 	*x = BlockResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_blocks_proto_msgTypes[4]
+		mi := &file_blocks_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -272,7 +883,7 @@ func (x *BlockResponse) String() string {
 func (*BlockResponse) ProtoMessage() {}
 
 func (x *BlockResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_blocks_proto_msgTypes[4]
+	mi := &file_blocks_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -285,7 +896,7 @@ func (x *BlockResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BlockResponse.ProtoReflect.Descriptor instead.
 func (*BlockResponse) Descriptor() ([]byte, []int) {
-	return file_blocks_proto_rawDescGZIP(), []int{4}
+	return file_blocks_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *BlockResponse) GetBlock() *Block {
@@ -308,7 +919,7 @@ type BlocksRequest struct {
 func (x *BlocksRequest) Reset() {
 	*x = BlocksRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_blocks_proto_msgTypes[5]
+		mi := &file_blocks_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -321,7 +932,7 @@ func (x *BlocksRequest) String() string {
 func (*BlocksRequest) ProtoMessage() {}
 
 func (x *BlocksRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_blocks_proto_msgTypes[5]
+	mi := &file_blocks_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -334,7 +945,7 @@ func (x *BlocksRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BlocksRequest.ProtoReflect.Descriptor instead.
 func (*BlocksRequest) Descriptor() ([]byte, []int) {
-	return file_blocks_proto_rawDescGZIP(), []int{5}
+	return file_blocks_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *BlocksRequest) GetFrom() uint64 {
@@ -363,7 +974,7 @@ type BlocksResponse struct {
 func (x *BlocksResponse) Reset() {
 	*x = BlocksResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_blocks_proto_msgTypes[6]
+		mi := &file_blocks_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -376,7 +987,7 @@ func (x *BlocksResponse) String() string {
 func (*BlocksResponse) ProtoMessage() {}
 
 func (x *BlocksResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_blocks_proto_msgTypes[6]
+	mi := &file_blocks_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -389,7 +1000,7 @@ func (x *BlocksResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BlocksResponse.ProtoReflect.Descriptor instead.
 func (*BlocksResponse) Descriptor() ([]byte, []int) {
-	return file_blocks_proto_rawDescGZIP(), []int{6}
+	return file_blocks_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *BlocksResponse) GetBlocks() []*Block {
@@ -405,39 +1016,126 @@ var file_blocks_proto_rawDesc = []byte{
 	0x0a, 0x0c, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0a,
 	0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x1a, 0x14, 0x61, 0x75, 0x74, 0x68,
 	0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x22, 0xa7, 0x01, 0x0a, 0x05, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x61,
+	0x22, 0xe5, 0x03, 0x0a, 0x05, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x61,
 	0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x12, 0x1b,
 	0x0a, 0x09, 0x70, 0x72, 0x65, 0x76, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28,
 	0x0c, 0x52, 0x08, 0x70, 0x72, 0x65, 0x76, 0x48, 0x61, 0x73, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x69,
 	0x6e, 0x64, 0x65, 0x78, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65,
-	0x78, 0x12, 0x39, 0x0a, 0x03, 0x64, 0x61, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27,
-	0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x2e, 0x44, 0x65, 0x76, 0x69,
-	0x63, 0x65, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x03, 0x64, 0x61, 0x72, 0x12, 0x1c, 0x0a, 0x09,
-	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52,
-	0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x22, 0x41, 0x0a, 0x16, 0x42, 0x6c,
-	0x6f, 0x63, 0x6b, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x27, 0x0a, 0x05, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x01, 0x20,
+	0x78, 0x12, 0x3b, 0x0a, 0x04, 0x64, 0x61, 0x72, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x27, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x2e, 0x44, 0x65, 0x76,
+	0x69, 0x63, 0x65, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x04, 0x64, 0x61, 0x72, 0x73, 0x12, 0x1c,
+	0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x1f, 0x0a, 0x0b,
+	0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x0a, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x45, 0x0a,
+	0x0b, 0x72, 0x65, 0x76, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x07, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x23, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x2e,
+	0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x76, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x0b, 0x72, 0x65, 0x76, 0x6f, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x12, 0x3c, 0x0a, 0x08, 0x72, 0x65, 0x6e, 0x65, 0x77, 0x61, 0x6c, 0x73,
+	0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x63, 0x68,
+	0x61, 0x69, 0x6e, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x6e, 0x65, 0x77, 0x61,
+	0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x08, 0x72, 0x65, 0x6e, 0x65, 0x77, 0x61,
+	0x6c, 0x73, 0x12, 0x2b, 0x0a, 0x05, 0x76, 0x6f, 0x74, 0x65, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x15, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x2e, 0x42,
+	0x6c, 0x6f, 0x63, 0x6b, 0x56, 0x6f, 0x74, 0x65, 0x52, 0x05, 0x76, 0x6f, 0x74, 0x65, 0x73, 0x12,
+	0x42, 0x0a, 0x09, 0x72, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x0a, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x24, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x2e,
+	0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x4b, 0x65, 0x79, 0x52, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x09, 0x72, 0x6f, 0x74, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72,
+	0x5f, 0x69, 0x64, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0c, 0x76, 0x61, 0x6c, 0x69,
+	0x64, 0x61, 0x74, 0x6f, 0x72, 0x49, 0x64, 0x73, 0x22, 0x86, 0x01, 0x0a, 0x09, 0x42, 0x6c, 0x6f,
+	0x63, 0x6b, 0x56, 0x6f, 0x74, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61,
+	0x74, 0x6f, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x76, 0x61,
+	0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x6c, 0x6f,
+	0x63, 0x6b, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x62,
+	0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x61, 0x73, 0x68, 0x12, 0x19, 0x0a, 0x08, 0x69, 0x73, 0x5f, 0x76,
+	0x61, 0x6c, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x69, 0x73, 0x56, 0x61,
+	0x6c, 0x69, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72,
+	0x65, 0x22, 0xb8, 0x01, 0x0a, 0x0b, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x04, 0x68, 0x61, 0x73, 0x68, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x72, 0x65, 0x76, 0x5f, 0x68, 0x61,
+	0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x70, 0x72, 0x65, 0x76, 0x48, 0x61,
+	0x73, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x65, 0x72, 0x6b,
+	0x6c, 0x65, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x6d,
+	0x65, 0x72, 0x6b, 0x6c, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x23, 0x0a, 0x0d, 0x76, 0x61, 0x6c, 0x69, 0x64,
+	0x61, 0x74, 0x6f, 0x72, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0c,
+	0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x49, 0x64, 0x73, 0x22, 0x34, 0x0a, 0x0a,
+	0x4d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x53, 0x74, 0x65, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x61,
+	0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x12, 0x12,
+	0x0a, 0x04, 0x6c, 0x65, 0x66, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x6c, 0x65,
+	0x66, 0x74, 0x22, 0x34, 0x0a, 0x15, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x6f, 0x6e, 0x50,
+	0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x64,
+	0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08,
+	0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x49, 0x64, 0x22, 0xae, 0x02, 0x0a, 0x16, 0x49, 0x6e, 0x63,
+	0x6c, 0x75, 0x73, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x2f, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e,
+	0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x52, 0x06, 0x68, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x12, 0x39, 0x0a, 0x03, 0x64, 0x61, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x27, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x2e, 0x44,
+	0x65, 0x76, 0x69, 0x63, 0x65, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x03, 0x64, 0x61, 0x72, 0x12,
+	0x2a, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e,
+	0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x2e, 0x4d, 0x65, 0x72, 0x6b, 0x6c,
+	0x65, 0x53, 0x74, 0x65, 0x70, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x3a, 0x0a, 0x07, 0x72,
+	0x65, 0x6e, 0x65, 0x77, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x62,
+	0x6c, 0x6f, 0x63, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65,
+	0x52, 0x65, 0x6e, 0x65, 0x77, 0x61, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x07,
+	0x72, 0x65, 0x6e, 0x65, 0x77, 0x61, 0x6c, 0x12, 0x40, 0x0a, 0x08, 0x72, 0x6f, 0x74, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x62, 0x6c, 0x6f, 0x63,
+	0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x4b, 0x65, 0x79,
+	0x52, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52,
+	0x08, 0x72, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x41, 0x0a, 0x16, 0x42, 0x6c, 0x6f,
+	0x63, 0x6b, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x27, 0x0a, 0x05, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x11, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x2e,
+	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x05, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x22, 0x5f, 0x0a, 0x17,
+	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x69, 0x73, 0x5f, 0x76, 0x61,
+	0x6c, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x69, 0x73, 0x56, 0x61, 0x6c,
+	0x69, 0x64, 0x12, 0x29, 0x0a, 0x04, 0x76, 0x6f, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x15, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x2e, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x56, 0x6f, 0x74, 0x65, 0x52, 0x04, 0x76, 0x6f, 0x74, 0x65, 0x22, 0x3d, 0x0a,
+	0x12, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x27, 0x0a, 0x05, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x11, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x2e,
+	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x05, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x22, 0x15, 0x0a, 0x13,
+	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x59, 0x0a, 0x10, 0x46, 0x69, 0x6e, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x69,
+	0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65,
+	0x78, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x49, 0x64, 0x22, 0x6d,
+	0x0a, 0x11, 0x46, 0x69, 0x6e, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x27, 0x0a, 0x05, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x11, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x2e,
+	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x05, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x2f, 0x0a, 0x04,
+	0x68, 0x6f, 0x70, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x62, 0x6c, 0x6f,
+	0x63, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x48, 0x6f, 0x70, 0x52, 0x04, 0x68, 0x6f, 0x70, 0x73, 0x22, 0x24, 0x0a,
+	0x0c, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a,
+	0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x69, 0x6e,
+	0x64, 0x65, 0x78, 0x22, 0x38, 0x0a, 0x0d, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27, 0x0a, 0x05, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x01, 0x20,
 	0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e,
-	0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x05, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x22, 0x34, 0x0a,
-	0x17, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x69, 0x73, 0x5f, 0x76,
-	0x61, 0x6c, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x69, 0x73, 0x56, 0x61,
-	0x6c, 0x69, 0x64, 0x22, 0x24, 0x0a, 0x0c, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x04, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x22, 0x38, 0x0a, 0x0d, 0x42, 0x6c, 0x6f,
-	0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27, 0x0a, 0x05, 0x62, 0x6c,
-	0x6f, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x62, 0x6c, 0x6f, 0x63,
-	0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x05, 0x62, 0x6c,
-	0x6f, 0x63, 0x6b, 0x22, 0x33, 0x0a, 0x0d, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x04, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x74, 0x6f, 0x22, 0x3b, 0x0a, 0x0e, 0x42, 0x6c, 0x6f, 0x63,
-	0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x29, 0x0a, 0x06, 0x62, 0x6c,
-	0x6f, 0x63, 0x6b, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x62, 0x6c, 0x6f,
-	0x63, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x06, 0x62,
-	0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x42, 0x10, 0x5a, 0x0e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61,
-	0x6c, 0x2f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x05, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x22, 0x33, 0x0a,
+	0x0d, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x66, 0x72,
+	0x6f, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02,
+	0x74, 0x6f, 0x22, 0x3b, 0x0a, 0x0e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x29, 0x0a, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x63, 0x68, 0x61, 0x69,
+	0x6e, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x42,
+	0x10, 0x5a, 0x0e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x74, 0x79, 0x70, 0x65,
+	0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -452,27 +1150,53 @@ func file_blocks_proto_rawDescGZIP() []byte {
 	return file_blocks_proto_rawDescData
 }
 
-var file_blocks_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_blocks_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
 var file_blocks_proto_goTypes = []interface{}{
 	(*Block)(nil),                       // 0: blockchain.Block
-	(*BlockValidationRequest)(nil),      // 1: blockchain.BlockValidationRequest
-	(*BlockValidationResponse)(nil),     // 2: blockchain.BlockValidationResponse
-	(*BlockRequest)(nil),                // 3: blockchain.BlockRequest
-	(*BlockResponse)(nil),               // 4: blockchain.BlockResponse
-	(*BlocksRequest)(nil),               // 5: blockchain.BlocksRequest
-	(*BlocksResponse)(nil),              // 6: blockchain.BlocksResponse
-	(*DeviceAuthenticationRequest)(nil), // 7: blockchain.DeviceAuthenticationRequest
+	(*BlockVote)(nil),                   // 1: blockchain.BlockVote
+	(*BlockHeader)(nil),                 // 2: blockchain.BlockHeader
+	(*MerkleStep)(nil),                  // 3: blockchain.MerkleStep
+	(*InclusionProofRequest)(nil),       // 4: blockchain.InclusionProofRequest
+	(*InclusionProofResponse)(nil),      // 5: blockchain.InclusionProofResponse
+	(*BlockValidationRequest)(nil),      // 6: blockchain.BlockValidationRequest
+	(*BlockValidationResponse)(nil),     // 7: blockchain.BlockValidationResponse
+	(*BlockCommitRequest)(nil),          // 8: blockchain.BlockCommitRequest
+	(*BlockCommitResponse)(nil),         // 9: blockchain.BlockCommitResponse
+	(*FindBlockRequest)(nil),            // 10: blockchain.FindBlockRequest
+	(*FindBlockResponse)(nil),           // 11: blockchain.FindBlockResponse
+	(*BlockRequest)(nil),                // 12: blockchain.BlockRequest
+	(*BlockResponse)(nil),               // 13: blockchain.BlockResponse
+	(*BlocksRequest)(nil),               // 14: blockchain.BlocksRequest
+	(*BlocksResponse)(nil),              // 15: blockchain.BlocksResponse
+	(*DeviceAuthenticationRequest)(nil), // 16: blockchain.DeviceAuthenticationRequest
+	(*DeviceRevocationRequest)(nil),     // 17: blockchain.DeviceRevocationRequest
+	(*DeviceRenewalRequest)(nil),        // 18: blockchain.DeviceRenewalRequest
+	(*DeviceKeyRotationRequest)(nil),    // 19: blockchain.DeviceKeyRotationRequest
+	(*VerificationHop)(nil),             // 20: blockchain.VerificationHop
 }
 var file_blocks_proto_depIdxs = []int32{
-	7, // 0: blockchain.Block.dar:type_name -> blockchain.DeviceAuthenticationRequest
-	0, // 1: blockchain.BlockValidationRequest.block:type_name -> blockchain.Block
-	0, // 2: blockchain.BlockResponse.block:type_name -> blockchain.Block
-	0, // 3: blockchain.BlocksResponse.blocks:type_name -> blockchain.Block
-	4, // [4:4] is the sub-list for method output_type
-	4, // [4:4] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	16, // 0: blockchain.Block.dars:type_name -> blockchain.DeviceAuthenticationRequest
+	17, // 1: blockchain.Block.revocations:type_name -> blockchain.DeviceRevocationRequest
+	18, // 2: blockchain.Block.renewals:type_name -> blockchain.DeviceRenewalRequest
+	1,  // 3: blockchain.Block.votes:type_name -> blockchain.BlockVote
+	19, // 4: blockchain.Block.rotations:type_name -> blockchain.DeviceKeyRotationRequest
+	2,  // 5: blockchain.InclusionProofResponse.header:type_name -> blockchain.BlockHeader
+	16, // 6: blockchain.InclusionProofResponse.dar:type_name -> blockchain.DeviceAuthenticationRequest
+	3,  // 7: blockchain.InclusionProofResponse.path:type_name -> blockchain.MerkleStep
+	18, // 8: blockchain.InclusionProofResponse.renewal:type_name -> blockchain.DeviceRenewalRequest
+	19, // 9: blockchain.InclusionProofResponse.rotation:type_name -> blockchain.DeviceKeyRotationRequest
+	0,  // 10: blockchain.BlockValidationRequest.block:type_name -> blockchain.Block
+	1,  // 11: blockchain.BlockValidationResponse.vote:type_name -> blockchain.BlockVote
+	0,  // 12: blockchain.BlockCommitRequest.block:type_name -> blockchain.Block
+	0,  // 13: blockchain.FindBlockResponse.block:type_name -> blockchain.Block
+	20, // 14: blockchain.FindBlockResponse.hops:type_name -> blockchain.VerificationHop
+	0,  // 15: blockchain.BlockResponse.block:type_name -> blockchain.Block
+	0,  // 16: blockchain.BlocksResponse.blocks:type_name -> blockchain.Block
+	17, // [17:17] is the sub-list for method output_type
+	17, // [17:17] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
 }
 
 func init() { file_blocks_proto_init() }
@@ -495,7 +1219,7 @@ func file_blocks_proto_init() {
 			}
 		}
 		file_blocks_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BlockValidationRequest); i {
+			switch v := v.(*BlockVote); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -507,7 +1231,7 @@ func file_blocks_proto_init() {
 			}
 		}
 		file_blocks_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BlockValidationResponse); i {
+			switch v := v.(*BlockHeader); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -519,7 +1243,7 @@ func file_blocks_proto_init() {
 			}
 		}
 		file_blocks_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BlockRequest); i {
+			switch v := v.(*MerkleStep); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -531,7 +1255,7 @@ func file_blocks_proto_init() {
 			}
 		}
 		file_blocks_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BlockResponse); i {
+			switch v := v.(*InclusionProofRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -543,7 +1267,7 @@ func file_blocks_proto_init() {
 			}
 		}
 		file_blocks_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BlocksRequest); i {
+			switch v := v.(*InclusionProofResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -555,6 +1279,114 @@ func file_blocks_proto_init() {
 			}
 		}
 		file_blocks_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlockValidationRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_blocks_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlockValidationResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_blocks_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlockCommitRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_blocks_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlockCommitResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_blocks_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FindBlockRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_blocks_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FindBlockResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_blocks_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlockRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_blocks_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlockResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_blocks_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlocksRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_blocks_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*BlocksResponse); i {
 			case 0:
 				return &v.state
@@ -573,7 +1405,7 @@ func file_blocks_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_blocks_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   7,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -4,6 +4,10 @@
 
 package types
 
+import (
+	"fmt"
+)
+
 // InfinityTTL is the value of the infinite TTL.
 const InfinityTTL = 0
 
@@ -22,10 +26,50 @@ const (
 	BucketClusterNodes = "cluster-nodes"
 	// BucketChildrenNodes is the name of the bucket that will store children nodes.
 	BucketChildrenNodes = "children-nodes"
+	// BucketTickets is the name of the bucket that will store statuses of device authentication requests.
+	BucketTickets = "tickets"
+	// BucketForks is the name of the bucket that will store blocks of competing branches.
+	BucketForks = "forks"
+	// BucketKeys is the name of the bucket that will store public keys by device ids.
+	BucketKeys = "keys"
+	// BucketRotations is the name of the bucket that will store new device ids by rotated ones.
+	BucketRotations = "rotations"
+	// BucketNonces is the name of the bucket that will store device ids by nonces issued to registering nodes.
+	BucketNonces = "nonces"
+	// BucketJoinTokens is the name of the bucket that will store hashes of join tokens approved by the operator.
+	BucketJoinTokens = "join-tokens"
+	// BucketMailbox is the name of the bucket that will store messages kept for offline devices.
+	BucketMailbox = "mailbox"
+	// BucketChallenges is the name of the bucket that will store device ids by challenges issued for mailbox access.
+	BucketChallenges = "challenges"
+	// BucketElection is the name of the bucket that will store the state of the cluster head elections.
+	BucketElection = "election"
+)
+
+const (
+	// EnvelopeVersionDirect is the version of content encrypted with the receiver key directly,
+	// its size is limited by the key size.
+	EnvelopeVersionDirect uint32 = 0
+	// EnvelopeVersionHybrid is the version of content encrypted with a random content key
+	// which is wrapped with the receiver key.
+	EnvelopeVersionHybrid uint32 = 1
 )
 
 var (
-	KeyCipher      = []byte("cipher")
-	KeyClusterHead = []byte("cluster-head")
-	KeyLastBlock   = []byte("last-block")
+	KeyCipher        = []byte("cipher")
+	KeyPendingCipher = []byte("pending-cipher")
+	KeyClusterHead   = []byte("cluster-head")
+	KeyLastBlock     = []byte("last-block")
+	KeyGenesisHash   = []byte("genesis-hash")
+	KeyDeviceIDs     = []byte("device-ids")
+
+	KeyTerm                = []byte("term")
+	KeyLevel               = []byte("level")
+	KeyUpperClusterHead    = []byte("upper-cluster-head")
+	KeyChildrenGenesisHash = []byte("children-genesis-hash")
 )
+
+// BucketAuthenticationTableLevel returns the name of the bucket that will store authentication table of the level.
+func BucketAuthenticationTableLevel(level uint32) string {
+	return fmt.Sprintf("%s %d", BucketAuthenticationTable, level)
+}
@@ -4,10 +4,11 @@
 
 package types
 
-func NewMessage(senderID, receiverID, data []byte) *Message {
+func NewMessage(senderID, receiverID, data []byte, envelopeVersion uint32) *Message {
 	return &Message{
-		SenderId:   senderID,
-		ReceiverId: receiverID,
-		Data:       data,
+		SenderId:        senderID,
+		ReceiverId:      receiverID,
+		Data:            data,
+		EnvelopeVersion: envelopeVersion,
 	}
 }
@@ -25,13 +25,30 @@ const (
 	Node_GetStatus_FullMethodName              = "/blockchain.Node/GetStatus"
 	Node_GetBlock_FullMethodName               = "/blockchain.Node/GetBlock"
 	Node_GetBlocks_FullMethodName              = "/blockchain.Node/GetBlocks"
+	Node_StreamBlocks_FullMethodName           = "/blockchain.Node/StreamBlocks"
 	Node_GetPeers_FullMethodName               = "/blockchain.Node/GetPeers"
+	Node_GetTopology_FullMethodName            = "/blockchain.Node/GetTopology"
 	Node_GetAuthenticationTable_FullMethodName = "/blockchain.Node/GetAuthenticationTable"
+	Node_GetInclusionProof_FullMethodName      = "/blockchain.Node/GetInclusionProof"
+	Node_GetPublicKey_FullMethodName           = "/blockchain.Node/GetPublicKey"
+	Node_FindBlock_FullMethodName              = "/blockchain.Node/FindBlock"
 	Node_SendMessage_FullMethodName            = "/blockchain.Node/SendMessage"
 	Node_SendDAR_FullMethodName                = "/blockchain.Node/SendDAR"
+	Node_GetDARStatus_FullMethodName           = "/blockchain.Node/GetDARStatus"
+	Node_RevokeDevice_FullMethodName           = "/blockchain.Node/RevokeDevice"
+	Node_RenewDAR_FullMethodName               = "/blockchain.Node/RenewDAR"
+	Node_RotateKey_FullMethodName              = "/blockchain.Node/RotateKey"
 	Node_SendBlock_FullMethodName              = "/blockchain.Node/SendBlock"
+	Node_CommitBlock_FullMethodName            = "/blockchain.Node/CommitBlock"
 	Node_VerifyDevice_FullMethodName           = "/blockchain.Node/VerifyDevice"
+	Node_GetRegistrationNonce_FullMethodName   = "/blockchain.Node/GetRegistrationNonce"
 	Node_RegisterNode_FullMethodName           = "/blockchain.Node/RegisterNode"
+	Node_DeregisterNode_FullMethodName         = "/blockchain.Node/DeregisterNode"
+	Node_RotatePeerKey_FullMethodName          = "/blockchain.Node/RotatePeerKey"
+	Node_AnnounceClusterHead_FullMethodName    = "/blockchain.Node/AnnounceClusterHead"
+	Node_GetMailboxChallenge_FullMethodName    = "/blockchain.Node/GetMailboxChallenge"
+	Node_FetchMessages_FullMethodName          = "/blockchain.Node/FetchMessages"
+	Node_AckMessages_FullMethodName            = "/blockchain.Node/AckMessages"
 )
 
 // NodeClient is the client API for Node service.
@@ -41,13 +58,30 @@ type NodeClient interface {
 	GetStatus(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusResponse, error)
 	GetBlock(ctx context.Context, in *BlockRequest, opts ...grpc.CallOption) (*BlockResponse, error)
 	GetBlocks(ctx context.Context, in *BlocksRequest, opts ...grpc.CallOption) (*BlocksResponse, error)
+	StreamBlocks(ctx context.Context, in *BlocksRequest, opts ...grpc.CallOption) (Node_StreamBlocksClient, error)
 	GetPeers(ctx context.Context, in *PeersRequest, opts ...grpc.CallOption) (*PeersResponse, error)
+	GetTopology(ctx context.Context, in *TopologyRequest, opts ...grpc.CallOption) (*TopologyResponse, error)
 	GetAuthenticationTable(ctx context.Context, in *AuthenticationTableRequest, opts ...grpc.CallOption) (*AuthenticationTableResponse, error)
+	GetInclusionProof(ctx context.Context, in *InclusionProofRequest, opts ...grpc.CallOption) (*InclusionProofResponse, error)
+	GetPublicKey(ctx context.Context, in *PublicKeyRequest, opts ...grpc.CallOption) (*PublicKeyResponse, error)
+	FindBlock(ctx context.Context, in *FindBlockRequest, opts ...grpc.CallOption) (*FindBlockResponse, error)
 	SendMessage(ctx context.Context, in *Message, opts ...grpc.CallOption) (*Message, error)
 	SendDAR(ctx context.Context, in *DeviceAuthenticationRequest, opts ...grpc.CallOption) (*DeviceAuthenticationResponse, error)
+	GetDARStatus(ctx context.Context, in *DARStatusRequest, opts ...grpc.CallOption) (*DeviceAuthenticationResponse, error)
+	RevokeDevice(ctx context.Context, in *DeviceRevocationRequest, opts ...grpc.CallOption) (*DeviceRevocationResponse, error)
+	RenewDAR(ctx context.Context, in *DeviceRenewalRequest, opts ...grpc.CallOption) (*DeviceAuthenticationResponse, error)
+	RotateKey(ctx context.Context, in *DeviceKeyRotationRequest, opts ...grpc.CallOption) (*DeviceAuthenticationResponse, error)
 	SendBlock(ctx context.Context, in *BlockValidationRequest, opts ...grpc.CallOption) (*BlockValidationResponse, error)
+	CommitBlock(ctx context.Context, in *BlockCommitRequest, opts ...grpc.CallOption) (*BlockCommitResponse, error)
 	VerifyDevice(ctx context.Context, in *VerifyDeviceRequest, opts ...grpc.CallOption) (*VerifyDeviceResponse, error)
+	GetRegistrationNonce(ctx context.Context, in *RegistrationNonceRequest, opts ...grpc.CallOption) (*RegistrationNonceResponse, error)
 	RegisterNode(ctx context.Context, in *NodeRegistrationRequest, opts ...grpc.CallOption) (*NodeRegistrationResponse, error)
+	DeregisterNode(ctx context.Context, in *NodeDeregistrationRequest, opts ...grpc.CallOption) (*NodeDeregistrationResponse, error)
+	RotatePeerKey(ctx context.Context, in *DeviceKeyRotationRequest, opts ...grpc.CallOption) (*PeerKeyRotationResponse, error)
+	AnnounceClusterHead(ctx context.Context, in *ClusterHeadAnnouncement, opts ...grpc.CallOption) (*ClusterHeadAnnouncementResponse, error)
+	GetMailboxChallenge(ctx context.Context, in *MailboxChallengeRequest, opts ...grpc.CallOption) (*MailboxChallengeResponse, error)
+	FetchMessages(ctx context.Context, in *FetchMessagesRequest, opts ...grpc.CallOption) (*FetchMessagesResponse, error)
+	AckMessages(ctx context.Context, in *AckMessagesRequest, opts ...grpc.CallOption) (*AckMessagesResponse, error)
 }
 
 type nodeClient struct {
@@ -85,6 +119,38 @@ func (c *nodeClient) GetBlocks(ctx context.Context, in *BlocksRequest, opts ...g
 	return out, nil
 }
 
+func (c *nodeClient) StreamBlocks(ctx context.Context, in *BlocksRequest, opts ...grpc.CallOption) (Node_StreamBlocksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Node_ServiceDesc.Streams[0], Node_StreamBlocks_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &nodeStreamBlocksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Node_StreamBlocksClient interface {
+	Recv() (*Block, error)
+	grpc.ClientStream
+}
+
+type nodeStreamBlocksClient struct {
+	grpc.ClientStream
+}
+
+func (x *nodeStreamBlocksClient) Recv() (*Block, error) {
+	m := new(Block)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *nodeClient) GetPeers(ctx context.Context, in *PeersRequest, opts ...grpc.CallOption) (*PeersResponse, error) {
 	out := new(PeersResponse)
 	err := c.cc.Invoke(ctx, Node_GetPeers_FullMethodName, in, out, opts...)
@@ -94,6 +160,15 @@ func (c *nodeClient) GetPeers(ctx context.Context, in *PeersRequest, opts ...grp
 	return out, nil
 }
 
+func (c *nodeClient) GetTopology(ctx context.Context, in *TopologyRequest, opts ...grpc.CallOption) (*TopologyResponse, error) {
+	out := new(TopologyResponse)
+	err := c.cc.Invoke(ctx, Node_GetTopology_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *nodeClient) GetAuthenticationTable(ctx context.Context, in *AuthenticationTableRequest, opts ...grpc.CallOption) (*AuthenticationTableResponse, error) {
 	out := new(AuthenticationTableResponse)
 	err := c.cc.Invoke(ctx, Node_GetAuthenticationTable_FullMethodName, in, out, opts...)
@@ -103,6 +178,33 @@ func (c *nodeClient) GetAuthenticationTable(ctx context.Context, in *Authenticat
 	return out, nil
 }
 
+func (c *nodeClient) GetInclusionProof(ctx context.Context, in *InclusionProofRequest, opts ...grpc.CallOption) (*InclusionProofResponse, error) {
+	out := new(InclusionProofResponse)
+	err := c.cc.Invoke(ctx, Node_GetInclusionProof_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeClient) GetPublicKey(ctx context.Context, in *PublicKeyRequest, opts ...grpc.CallOption) (*PublicKeyResponse, error) {
+	out := new(PublicKeyResponse)
+	err := c.cc.Invoke(ctx, Node_GetPublicKey_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeClient) FindBlock(ctx context.Context, in *FindBlockRequest, opts ...grpc.CallOption) (*FindBlockResponse, error) {
+	out := new(FindBlockResponse)
+	err := c.cc.Invoke(ctx, Node_FindBlock_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *nodeClient) SendMessage(ctx context.Context, in *Message, opts ...grpc.CallOption) (*Message, error) {
 	out := new(Message)
 	err := c.cc.Invoke(ctx, Node_SendMessage_FullMethodName, in, out, opts...)
@@ -121,6 +223,42 @@ func (c *nodeClient) SendDAR(ctx context.Context, in *DeviceAuthenticationReques
 	return out, nil
 }
 
+func (c *nodeClient) GetDARStatus(ctx context.Context, in *DARStatusRequest, opts ...grpc.CallOption) (*DeviceAuthenticationResponse, error) {
+	out := new(DeviceAuthenticationResponse)
+	err := c.cc.Invoke(ctx, Node_GetDARStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeClient) RevokeDevice(ctx context.Context, in *DeviceRevocationRequest, opts ...grpc.CallOption) (*DeviceRevocationResponse, error) {
+	out := new(DeviceRevocationResponse)
+	err := c.cc.Invoke(ctx, Node_RevokeDevice_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeClient) RenewDAR(ctx context.Context, in *DeviceRenewalRequest, opts ...grpc.CallOption) (*DeviceAuthenticationResponse, error) {
+	out := new(DeviceAuthenticationResponse)
+	err := c.cc.Invoke(ctx, Node_RenewDAR_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeClient) RotateKey(ctx context.Context, in *DeviceKeyRotationRequest, opts ...grpc.CallOption) (*DeviceAuthenticationResponse, error) {
+	out := new(DeviceAuthenticationResponse)
+	err := c.cc.Invoke(ctx, Node_RotateKey_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *nodeClient) SendBlock(ctx context.Context, in *BlockValidationRequest, opts ...grpc.CallOption) (*BlockValidationResponse, error) {
 	out := new(BlockValidationResponse)
 	err := c.cc.Invoke(ctx, Node_SendBlock_FullMethodName, in, out, opts...)
@@ -130,6 +268,15 @@ func (c *nodeClient) SendBlock(ctx context.Context, in *BlockValidationRequest,
 	return out, nil
 }
 
+func (c *nodeClient) CommitBlock(ctx context.Context, in *BlockCommitRequest, opts ...grpc.CallOption) (*BlockCommitResponse, error) {
+	out := new(BlockCommitResponse)
+	err := c.cc.Invoke(ctx, Node_CommitBlock_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *nodeClient) VerifyDevice(ctx context.Context, in *VerifyDeviceRequest, opts ...grpc.CallOption) (*VerifyDeviceResponse, error) {
 	out := new(VerifyDeviceResponse)
 	err := c.cc.Invoke(ctx, Node_VerifyDevice_FullMethodName, in, out, opts...)
@@ -139,6 +286,15 @@ func (c *nodeClient) VerifyDevice(ctx context.Context, in *VerifyDeviceRequest,
 	return out, nil
 }
 
+func (c *nodeClient) GetRegistrationNonce(ctx context.Context, in *RegistrationNonceRequest, opts ...grpc.CallOption) (*RegistrationNonceResponse, error) {
+	out := new(RegistrationNonceResponse)
+	err := c.cc.Invoke(ctx, Node_GetRegistrationNonce_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *nodeClient) RegisterNode(ctx context.Context, in *NodeRegistrationRequest, opts ...grpc.CallOption) (*NodeRegistrationResponse, error) {
 	out := new(NodeRegistrationResponse)
 	err := c.cc.Invoke(ctx, Node_RegisterNode_FullMethodName, in, out, opts...)
@@ -148,6 +304,60 @@ func (c *nodeClient) RegisterNode(ctx context.Context, in *NodeRegistrationReque
 	return out, nil
 }
 
+func (c *nodeClient) DeregisterNode(ctx context.Context, in *NodeDeregistrationRequest, opts ...grpc.CallOption) (*NodeDeregistrationResponse, error) {
+	out := new(NodeDeregistrationResponse)
+	err := c.cc.Invoke(ctx, Node_DeregisterNode_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeClient) RotatePeerKey(ctx context.Context, in *DeviceKeyRotationRequest, opts ...grpc.CallOption) (*PeerKeyRotationResponse, error) {
+	out := new(PeerKeyRotationResponse)
+	err := c.cc.Invoke(ctx, Node_RotatePeerKey_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeClient) AnnounceClusterHead(ctx context.Context, in *ClusterHeadAnnouncement, opts ...grpc.CallOption) (*ClusterHeadAnnouncementResponse, error) {
+	out := new(ClusterHeadAnnouncementResponse)
+	err := c.cc.Invoke(ctx, Node_AnnounceClusterHead_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeClient) GetMailboxChallenge(ctx context.Context, in *MailboxChallengeRequest, opts ...grpc.CallOption) (*MailboxChallengeResponse, error) {
+	out := new(MailboxChallengeResponse)
+	err := c.cc.Invoke(ctx, Node_GetMailboxChallenge_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeClient) FetchMessages(ctx context.Context, in *FetchMessagesRequest, opts ...grpc.CallOption) (*FetchMessagesResponse, error) {
+	out := new(FetchMessagesResponse)
+	err := c.cc.Invoke(ctx, Node_FetchMessages_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeClient) AckMessages(ctx context.Context, in *AckMessagesRequest, opts ...grpc.CallOption) (*AckMessagesResponse, error) {
+	out := new(AckMessagesResponse)
+	err := c.cc.Invoke(ctx, Node_AckMessages_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // NodeServer is the server API for Node service.
 // All implementations must embed UnimplementedNodeServer
 // for forward compatibility
@@ -155,13 +365,30 @@ type NodeServer interface {
 	GetStatus(context.Context, *StatusRequest) (*StatusResponse, error)
 	GetBlock(context.Context, *BlockRequest) (*BlockResponse, error)
 	GetBlocks(context.Context, *BlocksRequest) (*BlocksResponse, error)
+	StreamBlocks(*BlocksRequest, Node_StreamBlocksServer) error
 	GetPeers(context.Context, *PeersRequest) (*PeersResponse, error)
+	GetTopology(context.Context, *TopologyRequest) (*TopologyResponse, error)
 	GetAuthenticationTable(context.Context, *AuthenticationTableRequest) (*AuthenticationTableResponse, error)
+	GetInclusionProof(context.Context, *InclusionProofRequest) (*InclusionProofResponse, error)
+	GetPublicKey(context.Context, *PublicKeyRequest) (*PublicKeyResponse, error)
+	FindBlock(context.Context, *FindBlockRequest) (*FindBlockResponse, error)
 	SendMessage(context.Context, *Message) (*Message, error)
 	SendDAR(context.Context, *DeviceAuthenticationRequest) (*DeviceAuthenticationResponse, error)
+	GetDARStatus(context.Context, *DARStatusRequest) (*DeviceAuthenticationResponse, error)
+	RevokeDevice(context.Context, *DeviceRevocationRequest) (*DeviceRevocationResponse, error)
+	RenewDAR(context.Context, *DeviceRenewalRequest) (*DeviceAuthenticationResponse, error)
+	RotateKey(context.Context, *DeviceKeyRotationRequest) (*DeviceAuthenticationResponse, error)
 	SendBlock(context.Context, *BlockValidationRequest) (*BlockValidationResponse, error)
+	CommitBlock(context.Context, *BlockCommitRequest) (*BlockCommitResponse, error)
 	VerifyDevice(context.Context, *VerifyDeviceRequest) (*VerifyDeviceResponse, error)
+	GetRegistrationNonce(context.Context, *RegistrationNonceRequest) (*RegistrationNonceResponse, error)
 	RegisterNode(context.Context, *NodeRegistrationRequest) (*NodeRegistrationResponse, error)
+	DeregisterNode(context.Context, *NodeDeregistrationRequest) (*NodeDeregistrationResponse, error)
+	RotatePeerKey(context.Context, *DeviceKeyRotationRequest) (*PeerKeyRotationResponse, error)
+	AnnounceClusterHead(context.Context, *ClusterHeadAnnouncement) (*ClusterHeadAnnouncementResponse, error)
+	GetMailboxChallenge(context.Context, *MailboxChallengeRequest) (*MailboxChallengeResponse, error)
+	FetchMessages(context.Context, *FetchMessagesRequest) (*FetchMessagesResponse, error)
+	AckMessages(context.Context, *AckMessagesRequest) (*AckMessagesResponse, error)
 	mustEmbedUnimplementedNodeServer()
 }
 
@@ -178,27 +405,78 @@ func (UnimplementedNodeServer) GetBlock(context.Context, *BlockRequest) (*BlockR
 func (UnimplementedNodeServer) GetBlocks(context.Context, *BlocksRequest) (*BlocksResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetBlocks not implemented")
 }
+func (UnimplementedNodeServer) StreamBlocks(*BlocksRequest, Node_StreamBlocksServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamBlocks not implemented")
+}
 func (UnimplementedNodeServer) GetPeers(context.Context, *PeersRequest) (*PeersResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetPeers not implemented")
 }
+func (UnimplementedNodeServer) GetTopology(context.Context, *TopologyRequest) (*TopologyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTopology not implemented")
+}
 func (UnimplementedNodeServer) GetAuthenticationTable(context.Context, *AuthenticationTableRequest) (*AuthenticationTableResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAuthenticationTable not implemented")
 }
+func (UnimplementedNodeServer) GetInclusionProof(context.Context, *InclusionProofRequest) (*InclusionProofResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInclusionProof not implemented")
+}
+func (UnimplementedNodeServer) GetPublicKey(context.Context, *PublicKeyRequest) (*PublicKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPublicKey not implemented")
+}
+func (UnimplementedNodeServer) FindBlock(context.Context, *FindBlockRequest) (*FindBlockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindBlock not implemented")
+}
 func (UnimplementedNodeServer) SendMessage(context.Context, *Message) (*Message, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SendMessage not implemented")
 }
 func (UnimplementedNodeServer) SendDAR(context.Context, *DeviceAuthenticationRequest) (*DeviceAuthenticationResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SendDAR not implemented")
 }
+func (UnimplementedNodeServer) GetDARStatus(context.Context, *DARStatusRequest) (*DeviceAuthenticationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDARStatus not implemented")
+}
+func (UnimplementedNodeServer) RevokeDevice(context.Context, *DeviceRevocationRequest) (*DeviceRevocationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeDevice not implemented")
+}
+func (UnimplementedNodeServer) RenewDAR(context.Context, *DeviceRenewalRequest) (*DeviceAuthenticationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenewDAR not implemented")
+}
+func (UnimplementedNodeServer) RotateKey(context.Context, *DeviceKeyRotationRequest) (*DeviceAuthenticationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateKey not implemented")
+}
 func (UnimplementedNodeServer) SendBlock(context.Context, *BlockValidationRequest) (*BlockValidationResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SendBlock not implemented")
 }
+func (UnimplementedNodeServer) CommitBlock(context.Context, *BlockCommitRequest) (*BlockCommitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CommitBlock not implemented")
+}
 func (UnimplementedNodeServer) VerifyDevice(context.Context, *VerifyDeviceRequest) (*VerifyDeviceResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method VerifyDevice not implemented")
 }
+func (UnimplementedNodeServer) GetRegistrationNonce(context.Context, *RegistrationNonceRequest) (*RegistrationNonceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRegistrationNonce not implemented")
+}
 func (UnimplementedNodeServer) RegisterNode(context.Context, *NodeRegistrationRequest) (*NodeRegistrationResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RegisterNode not implemented")
 }
+func (UnimplementedNodeServer) DeregisterNode(context.Context, *NodeDeregistrationRequest) (*NodeDeregistrationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeregisterNode not implemented")
+}
+func (UnimplementedNodeServer) RotatePeerKey(context.Context, *DeviceKeyRotationRequest) (*PeerKeyRotationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotatePeerKey not implemented")
+}
+func (UnimplementedNodeServer) AnnounceClusterHead(context.Context, *ClusterHeadAnnouncement) (*ClusterHeadAnnouncementResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AnnounceClusterHead not implemented")
+}
+func (UnimplementedNodeServer) GetMailboxChallenge(context.Context, *MailboxChallengeRequest) (*MailboxChallengeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMailboxChallenge not implemented")
+}
+func (UnimplementedNodeServer) FetchMessages(context.Context, *FetchMessagesRequest) (*FetchMessagesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FetchMessages not implemented")
+}
+func (UnimplementedNodeServer) AckMessages(context.Context, *AckMessagesRequest) (*AckMessagesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AckMessages not implemented")
+}
 func (UnimplementedNodeServer) mustEmbedUnimplementedNodeServer() {}
 
 // UnsafeNodeServer may be embedded to opt out of forward compatibility for this service.
@@ -266,6 +544,27 @@ func _Node_GetBlocks_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Node_StreamBlocks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BlocksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NodeServer).StreamBlocks(m, &nodeStreamBlocksServer{stream})
+}
+
+type Node_StreamBlocksServer interface {
+	Send(*Block) error
+	grpc.ServerStream
+}
+
+type nodeStreamBlocksServer struct {
+	grpc.ServerStream
+}
+
+func (x *nodeStreamBlocksServer) Send(m *Block) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Node_GetPeers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(PeersRequest)
 	if err := dec(in); err != nil {
@@ -284,6 +583,24 @@ func _Node_GetPeers_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Node_GetTopology_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TopologyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServer).GetTopology(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Node_GetTopology_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServer).GetTopology(ctx, req.(*TopologyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Node_GetAuthenticationTable_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(AuthenticationTableRequest)
 	if err := dec(in); err != nil {
@@ -302,6 +619,60 @@ func _Node_GetAuthenticationTable_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Node_GetInclusionProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InclusionProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServer).GetInclusionProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Node_GetInclusionProof_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServer).GetInclusionProof(ctx, req.(*InclusionProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Node_GetPublicKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublicKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServer).GetPublicKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Node_GetPublicKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServer).GetPublicKey(ctx, req.(*PublicKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Node_FindBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServer).FindBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Node_FindBlock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServer).FindBlock(ctx, req.(*FindBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Node_SendMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Message)
 	if err := dec(in); err != nil {
@@ -338,6 +709,78 @@ func _Node_SendDAR_Handler(srv interface{}, ctx context.Context, dec func(interf
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Node_GetDARStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DARStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServer).GetDARStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Node_GetDARStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServer).GetDARStatus(ctx, req.(*DARStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Node_RevokeDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeviceRevocationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServer).RevokeDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Node_RevokeDevice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServer).RevokeDevice(ctx, req.(*DeviceRevocationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Node_RenewDAR_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeviceRenewalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServer).RenewDAR(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Node_RenewDAR_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServer).RenewDAR(ctx, req.(*DeviceRenewalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Node_RotateKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeviceKeyRotationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServer).RotateKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Node_RotateKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServer).RotateKey(ctx, req.(*DeviceKeyRotationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Node_SendBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(BlockValidationRequest)
 	if err := dec(in); err != nil {
@@ -356,6 +799,24 @@ func _Node_SendBlock_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Node_CommitBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockCommitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServer).CommitBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Node_CommitBlock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServer).CommitBlock(ctx, req.(*BlockCommitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Node_VerifyDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(VerifyDeviceRequest)
 	if err := dec(in); err != nil {
@@ -374,6 +835,24 @@ func _Node_VerifyDevice_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Node_GetRegistrationNonce_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegistrationNonceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServer).GetRegistrationNonce(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Node_GetRegistrationNonce_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServer).GetRegistrationNonce(ctx, req.(*RegistrationNonceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Node_RegisterNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(NodeRegistrationRequest)
 	if err := dec(in); err != nil {
@@ -392,6 +871,114 @@ func _Node_RegisterNode_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Node_DeregisterNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeDeregistrationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServer).DeregisterNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Node_DeregisterNode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServer).DeregisterNode(ctx, req.(*NodeDeregistrationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Node_RotatePeerKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeviceKeyRotationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServer).RotatePeerKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Node_RotatePeerKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServer).RotatePeerKey(ctx, req.(*DeviceKeyRotationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Node_AnnounceClusterHead_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClusterHeadAnnouncement)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServer).AnnounceClusterHead(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Node_AnnounceClusterHead_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServer).AnnounceClusterHead(ctx, req.(*ClusterHeadAnnouncement))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Node_GetMailboxChallenge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MailboxChallengeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServer).GetMailboxChallenge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Node_GetMailboxChallenge_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServer).GetMailboxChallenge(ctx, req.(*MailboxChallengeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Node_FetchMessages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FetchMessagesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServer).FetchMessages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Node_FetchMessages_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServer).FetchMessages(ctx, req.(*FetchMessagesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Node_AckMessages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AckMessagesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServer).AckMessages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Node_AckMessages_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServer).AckMessages(ctx, req.(*AckMessagesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Node_ServiceDesc is the grpc.ServiceDesc for Node service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -415,10 +1002,26 @@ var Node_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetPeers",
 			Handler:    _Node_GetPeers_Handler,
 		},
+		{
+			MethodName: "GetTopology",
+			Handler:    _Node_GetTopology_Handler,
+		},
 		{
 			MethodName: "GetAuthenticationTable",
 			Handler:    _Node_GetAuthenticationTable_Handler,
 		},
+		{
+			MethodName: "GetInclusionProof",
+			Handler:    _Node_GetInclusionProof_Handler,
+		},
+		{
+			MethodName: "GetPublicKey",
+			Handler:    _Node_GetPublicKey_Handler,
+		},
+		{
+			MethodName: "FindBlock",
+			Handler:    _Node_FindBlock_Handler,
+		},
 		{
 			MethodName: "SendMessage",
 			Handler:    _Node_SendMessage_Handler,
@@ -427,19 +1030,73 @@ var Node_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SendDAR",
 			Handler:    _Node_SendDAR_Handler,
 		},
+		{
+			MethodName: "GetDARStatus",
+			Handler:    _Node_GetDARStatus_Handler,
+		},
+		{
+			MethodName: "RevokeDevice",
+			Handler:    _Node_RevokeDevice_Handler,
+		},
+		{
+			MethodName: "RenewDAR",
+			Handler:    _Node_RenewDAR_Handler,
+		},
+		{
+			MethodName: "RotateKey",
+			Handler:    _Node_RotateKey_Handler,
+		},
 		{
 			MethodName: "SendBlock",
 			Handler:    _Node_SendBlock_Handler,
 		},
+		{
+			MethodName: "CommitBlock",
+			Handler:    _Node_CommitBlock_Handler,
+		},
 		{
 			MethodName: "VerifyDevice",
 			Handler:    _Node_VerifyDevice_Handler,
 		},
+		{
+			MethodName: "GetRegistrationNonce",
+			Handler:    _Node_GetRegistrationNonce_Handler,
+		},
 		{
 			MethodName: "RegisterNode",
 			Handler:    _Node_RegisterNode_Handler,
 		},
+		{
+			MethodName: "DeregisterNode",
+			Handler:    _Node_DeregisterNode_Handler,
+		},
+		{
+			MethodName: "RotatePeerKey",
+			Handler:    _Node_RotatePeerKey_Handler,
+		},
+		{
+			MethodName: "AnnounceClusterHead",
+			Handler:    _Node_AnnounceClusterHead_Handler,
+		},
+		{
+			MethodName: "GetMailboxChallenge",
+			Handler:    _Node_GetMailboxChallenge_Handler,
+		},
+		{
+			MethodName: "FetchMessages",
+			Handler:    _Node_FetchMessages_Handler,
+		},
+		{
+			MethodName: "AckMessages",
+			Handler:    _Node_AckMessages_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamBlocks",
+			Handler:       _Node_StreamBlocks_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "node.proto",
 }